@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package auth
+
+import (
+	"net/http"
+	"slices"
+)
+
+// AllowNames wraps p so that only principals with one of names are
+// authenticated; anyone else is treated as not authenticated.
+func AllowNames(p Provider, names []string) Provider {
+	return allowNames{p: p, names: slices.Clone(names)}
+}
+
+type allowNames struct {
+	p     Provider
+	names []string
+}
+
+func (a allowNames) Authenticate(r *http.Request) (Principal, bool, error) {
+	pr, ok, err := a.p.Authenticate(r)
+	if err != nil || !ok {
+		return pr, ok, err
+	}
+	if !slices.Contains(a.names, pr.Name) {
+		return Principal{}, false, nil
+	}
+	return pr, true, nil
+}
+
+func (a allowNames) Challenges(err error) []string {
+	if c, ok := a.p.(Challenger); ok {
+		return c.Challenges(err)
+	}
+	return nil
+}
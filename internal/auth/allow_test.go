@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package auth
+
+import (
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AllowNames", func() {
+	basic := NewBasicAuth(map[string]string{"alice": "a", "bob": "b"}, "mocker")
+	allowed := AllowNames(basic, []string{"alice"})
+
+	authenticate := func(user, pass string) (Principal, bool, error) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.SetBasicAuth(user, pass)
+		return allowed.Authenticate(req)
+	}
+
+	It("passes listed principals through", func() {
+		p, ok, err := authenticate("alice", "a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(p.Name).To(Equal("alice"))
+	})
+
+	It("treats other principals as not authenticated", func() {
+		_, ok, err := authenticate("bob", "b")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+})
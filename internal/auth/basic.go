@@ -12,17 +12,29 @@ import (
 )
 
 type BasicAuth struct {
-	users map[string]string
-	Realm string
+	users  map[string]string
+	grants map[string]Grants
+	Realm  string
 }
 
-func NewBasicAuth(users map[string]string, realm string) *BasicAuth {
+type BasicOption func(*BasicAuth)
+
+// WithUserGrants attaches roles and scopes to users by name.
+func WithUserGrants(grants map[string]Grants) BasicOption {
+	return func(a *BasicAuth) { a.grants = grants }
+}
+
+func NewBasicAuth(users map[string]string, realm string, opts ...BasicOption) *BasicAuth {
 	cp := make(map[string]string, len(users))
 	for u, p := range users {
 		cp[u] = p
 	}
 
-	return &BasicAuth{users: cp, Realm: realm}
+	a := &BasicAuth{users: cp, Realm: realm}
+	for _, o := range opts {
+		o(a)
+	}
+	return a
 }
 
 func (a *BasicAuth) Authenticate(r *http.Request) (Principal, bool, error) {
@@ -54,7 +66,12 @@ func (a *BasicAuth) Authenticate(r *http.Request) (Principal, bool, error) {
 	}
 
 	if subtle.ConstantTimeCompare([]byte(want), []byte(pass)) == 1 {
-		return Principal{Name: user}, true, nil
+		g := a.grants[user]
+		return Principal{Name: user, Roles: g.Roles, Scopes: g.Scopes}, true, nil
 	}
 	return Principal{}, false, nil
 }
+
+func (a *BasicAuth) Challenges(error) []string {
+	return []string{`Basic realm="` + a.Realm + `"`}
+}
@@ -79,4 +79,17 @@ var _ = Describe("BasicAuth", func() {
 			true, false, []string(nil),
 		),
 	)
+	It("attaches the user's grants to the principal", func() {
+		a := NewBasicAuth(map[string]string{"alice": "secret"}, "mocker", WithUserGrants(map[string]Grants{
+			"alice": {Roles: []string{"admin"}, Scopes: []string{"read"}},
+		}))
+		req := httptest.NewRequest("GET", "http://example.com/x", nil)
+		req.Header.Set("Authorization", b64("alice", "secret"))
+
+		p, ok, err := a.Authenticate(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(p.Satisfies([]string{"read"}, []string{"admin"})).To(BeTrue())
+		Expect(p.Satisfies([]string{"write"}, nil)).To(BeFalse())
+	})
 })
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+)
+
+// Challenger is implemented by providers that answer failed requests with
+// WWW-Authenticate challenges; err is what Authenticate returned.
+type Challenger interface {
+	Challenges(err error) []string
+}
+
+// AnyOf authenticates with the first of ps that accepts the request. When
+// none does, the errors of all of them are returned.
+func AnyOf(ps ...Provider) Provider {
+	return chain{ps: slices.Clone(ps)}
+}
+
+// AllOf authenticates only if every one of ps accepts the request. The
+// principal takes its name and claims from the first provider that has
+// them and the roles and scopes of all.
+func AllOf(ps ...Provider) Provider {
+	return chain{ps: slices.Clone(ps), all: true}
+}
+
+type chain struct {
+	ps  []Provider
+	all bool
+}
+
+func (c chain) Authenticate(r *http.Request) (Principal, bool, error) {
+	if len(c.ps) == 0 {
+		return Principal{}, false, nil
+	}
+
+	var merged Principal
+	var errs []error
+	for _, p := range c.ps {
+		pr, ok, err := p.Authenticate(r)
+		switch {
+		case ok && err == nil && !c.all:
+			return pr, true, nil
+		case ok && err == nil:
+			merged = merge(merged, pr)
+		case c.all:
+			return Principal{}, false, err
+		default:
+			errs = append(errs, err)
+		}
+	}
+	if c.all {
+		return merged, true, nil
+	}
+	return Principal{}, false, errors.Join(errs...)
+}
+
+// Challenges collects the challenges of all providers.
+func (c chain) Challenges(err error) []string {
+	var out []string
+	for _, p := range c.ps {
+		if ch, ok := p.(Challenger); ok {
+			out = append(out, ch.Challenges(err)...)
+		}
+	}
+	return out
+}
+
+func merge(a, b Principal) Principal {
+	if a.Name == "" {
+		a.Name = b.Name
+	}
+	if a.Claims == nil {
+		a.Claims = b.Claims
+	}
+	for _, r := range b.Roles {
+		if !slices.Contains(a.Roles, r) {
+			a.Roles = append(a.Roles, r)
+		}
+	}
+	for _, s := range b.Scopes {
+		if !slices.Contains(a.Scopes, s) {
+			a.Scopes = append(a.Scopes, s)
+		}
+	}
+	return a
+}
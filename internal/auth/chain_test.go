@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AnyOf and AllOf", func() {
+	secret := []byte("s3cret")
+	token := NewTokenAuth("X-API-Key", "", []string{"k1"}, WithTokenGrants(map[string]Grants{"k1": {Scopes: []string{"read"}}}))
+	basic := NewBasicAuth(map[string]string{"alice": "a"}, "mocker", WithUserGrants(map[string]Grants{"alice": {Roles: []string{"admin"}}}))
+	jwt := NewJWTAuth(&KeySet{secrets: [][]byte{secret}})
+
+	request := func(key, user, pass string) *http.Request {
+		req := httptest.NewRequest("GET", "/", nil)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		if user != "" {
+			req.SetBasicAuth(user, pass)
+		}
+		return req
+	}
+
+	DescribeTable("AnyOf accepts the first provider that authenticates",
+		func(req *http.Request, wantOK bool, wantName string) {
+			p, ok, err := AnyOf(token, basic).Authenticate(req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(Equal(wantOK))
+			Expect(p.Name).To(Equal(wantName))
+		},
+		Entry("api key", request("k1", "", ""), true, "token"),
+		Entry("basic", request("", "alice", "a"), true, "alice"),
+		Entry("wrong key, right password", request("nope", "alice", "a"), true, "alice"),
+		Entry("neither", request("nope", "alice", "x"), false, ""),
+	)
+
+	It("AnyOf reports provider errors only when no provider accepts", func() {
+		req := request("", "", "")
+		req.Header.Set("Authorization", "Bearer not-a-jwt")
+		_, ok, err := AnyOf(token, jwt).Authenticate(req)
+		Expect(ok).To(BeFalse())
+		Expect(err).To(MatchError(ErrInvalidToken))
+
+		req.Header.Set("X-API-Key", "k1")
+		_, ok, err = AnyOf(jwt, token).Authenticate(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+	})
+
+	It("AllOf requires every provider and merges their grants", func() {
+		all := AllOf(basic, token)
+
+		p, ok, err := all.Authenticate(request("k1", "alice", "a"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(p.Name).To(Equal("alice"))
+		Expect(p.Satisfies([]string{"read"}, []string{"admin"})).To(BeTrue())
+
+		for _, req := range []*http.Request{request("k1", "", ""), request("", "alice", "a"), request("k2", "alice", "a")} {
+			_, ok, err := all.Authenticate(req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		}
+	})
+
+	It("aggregates the challenges of its providers", func() {
+		Expect(AnyOf(token, basic, AllowNames(jwt, []string{"alice"})).(Challenger).Challenges(ErrInvalidToken)).To(Equal([]string{
+			`Basic realm="mocker"`,
+			`Bearer realm="mocker", error="invalid_token"`,
+		}))
+		Expect(AllOf(token).(Challenger).Challenges(nil)).To(BeEmpty())
+	})
+
+	It("never authenticates without providers", func() {
+		_, ok, err := AnyOf().Authenticate(request("k1", "", ""))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+})
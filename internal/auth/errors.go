@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package auth
+
+import "errors"
+
+var (
+	ErrInvalidKey   = errors.New("invalid key material")
+	ErrInvalidToken = errors.New("invalid token")
+)
@@ -0,0 +1,349 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256" // register SHA-256 for crypto.Hash
+	_ "crypto/sha512" // register SHA-384 and SHA-512
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// JWTAuth accepts bearer JWTs signed with one of its keys.
+type JWTAuth struct {
+	Header   string
+	Prefix   string
+	Realm    string
+	Issuer   string
+	Audience []string
+	// tolerance for exp and nbf
+	ClockSkew time.Duration
+	// accepted "alg" values, all supported ones when empty
+	Algorithms []string
+	// dotted path of the claim holding the principal's roles
+	RolesClaim string
+
+	keys *KeySet
+	now  func() time.Time
+}
+
+type JWTOption func(*JWTAuth)
+
+func WithIssuer(iss string) JWTOption {
+	return func(a *JWTAuth) { a.Issuer = iss }
+}
+
+// WithAudience requires the token's aud to contain one of auds.
+func WithAudience(auds ...string) JWTOption {
+	return func(a *JWTAuth) { a.Audience = auds }
+}
+
+func WithClockSkew(d time.Duration) JWTOption {
+	return func(a *JWTAuth) { a.ClockSkew = d }
+}
+
+func WithAlgorithms(algs ...string) JWTOption {
+	return func(a *JWTAuth) { a.Algorithms = algs }
+}
+
+// WithRolesClaim reads roles from the claim at path, e.g.
+// "realm_access.roles", instead of "roles".
+func WithRolesClaim(path string) JWTOption {
+	return func(a *JWTAuth) { a.RolesClaim = path }
+}
+
+// WithClock replaces time.Now for exp and nbf checks.
+func WithClock(now func() time.Time) JWTOption {
+	return func(a *JWTAuth) { a.now = now }
+}
+
+// WithTokenHeader reads the token from header after prefix instead of
+// "Authorization: Bearer ".
+func WithTokenHeader(header, prefix string) JWTOption {
+	return func(a *JWTAuth) { a.Header, a.Prefix = header, prefix }
+}
+
+func NewJWTAuth(keys *KeySet, opts ...JWTOption) *JWTAuth {
+	a := &JWTAuth{Header: "Authorization", Prefix: "Bearer ", Realm: "mocker", RolesClaim: "roles", keys: keys, now: time.Now}
+	for _, o := range opts {
+		o(a)
+	}
+	return a
+}
+
+// Trusting returns a copy of a that also accepts tokens signed by key.
+func (a *JWTAuth) Trusting(kid string, key crypto.PublicKey) *JWTAuth {
+	cp := *a
+	ks := &KeySet{}
+	if a.keys != nil {
+		ks.secrets = slices.Clone(a.keys.secrets)
+		ks.public = slices.Clone(a.keys.public)
+	}
+	ks.AddPublicKey(kid, key)
+	cp.keys = ks
+	return &cp
+}
+
+// SupportedAlgorithms lists the JWS algorithms JWTAuth can verify.
+var SupportedAlgorithms = []string{
+	"HS256", "HS384", "HS512",
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+}
+
+// Authenticate returns ok=false without error when no token is sent and an
+// ErrInvalidToken error when a token is sent but rejected.
+func (a *JWTAuth) Authenticate(r *http.Request) (Principal, bool, error) {
+	hv := r.Header.Get(a.Header)
+	if hv == "" {
+		return Principal{}, false, nil
+	}
+	token := hv
+	if a.Prefix != "" {
+		if len(hv) < len(a.Prefix) || !strings.EqualFold(hv[:len(a.Prefix)], a.Prefix) {
+			return Principal{}, false, nil
+		}
+		token = strings.TrimSpace(hv[len(a.Prefix):])
+	}
+
+	claims, err := a.Verify(token)
+	if err != nil {
+		return Principal{}, false, err
+	}
+	sub, _ := claims["sub"].(string)
+	return Principal{
+		Name:   sub,
+		Roles:  claimStrings(claimAt(claims, a.RolesClaim)),
+		Scopes: scopesOf(claims),
+		Claims: claims,
+	}, true, nil
+}
+
+// scopesOf reads the space separated "scope" claim, falling back to the
+// "scp" claim used by some issuers.
+func scopesOf(claims map[string]any) []string {
+	if s, ok := claims["scope"].(string); ok {
+		return strings.Fields(s)
+	}
+	return claimStrings(claims["scp"])
+}
+
+func claimAt(claims map[string]any, path string) any {
+	var v any = claims
+	for _, k := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[k]
+	}
+	return v
+}
+
+// claimStrings accepts a string list or a space separated string.
+func claimStrings(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, x := range v {
+			if s, ok := x.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// Challenges asks for a bearer token and flags rejected ones.
+func (a *JWTAuth) Challenges(err error) []string {
+	c := `Bearer realm="` + a.Realm + `"`
+	if errors.Is(err, ErrInvalidToken) {
+		c += `, error="invalid_token"`
+	}
+	return []string{c}
+}
+
+// Verify checks the token's signature and registered claims and returns
+// its claims.
+func (a *JWTAuth) Verify(token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+	if !slices.Contains(SupportedAlgorithms, header.Alg) ||
+		(len(a.Algorithms) > 0 && !slices.Contains(a.Algorithms, header.Alg)) {
+		return nil, fmt.Errorf("%w: algorithm %q not accepted", ErrInvalidToken, header.Alg)
+	}
+
+	sig, err := decodeB64(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature encoding", ErrInvalidToken)
+	}
+	if !a.keys.verify(header.Alg, header.Kid, []byte(parts[0]+"."+parts[1]), sig) {
+		return nil, fmt.Errorf("%w: signature", ErrInvalidToken)
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+	if err := a.checkClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (a *JWTAuth) checkClaims(claims map[string]any) error {
+	now := a.now()
+	if exp, ok := numericDate(claims, "exp"); ok && !now.Before(exp.Add(a.ClockSkew)) {
+		return fmt.Errorf("%w: expired at %s", ErrInvalidToken, exp.UTC().Format(time.RFC3339))
+	}
+	if nbf, ok := numericDate(claims, "nbf"); ok && now.Add(a.ClockSkew).Before(nbf) {
+		return fmt.Errorf("%w: not valid before %s", ErrInvalidToken, nbf.UTC().Format(time.RFC3339))
+	}
+	if a.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != a.Issuer {
+			return fmt.Errorf("%w: issuer %q not accepted", ErrInvalidToken, iss)
+		}
+	}
+	if len(a.Audience) > 0 {
+		var auds []string
+		switch v := claims["aud"].(type) {
+		case string:
+			auds = []string{v}
+		case []any:
+			for _, x := range v {
+				if s, ok := x.(string); ok {
+					auds = append(auds, s)
+				}
+			}
+		}
+		if !slices.ContainsFunc(auds, func(s string) bool { return slices.Contains(a.Audience, s) }) {
+			return fmt.Errorf("%w: audience not accepted", ErrInvalidToken)
+		}
+	}
+	return nil
+}
+
+func numericDate(claims map[string]any, key string) (time.Time, bool) {
+	switch v := claims[key].(type) {
+	case float64:
+		return time.Unix(0, int64(v*float64(time.Second))), true
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.Unix(0, int64(f*float64(time.Second))), true
+	}
+	return time.Time{}, false
+}
+
+func decodeSegment(seg string, v any) error {
+	raw, err := decodeB64(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func hashFor(alg string) crypto.Hash {
+	switch alg[2:] {
+	case "384":
+		return crypto.SHA384
+	case "512":
+		return crypto.SHA512
+	}
+	return crypto.SHA256
+}
+
+// verify tries the keys matching alg, preferring those with the token's kid.
+func (ks *KeySet) verify(alg, kid string, signed, sig []byte) bool {
+	if ks == nil {
+		return false
+	}
+	hash := hashFor(alg)
+
+	if strings.HasPrefix(alg, "HS") {
+		for _, secret := range ks.secrets {
+			mac := hmac.New(hash.New, secret)
+			mac.Write(signed)
+			if hmac.Equal(mac.Sum(nil), sig) {
+				return true
+			}
+		}
+		return false
+	}
+
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	cands := ks.public
+	if kid != "" && slices.ContainsFunc(cands, func(k publicKey) bool { return k.kid == kid }) {
+		cands = slices.DeleteFunc(slices.Clone(cands), func(k publicKey) bool { return k.kid != kid })
+	}
+	for _, pk := range cands {
+		if pk.alg != "" && pk.alg != alg {
+			continue
+		}
+		switch key := pk.key.(type) {
+		case *rsa.PublicKey:
+			switch alg[:2] {
+			case "RS":
+				if rsa.VerifyPKCS1v15(key, hash, digest, sig) == nil {
+					return true
+				}
+			case "PS":
+				if rsa.VerifyPSS(key, hash, digest, sig, nil) == nil {
+					return true
+				}
+			}
+		case *ecdsa.PublicKey:
+			size := (key.Curve.Params().BitSize + 7) / 8
+			if alg[:2] != "ES" || len(sig) != 2*size || ecAlg(key) != alg {
+				continue
+			}
+			r := new(big.Int).SetBytes(sig[:size])
+			s := new(big.Int).SetBytes(sig[size:])
+			if ecdsa.Verify(key, digest, r, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ecAlg returns the JWS algorithm that belongs to the key's curve.
+func ecAlg(key *ecdsa.PublicKey) string {
+	switch key.Curve.Params().BitSize {
+	case 384:
+		return "ES384"
+	case 521:
+		return "ES512"
+	}
+	return "ES256"
+}
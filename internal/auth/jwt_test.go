@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// sign builds a compact JWS over claims; key is a []byte secret or a
+// private key matching alg.
+func sign(alg, kid string, claims map[string]any, key any) string {
+	header := map[string]any{"alg": alg, "typ": "JWT"}
+	if kid != "" {
+		header["kid"] = kid
+	}
+	enc := func(v any) string {
+		b, err := json.Marshal(v)
+		Expect(err).NotTo(HaveOccurred())
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signed := enc(header) + "." + enc(claims)
+
+	hash := hashFor(alg)
+	var sig []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(hash.New, k)
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		h := hash.New()
+		h.Write([]byte(signed))
+		var err error
+		if alg[:2] == "PS" {
+			sig, err = rsa.SignPSS(rand.Reader, k, hash, h.Sum(nil), nil)
+		} else {
+			sig, err = rsa.SignPKCS1v15(rand.Reader, k, hash, h.Sum(nil))
+		}
+		Expect(err).NotTo(HaveOccurred())
+	case *ecdsa.PrivateKey:
+		h := hash.New()
+		h.Write([]byte(signed))
+		r, s, err := ecdsa.Sign(rand.Reader, k, h.Sum(nil))
+		Expect(err).NotTo(HaveOccurred())
+		size := (k.Curve.Params().BitSize + 7) / 8
+		sig = append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+var (
+	rsaKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _  = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+)
+
+var _ = Describe("JWTAuth", func() {
+	secret := []byte("s3cret")
+	now := time.Unix(1_700_000_000, 0)
+	claims := func(extra map[string]any) map[string]any {
+		c := map[string]any{"sub": "alice", "exp": now.Add(time.Hour).Unix(), "scope": "read"}
+		for k, v := range extra {
+			c[k] = v
+		}
+		return c
+	}
+	newAuth := func(ks *KeySet, opts ...JWTOption) *JWTAuth {
+		a := NewJWTAuth(ks, opts...)
+		a.now = func() time.Time { return now }
+		return a
+	}
+	authenticate := func(a *JWTAuth, header string) (Principal, bool, error) {
+		req := httptest.NewRequest("GET", "/", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		return a.Authenticate(req)
+	}
+
+	It("accepts HS, RS, PS and ES signatures and exposes subject and claims", func() {
+		ks := &KeySet{secrets: [][]byte{secret}}
+		ks.AddPublicKey("rsa", &rsaKey.PublicKey)
+		ks.AddPublicKey("ec", &ecKey.PublicKey)
+		a := newAuth(ks)
+
+		for alg, key := range map[string]any{"HS256": secret, "HS512": secret, "RS256": rsaKey, "PS384": rsaKey, "ES256": ecKey} {
+			p, ok, err := authenticate(a, "Bearer "+sign(alg, "", claims(nil), key))
+			Expect(err).NotTo(HaveOccurred(), alg)
+			Expect(ok).To(BeTrue(), alg)
+			Expect(p.Name).To(Equal("alice"))
+			Expect(p.Claims).To(HaveKeyWithValue("scope", "read"))
+		}
+	})
+
+	It("ignores requests without a bearer token", func() {
+		a := newAuth(&KeySet{secrets: [][]byte{secret}})
+		for _, h := range []string{"", "Basic Zm9vOmJhcg=="} {
+			_, ok, err := authenticate(a, h)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		}
+	})
+
+	DescribeTable("rejects invalid tokens",
+		func(token func() string, opts []JWTOption, wantSub string) {
+			ks := &KeySet{secrets: [][]byte{secret}}
+			ks.AddPublicKey("rsa", &rsaKey.PublicKey)
+			_, ok, err := authenticate(newAuth(ks, opts...), "Bearer "+token())
+			Expect(ok).To(BeFalse())
+			Expect(err).To(MatchError(ErrInvalidToken))
+			Expect(err.Error()).To(ContainSubstring(wantSub))
+		},
+		Entry("malformed", func() string { return "abc" }, nil, "malformed"),
+		Entry("alg none", func() string {
+			return sign("none", "", claims(nil), nil)
+		}, nil, `algorithm "none"`),
+		Entry("algorithm not allowed", func() string {
+			return sign("HS256", "", claims(nil), secret)
+		}, []JWTOption{WithAlgorithms("RS256")}, `algorithm "HS256"`),
+		Entry("wrong secret", func() string {
+			return sign("HS256", "", claims(nil), []byte("other"))
+		}, nil, "signature"),
+		Entry("key id of another key", func() string {
+			other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			return sign("ES256", "rsa", claims(nil), other)
+		}, nil, "signature"),
+		Entry("expired", func() string {
+			return sign("HS256", "", claims(map[string]any{"exp": now.Add(-time.Minute).Unix()}), secret)
+		}, nil, "expired"),
+		Entry("expired beyond skew", func() string {
+			return sign("HS256", "", claims(map[string]any{"exp": now.Add(-2 * time.Minute).Unix()}), secret)
+		}, []JWTOption{WithClockSkew(time.Minute)}, "expired"),
+		Entry("not yet valid", func() string {
+			return sign("HS256", "", claims(map[string]any{"nbf": now.Add(time.Minute).Unix()}), secret)
+		}, nil, "not valid before"),
+		Entry("wrong issuer", func() string {
+			return sign("HS256", "", claims(map[string]any{"iss": "evil"}), secret)
+		}, []JWTOption{WithIssuer("https://id.example.com")}, "issuer"),
+		Entry("wrong audience", func() string {
+			return sign("HS256", "", claims(map[string]any{"aud": []string{"web"}}), secret)
+		}, []JWTOption{WithAudience("api")}, "audience"),
+	)
+
+	It("tolerates exp and nbf within the clock skew", func() {
+		a := newAuth(&KeySet{secrets: [][]byte{secret}}, WithClockSkew(time.Minute))
+		token := sign("HS256", "", claims(map[string]any{
+			"exp": now.Add(-30 * time.Second).Unix(),
+			"nbf": now.Add(30 * time.Second).Unix(),
+		}), secret)
+		_, ok, err := authenticate(a, "Bearer "+token)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+	})
+
+	It("matches issuer and any audience", func() {
+		a := newAuth(&KeySet{secrets: [][]byte{secret}}, WithIssuer("https://id.example.com"), WithAudience("api", "admin"))
+		for _, aud := range []any{"api", []string{"web", "admin"}} {
+			token := sign("HS256", "", claims(map[string]any{"iss": "https://id.example.com", "aud": aud}), secret)
+			_, ok, err := authenticate(a, "Bearer "+token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+		}
+	})
+
+	It("reads the token from a custom header", func() {
+		a := newAuth(&KeySet{secrets: [][]byte{secret}}, WithTokenHeader("X-Token", ""))
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("X-Token", sign("HS256", "", claims(nil), secret))
+		_, ok, err := a.Authenticate(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+	})
+
+	It("checks ES signatures against the curve size", func() {
+		p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		ks := &KeySet{}
+		ks.AddPublicKey("", &p384.PublicKey)
+		a := newAuth(ks)
+
+		_, ok, err := authenticate(a, "Bearer "+sign("ES384", "", claims(nil), p384))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+
+		// a P-256 signature must not verify against the P-384 key
+		_, ok, err = authenticate(a, "Bearer "+sign("ES256", "", claims(nil), ecKey))
+		Expect(ok).To(BeFalse())
+		Expect(err).To(MatchError(ErrInvalidToken))
+	})
+	DescribeTable("reads roles and scopes from claims",
+		func(extra map[string]any, opts []JWTOption, wantRoles, wantScopes []string) {
+			a := newAuth(&KeySet{secrets: [][]byte{secret}}, opts...)
+			p, ok, err := authenticate(a, "Bearer "+sign("HS256", "", claims(extra), secret))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(p.Roles).To(Equal(wantRoles))
+			Expect(p.Scopes).To(Equal(wantScopes))
+		},
+		Entry("space separated scope and role list",
+			map[string]any{"scope": "read write", "roles": []string{"admin"}}, nil,
+			[]string{"admin"}, []string{"read", "write"}),
+		Entry("scp array",
+			map[string]any{"scope": nil, "scp": []string{"read"}}, nil,
+			[]string(nil), []string{"read"}),
+		Entry("nested roles claim",
+			map[string]any{"realm_access": map[string]any{"roles": []string{"ops"}}},
+			[]JWTOption{WithRolesClaim("realm_access.roles")},
+			[]string{"ops"}, []string{"read"}),
+	)
+})
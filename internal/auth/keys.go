@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// KeySet holds the keys JWT signatures are verified with.
+type KeySet struct {
+	secrets [][]byte
+	public  []publicKey
+}
+
+type publicKey struct {
+	kid string
+	alg string
+	key crypto.PublicKey
+}
+
+// LoadKeySet collects an HMAC secret, PEM public keys or certificates, and
+// the keys of a local JWKS file. At least one key is required.
+func LoadKeySet(secret string, pemFiles []string, jwksFile string) (*KeySet, error) {
+	ks := &KeySet{}
+	if secret != "" {
+		ks.secrets = append(ks.secrets, []byte(secret))
+	}
+	for _, f := range pemFiles {
+		if err := ks.addPEMFile(f); err != nil {
+			return nil, err
+		}
+	}
+	if jwksFile != "" {
+		raw, err := os.ReadFile(jwksFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+		}
+		if err := ks.AddJWKS(raw); err != nil {
+			return nil, fmt.Errorf("%s: %w", jwksFile, err)
+		}
+	}
+	if ks.Empty() {
+		return nil, fmt.Errorf("%w: no keys configured", ErrInvalidKey)
+	}
+	return ks, nil
+}
+
+func (ks *KeySet) Empty() bool {
+	return len(ks.secrets) == 0 && len(ks.public) == 0
+}
+
+// AddPublicKey adds an RSA or ECDSA public key under an optional key id.
+func (ks *KeySet) AddPublicKey(kid string, key crypto.PublicKey) {
+	ks.public = append(ks.public, publicKey{kid: kid, key: key})
+}
+
+func (ks *KeySet) addPEMFile(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	n := 0
+	for {
+		var block *pem.Block
+		block, raw = pem.Decode(raw)
+		if block == nil {
+			break
+		}
+		key, err := parsePEMBlock(block)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidKey, path, err)
+		}
+		if key != nil {
+			ks.AddPublicKey("", key)
+			n++
+		}
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: %s: no public key found", ErrInvalidKey, path)
+	}
+	return nil
+}
+
+func parsePEMBlock(b *pem.Block) (crypto.PublicKey, error) {
+	var key any
+	var err error
+	switch b.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(b.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(b.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(b.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		// private keys and other blocks are skipped
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %T", key)
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	// symmetric
+	K string `json:"k,omitempty"`
+}
+
+// AddJWKS adds the signing keys of a JSON Web Key Set document.
+func (ks *KeySet) AddJWKS(raw []byte) error {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("%w: jwks: %v", ErrInvalidKey, err)
+	}
+	if len(doc.Keys) == 0 {
+		return fmt.Errorf("%w: jwks has no keys", ErrInvalidKey)
+	}
+	for i, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if err := ks.addJWK(k); err != nil {
+			return fmt.Errorf("%w: jwks keys[%d]: %v", ErrInvalidKey, i, err)
+		}
+	}
+	return nil
+}
+
+func (ks *KeySet) addJWK(k jwk) error {
+	switch k.Kty {
+	case "oct":
+		secret, err := decodeB64(k.K)
+		if err != nil || len(secret) == 0 {
+			return fmt.Errorf("invalid k")
+		}
+		ks.secrets = append(ks.secrets, secret)
+	case "RSA":
+		n, err1 := decodeB64(k.N)
+		e, err2 := decodeB64(k.E)
+		if err1 != nil || err2 != nil || len(n) == 0 || len(e) == 0 {
+			return fmt.Errorf("invalid n or e")
+		}
+		key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		ks.public = append(ks.public, publicKey{kid: k.Kid, alg: k.Alg, key: key})
+	case "EC":
+		curve := curveByName(k.Crv)
+		if curve == nil {
+			return fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err1 := decodeB64(k.X)
+		y, err2 := decodeB64(k.Y)
+		if err1 != nil || err2 != nil {
+			return fmt.Errorf("invalid x or y")
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return fmt.Errorf("point not on curve %s", k.Crv)
+		}
+		ks.public = append(ks.public, publicKey{kid: k.Kid, alg: k.Alg, key: key})
+	default:
+		return fmt.Errorf("unsupported kty %q", k.Kty)
+	}
+	return nil
+}
+
+func curveByName(name string) elliptic.Curve {
+	switch name {
+	case "P-256":
+		return elliptic.P256()
+	case "P-384":
+		return elliptic.P384()
+	case "P-521":
+		return elliptic.P521()
+	}
+	return nil
+}
+
+func decodeB64(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package auth
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func writeFile(dir, name string, data []byte) string {
+	p := filepath.Join(dir, name)
+	Expect(os.WriteFile(p, data, 0o600)).To(Succeed())
+	return p
+}
+
+func pemPublicKey(key any) []byte {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	Expect(err).NotTo(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func b64url(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+var _ = Describe("LoadKeySet", func() {
+	var dir string
+	BeforeEach(func() { dir = GinkgoT().TempDir() })
+
+	verifies := func(ks *KeySet, alg, kid string, key any) bool {
+		_, err := NewJWTAuth(ks).Verify(sign(alg, kid, map[string]any{"sub": "x"}, key))
+		return err == nil
+	}
+
+	It("loads PEM public keys and certificates", func() {
+		tpl := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "mocker"}, NotAfter: time.Now().Add(time.Hour)}
+		der, err := x509.CreateCertificate(nil, tpl, tpl, &ecKey.PublicKey, ecKey)
+		Expect(err).NotTo(HaveOccurred())
+
+		keys := writeFile(dir, "keys.pem", pemPublicKey(&rsaKey.PublicKey))
+		cert := writeFile(dir, "cert.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+		ks, err := LoadKeySet("", []string{keys, cert}, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verifies(ks, "RS256", "", rsaKey)).To(BeTrue())
+		Expect(verifies(ks, "ES256", "", ecKey)).To(BeTrue())
+	})
+
+	It("loads RSA, EC and symmetric keys from a JWKS file", func() {
+		ecSize := 32
+		jwks, err := json.Marshal(map[string]any{"keys": []map[string]any{
+			{"kty": "RSA", "kid": "r1", "alg": "RS256", "n": b64url(rsaKey.N.Bytes()), "e": b64url(big.NewInt(int64(rsaKey.E)).Bytes())},
+			{"kty": "EC", "kid": "e1", "crv": "P-256", "x": b64url(ecKey.X.FillBytes(make([]byte, ecSize))), "y": b64url(ecKey.Y.FillBytes(make([]byte, ecSize)))},
+			{"kty": "oct", "kid": "h1", "k": b64url([]byte("jwks-secret"))},
+			{"kty": "RSA", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		}})
+		Expect(err).NotTo(HaveOccurred())
+
+		ks, err := LoadKeySet("", nil, writeFile(dir, "jwks.json", jwks))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(verifies(ks, "RS256", "r1", rsaKey)).To(BeTrue())
+		Expect(verifies(ks, "RS512", "r1", rsaKey)).To(BeFalse(), "alg pinned by the JWK")
+		Expect(verifies(ks, "ES256", "e1", ecKey)).To(BeTrue())
+		Expect(verifies(ks, "HS256", "h1", []byte("jwks-secret"))).To(BeTrue())
+	})
+
+	DescribeTable("rejects bad key material",
+		func(build func() (string, []string, string), wantSub string) {
+			secret, pems, jwks := build()
+			_, err := LoadKeySet(secret, pems, jwks)
+			Expect(err).To(MatchError(ErrInvalidKey))
+			Expect(err.Error()).To(ContainSubstring(wantSub))
+		},
+		Entry("no keys", func() (string, []string, string) { return "", nil, "" }, "no keys"),
+		Entry("missing PEM file", func() (string, []string, string) {
+			return "", []string{filepath.Join(dir, "nope.pem")}, ""
+		}, "nope.pem"),
+		Entry("PEM without public key", func() (string, []string, string) {
+			return "", []string{writeFile(dir, "empty.pem", []byte("not pem"))}, ""
+		}, "no public key"),
+		Entry("malformed JWKS", func() (string, []string, string) {
+			return "", nil, writeFile(dir, "jwks.json", []byte("{"))
+		}, "jwks"),
+		Entry("unsupported curve", func() (string, []string, string) {
+			return "", nil, writeFile(dir, "jwks.json", []byte(`{"keys":[{"kty":"EC","crv":"P-192","x":"AA","y":"AA"}]}`))
+		}, "unsupported curve"),
+		Entry("point not on curve", func() (string, []string, string) {
+			return "", nil, writeFile(dir, "jwks.json", []byte(`{"keys":[{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}]}`))
+		}, "not on curve"),
+	)
+})
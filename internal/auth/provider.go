@@ -3,10 +3,38 @@
 
 package auth
 
-import "net/http"
+import (
+	"net/http"
+	"slices"
+)
 
 type Principal struct {
-	Name string
+	Name   string
+	Roles  []string
+	Scopes []string
+	// verified token claims, nil for providers without claims
+	Claims map[string]any
+}
+
+// Satisfies reports whether p holds every one of scopes and roles.
+func (p Principal) Satisfies(scopes, roles []string) bool {
+	for _, s := range scopes {
+		if !slices.Contains(p.Scopes, s) {
+			return false
+		}
+	}
+	for _, r := range roles {
+		if !slices.Contains(p.Roles, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Grants are the roles and scopes attached to a user or token.
+type Grants struct {
+	Roles  []string
+	Scopes []string
 }
 
 type Provider interface {
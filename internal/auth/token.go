@@ -13,9 +13,17 @@ type TokenAuth struct {
 	Header  string
 	Prefix  string
 	allowed map[string]struct{}
+	grants  map[string]Grants
 }
 
-func NewTokenAuth(header, prefix string, tokens []string) *TokenAuth {
+type TokenOption func(*TokenAuth)
+
+// WithTokenGrants attaches roles and scopes to tokens.
+func WithTokenGrants(grants map[string]Grants) TokenOption {
+	return func(a *TokenAuth) { a.grants = grants }
+}
+
+func NewTokenAuth(header, prefix string, tokens []string, opts ...TokenOption) *TokenAuth {
 	m := make(map[string]struct{}, len(tokens))
 	for _, t := range tokens {
 		if t == "" {
@@ -23,7 +31,23 @@ func NewTokenAuth(header, prefix string, tokens []string) *TokenAuth {
 		}
 		m[t] = struct{}{}
 	}
-	return &TokenAuth{Header: header, Prefix: prefix, allowed: m}
+	a := &TokenAuth{Header: header, Prefix: prefix, allowed: m}
+	for _, o := range opts {
+		o(a)
+	}
+	return a
+}
+
+// Only returns a copy of a that accepts just the given tokens, as far as
+// a accepts them.
+func (a *TokenAuth) Only(tokens []string) *TokenAuth {
+	m := make(map[string]struct{}, len(tokens))
+	for _, t := range tokens {
+		if _, ok := a.allowed[t]; ok {
+			m[t] = struct{}{}
+		}
+	}
+	return &TokenAuth{Header: a.Header, Prefix: a.Prefix, allowed: m, grants: a.grants}
 }
 
 func (a *TokenAuth) Authenticate(r *http.Request) (Principal, bool, error) {
@@ -43,7 +67,8 @@ func (a *TokenAuth) Authenticate(r *http.Request) (Principal, bool, error) {
 
 	for t := range a.allowed {
 		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
-			return Principal{Name: "token"}, true, nil
+			g := a.grants[t]
+			return Principal{Name: "token", Roles: g.Roles, Scopes: g.Scopes}, true, nil
 		}
 	}
 
@@ -74,5 +74,39 @@ var _ = Describe("TokenAuth", func() {
 			},
 			true, false, []string(nil),
 		),
+		Entry("token outside a restricted subset",
+			"Bearer devtoken123",
+			func() (*TokenAuth, string) {
+				return NewTokenAuth("Authorization", "Bearer ", []string{"devtoken123", "t2"}).Only([]string{"t2", "unknown"}), "Authorization"
+			},
+			false, false, []string(nil),
+		),
+		Entry("token inside a restricted subset",
+			"Bearer t2",
+			func() (*TokenAuth, string) {
+				return NewTokenAuth("Authorization", "Bearer ", []string{"devtoken123", "t2"}).Only([]string{"t2"}), "Authorization"
+			},
+			true, false, []string(nil),
+		),
 	)
+	It("attaches grants to the principal, also through Only", func() {
+		a := NewTokenAuth("Authorization", "Bearer ", []string{"t1", "t2"}, WithTokenGrants(map[string]Grants{
+			"t1": {Roles: []string{"admin"}, Scopes: []string{"users:write"}},
+		}))
+		req := httptest.NewRequest("GET", "http://example.com/x", nil)
+		req.Header.Set("Authorization", "Bearer t1")
+
+		for _, prov := range []*TokenAuth{a, a.Only([]string{"t1"})} {
+			p, ok, err := prov.Authenticate(req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(p.Roles).To(Equal([]string{"admin"}))
+			Expect(p.Scopes).To(Equal([]string{"users:write"}))
+		}
+
+		req.Header.Set("Authorization", "Bearer t2")
+		p, ok, _ := a.Authenticate(req)
+		Expect(ok).To(BeTrue())
+		Expect(p.Roles).To(BeEmpty())
+	})
 })
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package cli
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Bl4cky99/mocker/internal/auth"
+	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/errx"
+	"github.com/Bl4cky99/mocker/internal/render"
+)
+
+// loadChecked loads the config at path and runs checkConfig on it.
+func loadChecked(path string) (*config.Config, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkConfig(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %q: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// checkConfig validates what config leaves to the packages that use it:
+// template syntax and jwt keys.
+func checkConfig(cfg *config.Config) error {
+	e := errx.New()
+
+	for i, ep := range cfg.Endpoints {
+		scope := fmt.Sprintf("endpoints[%d]", i)
+		if ep.Sequence != nil && ep.Sequence.Key != "" {
+			if err := render.Check(render.EngineText, ep.Sequence.Key); err != nil {
+				e.Wrapf(config.ErrEndpointConfig, "%s.sequence.key: %v", scope, err)
+			}
+		}
+
+		for j, rv := range ep.Responses {
+			rscope := fmt.Sprintf("%s.responses[%d]", scope, j)
+			if rv.Proxy {
+				continue
+			}
+			engine := render.EngineFor(rv.Engine, cfg.ContentType(rv))
+			switch {
+			case rv.Body != "":
+				if err := render.Check(engine, rv.Body); err != nil {
+					e.Wrapf(config.ErrTemplate, "%s.body: %v", rscope, err)
+				}
+			case rv.BodyFile != "":
+				if err := render.CheckFile(engine, rv.BodyFile); err != nil {
+					e.Wrapf(config.ErrTemplate, "%s.bodyFile %q: %v", rscope, rv.BodyFile, err)
+				}
+			}
+		}
+	}
+
+	if j := cfg.Auth.JWT; j != nil && cfg.UsesAuth("jwt") {
+		for i, alg := range j.Algorithms {
+			e.If(!slices.Contains(auth.SupportedAlgorithms, alg), config.ErrAuthConfig, "auth.jwt.algorithms[%d]: %q unsupported", i, alg)
+		}
+		if j.Secret != "" || len(j.PublicKeyFiles) > 0 || j.JWKSFile != "" {
+			if _, err := auth.LoadKeySet(j.Secret, j.PublicKeyFiles, j.JWKSFile); err != nil {
+				e.Wrapf(config.ErrAuthConfig, "auth.jwt: %v", err)
+			}
+		}
+	}
+
+	return e.Err()
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/errx"
+)
+
+var _ = Describe("checkConfig", func() {
+	endpoint := func(rv config.ResponseVariant) config.Endpoint {
+		return config.Endpoint{Method: "GET", Path: "/ok", Responses: []config.ResponseVariant{rv}}
+	}
+	writeFile := func(name, content string) string {
+		p := filepath.Join(GinkgoT().TempDir(), name)
+		Expect(os.WriteFile(p, []byte(content), 0o600)).To(Succeed())
+		return p
+	}
+
+	It("accepts templates using helpers and unparsed bodies with engine none", func() {
+		cfg := &config.Config{Endpoints: []config.Endpoint{
+			endpoint(config.ResponseVariant{Status: 200, Body: `{"id": "{{ uuid }}"}`}),
+			endpoint(config.ResponseVariant{Status: 200, Body: "{{ not a template", Engine: "none"}),
+		}}
+		Expect(checkConfig(cfg)).To(Succeed())
+	})
+
+	It("ignores an unused jwt block", func() {
+		nope := filepath.Join(GinkgoT().TempDir(), "nope.pem")
+		cfg := &config.Config{Auth: config.AuthConfig{Type: "none", JWT: &config.JWTAuthConfig{PublicKeyFiles: []string{nope}}}}
+		Expect(checkConfig(cfg)).To(Succeed())
+		opts, err := authOptions(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(opts).To(HaveLen(1))
+	})
+
+	DescribeTable("reports",
+		func(build func() *config.Config, sentinel error, subs []string) {
+			err := checkConfig(build())
+			Expect(err).To(MatchError(sentinel))
+			Expect(errx.ErrContainsAll(err, subs...)).To(BeTrue(), err.Error())
+		},
+		Entry("an inline template syntax error",
+			func() *config.Config {
+				return &config.Config{Endpoints: []config.Endpoint{endpoint(config.ResponseVariant{Status: 200, Body: `{"id": "{{ .Path.id }"}`})}}
+			},
+			config.ErrTemplate, []string{"endpoints[0].responses[0].body"},
+		),
+		Entry("an unknown template helper",
+			func() *config.Config {
+				return &config.Config{Endpoints: []config.Endpoint{endpoint(config.ResponseVariant{Status: 200, Body: `{{ nope }}`})}}
+			},
+			config.ErrTemplate, []string{"endpoints[0].responses[0].body", `function "nope" not defined`},
+		),
+		Entry("a body file template syntax error",
+			func() *config.Config {
+				body := writeFile("broken.tmpl", "{{ if .Query.a }}open")
+				return &config.Config{Endpoints: []config.Endpoint{endpoint(config.ResponseVariant{Status: 200, BodyFile: body})}}
+			},
+			config.ErrTemplate, []string{"endpoints[0].responses[0].bodyFile", "broken.tmpl"},
+		),
+		Entry("an invalid sequence key template",
+			func() *config.Config {
+				ep := endpoint(config.ResponseVariant{Status: 200, Body: "{}"})
+				ep.Sequence = &config.SequenceSpec{Mode: "cycle", Key: "{{ .Path.id"}
+				return &config.Config{Endpoints: []config.Endpoint{ep}}
+			},
+			config.ErrEndpointConfig, []string{"endpoints[0].sequence.key"},
+		),
+		Entry("an unsupported jwt algorithm",
+			func() *config.Config {
+				return &config.Config{Auth: config.AuthConfig{Type: "jwt", JWT: &config.JWTAuthConfig{Secret: "s", Algorithms: []string{"none"}}}}
+			},
+			config.ErrAuthConfig, []string{"auth.jwt.algorithms[0]"},
+		),
+		Entry("a missing jwt public key file",
+			func() *config.Config {
+				nope := filepath.Join(GinkgoT().TempDir(), "nope.pem")
+				return &config.Config{Auth: config.AuthConfig{Type: "jwt", JWT: &config.JWTAuthConfig{PublicKeyFiles: []string{nope}}}}
+			},
+			config.ErrAuthConfig, []string{"auth.jwt", "nope.pem"},
+		),
+		Entry("a jwt public key file without a key",
+			func() *config.Config {
+				pem := writeFile("key.pem", "{}")
+				return &config.Config{Auth: config.AuthConfig{Type: "jwt", JWT: &config.JWTAuthConfig{PublicKeyFiles: []string{pem}}}}
+			},
+			config.ErrAuthConfig, []string{"auth.jwt", "no public key"},
+		),
+	)
+
+	It("runs after loading", func() {
+		path := writeFile("config.yaml", `endpoints:
+  - method: GET
+    path: /ok
+    responses:
+      - status: 200
+        body: '{{ nope }}'
+`)
+		_, err := loadChecked(path)
+		Expect(err).To(MatchError(config.ErrTemplate))
+		Expect(err.Error()).To(ContainSubstring("invalid config"))
+	})
+})
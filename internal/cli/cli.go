@@ -9,17 +9,24 @@ import (
 	"flag"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/Bl4cky99/mocker/internal/auth"
 	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/errx"
 	"github.com/Bl4cky99/mocker/internal/httpx"
+	"github.com/Bl4cky99/mocker/internal/openapi"
 	"github.com/Bl4cky99/mocker/internal/render"
+	"github.com/Bl4cky99/mocker/internal/state"
+	"github.com/Bl4cky99/mocker/internal/watch"
 )
 
 type httpServer interface {
@@ -27,14 +34,23 @@ type httpServer interface {
 	Shutdown(context.Context) error
 }
 
+type reloader interface {
+	Reload(*config.Config, ...httpx.Option) error
+}
+
 var (
-	loadConfig    = config.Load
+	loadConfig    = loadChecked
 	newHTTPServer = func(ctx context.Context, cfg *config.Config, opts ...httpx.Option) (httpServer, error) {
 		return httpx.New(ctx, cfg, opts...)
 	}
 	notifyContext = signal.NotifyContext
 	runServer     = cmdServer
 	runValidate   = cmdValidate
+	runState      = cmdState
+	runRecord     = cmdRecord
+	runImport     = cmdImport
+	runExport     = cmdExport
+	watchInterval = 500 * time.Millisecond
 )
 
 const usageHeader = `mocker - local mock API server
@@ -45,6 +61,10 @@ Usage:
 Commands:
 	server Start the mock server
 	validate Validate a config file and exit
+	record Proxy to an upstream and generate a config from the traffic
+	import Generate a config from an OpenAPI document
+	export Describe the configured endpoints as an OpenAPI document
+	state Export, import or clear saved runtime state
 	version Print version info
 	
 Run 'mocker <command> --help' for command-specific flags.
@@ -64,6 +84,14 @@ func Execute(version, commit, date string) int {
 		return runServer(version, commit, date, os.Args[2:])
 	case "validate":
 		return runValidate(os.Args[2:])
+	case "record":
+		return runRecord(os.Args[2:])
+	case "state":
+		return runState(os.Args[2:])
+	case "import":
+		return runImport(os.Args[2:])
+	case "export":
+		return runExport(os.Args[2:])
 	case "version", "-v", "--version":
 		fmt.Printf("mocker %s (commit %s, built %s)\n", version, commit, date)
 		return 0
@@ -83,6 +111,11 @@ Flags:
 	-a, --addr string		Override server address (e.g. :9000)
 	-l, --log-level string 		Log level: debug|info|warn|error (default: "info")
 	-p, --pretty			Human-readable logs instead of JSON
+	-w, --watch			Reload config, body files and schemas on change
+	    --openapi string		Serve the operations of an OpenAPI document (overrides server.openapi)
+	    --scenario name=state	Start a scenario in the given state (repeatable)
+	    --seed uint			Seed for random variant selection and generated data (reproducible runs)
+	    --state string		Persist runtime state to this file (overrides server.stateFile)
 	    --version			Print version on startup
 `)
 	}
@@ -98,6 +131,18 @@ Flags:
 	pretty := fs.Bool("pretty", false, "")
 	fs.BoolVar(pretty, "p", *pretty, "human-readable logs")
 
+	watchMode := fs.Bool("watch", false, "")
+	fs.BoolVar(watchMode, "w", *watchMode, "reload on config, body and schema changes")
+
+	scenarioStates := scenarioFlag{}
+	fs.Var(scenarioStates, "scenario", "initial scenario state as name=state")
+
+	seed := fs.Uint64("seed", 0, "seed for random variant selection and generated data")
+
+	statePath := fs.String("state", "", "state file")
+
+	specPath := fs.String("openapi", "", "OpenAPI document to serve")
+
 	printVersion := fs.Bool("version", false, "")
 
 	if err := fs.Parse(args); err != nil {
@@ -121,34 +166,82 @@ Flags:
 	ctx, stop := notifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	cfg, err := loadConfig(*cfgPath)
+	so := serveOptions{cfgPath: *cfgPath, addr: *addr, openapi: *specPath}
+	if *specPath != "" && !flagSet(fs, "config") {
+		if _, err := os.Stat(*cfgPath); errors.Is(err, os.ErrNotExist) {
+			// serve the document alone
+			so.cfgPath = ""
+		}
+	}
+
+	cfg, err := so.load()
 	if err != nil {
 		log.Error("load config", "path", *cfgPath, "err", err)
 		return 1
 	}
-	if *addr != "" {
-		cfg.Server.Addr = *addr
+
+	authOpts, err := authOptions(cfg)
+	if err != nil {
+		log.Error("init auth", "err", err)
+		return 1
 	}
 
-	var prov auth.Provider
-	switch cfg.Auth.Type {
-	case "token":
-		prov = auth.NewTokenAuth(cfg.Auth.Token.Header, cfg.Auth.Token.Prefix, cfg.Auth.Token.Tokens)
-	case "basic":
-		users := make(map[string]string, len(cfg.Auth.Basic.Users))
-		for _, u := range cfg.Auth.Basic.Users {
-			users[u.Username] = u.Password
-		}
-		prov = auth.NewBasicAuth(users, "mocker")
+	var renderOpts []render.Option
+	opts := append([]httpx.Option{httpx.WithLogger(log)}, authOpts...)
+	if len(scenarioStates) > 0 {
+		opts = append(opts, httpx.WithScenarioStates(scenarioStates))
+	}
+	if flagSet(fs, "seed") {
+		log.Info("using fixed seed", "seed", *seed)
+		opts = append(opts, httpx.WithSeed(*seed))
+		renderOpts = append(renderOpts, render.WithSeed(*seed))
 	}
-	r := render.New()
+	opts = append(opts, httpx.WithRenderer(render.New(renderOpts...)))
 
-	srv, err := newHTTPServer(ctx, cfg, httpx.WithLogger(log), httpx.WithAuth(prov, cfg.Auth.Type), httpx.WithRenderer(r))
+	srv, err := newHTTPServer(ctx, cfg, opts...)
 	if err != nil {
 		log.Error("init server", "err", err)
 		return 1
 	}
 
+	stateFile := cfg.Server.StateFile
+	if *statePath != "" {
+		stateFile = *statePath
+	}
+	var (
+		st        stateful
+		persisted chan struct{}
+	)
+	if stateFile != "" {
+		var ok bool
+		if st, ok = srv.(stateful); !ok {
+			log.Warn("state persistence not supported by server, ignoring state file")
+		} else {
+			if err := restoreState(log, stateFile, st); err != nil {
+				log.Error("restore state", "path", stateFile, "err", err)
+				return 1
+			}
+			interval := defaultStateInterval
+			if cfg.Server.StateInterval > 0 {
+				interval = time.Duration(cfg.Server.StateInterval) * time.Second
+			}
+			persisted = make(chan struct{})
+			go func() {
+				defer close(persisted)
+				persistState(ctx, log, stateFile, interval, st)
+			}()
+		}
+	}
+
+	if *watchMode {
+		rl, ok := srv.(reloader)
+		if !ok {
+			log.Warn("watch mode not supported by server, ignoring --watch")
+		} else {
+			go watchConfig(ctx, log, so, cfg, rl)
+		}
+	}
+
 	go func() {
 		log.Info("server starting", "addr", cfg.Server.Addr, "basePath", cfg.Server.BasePath)
 		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -162,14 +255,193 @@ Flags:
 	shutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	log.Info("shutting down...")
-	if err := srv.Shutdown(shutCtx); err != nil {
-		log.Error("graceful shutdown failed", "err", err)
+	shutErr := srv.Shutdown(shutCtx)
+	if st != nil {
+		// a tick still saving must not overwrite the final snapshot
+		<-persisted
+		if err := state.Save(stateFile, st.Snapshot()); err != nil {
+			log.Error("save state", "path", stateFile, "err", err)
+		} else {
+			log.Info("state saved", "path", stateFile)
+		}
+	}
+	if shutErr != nil {
+		log.Error("graceful shutdown failed", "err", shutErr)
 		return 1
 	}
 	log.Info("bye")
 	return 0
 }
 
+// authOptions sets the provider of auth.type as the server default and
+// registers every other configured provider for endpoint overrides. For
+// "any" and "all" the server combines the registered providers itself.
+func authOptions(cfg *config.Config) ([]httpx.Option, error) {
+	def, err := buildAuth(cfg, cfg.Auth.Type)
+	if err != nil {
+		return nil, err
+	}
+	opts := []httpx.Option{httpx.WithAuth(def, cfg.Auth.Type)}
+	for _, typ := range config.AuthProviders {
+		if typ == cfg.Auth.Type || !cfg.Auth.Configured(typ) || !cfg.UsesAuth(typ) {
+			continue
+		}
+		p, err := buildAuth(cfg, typ)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, httpx.WithAuthProvider(typ, p))
+	}
+	return opts, nil
+}
+
+func buildAuth(cfg *config.Config, typ string) (auth.Provider, error) {
+	switch typ {
+	case "token":
+		t := cfg.Auth.Token
+		grants := make(map[string]auth.Grants, len(t.Grants))
+		for tok, g := range t.Grants {
+			grants[tok] = auth.Grants{Roles: g.Roles, Scopes: g.Scopes}
+		}
+		return auth.NewTokenAuth(t.Header, t.Prefix, t.Tokens, auth.WithTokenGrants(grants)), nil
+	case "basic":
+		users := make(map[string]string, len(cfg.Auth.Basic.Users))
+		grants := make(map[string]auth.Grants, len(cfg.Auth.Basic.Users))
+		for _, u := range cfg.Auth.Basic.Users {
+			users[u.Username] = u.Password
+			grants[u.Username] = auth.Grants{Roles: u.Roles, Scopes: u.Scopes}
+		}
+		return auth.NewBasicAuth(users, "mocker", auth.WithUserGrants(grants)), nil
+	case "jwt":
+		return buildJWTAuth(cfg.Auth.JWT)
+	}
+	return nil, nil
+}
+
+func buildJWTAuth(j *config.JWTAuthConfig) (*auth.JWTAuth, error) {
+	// without own keys only the built-in oauth issuer's tokens are accepted
+	keys := &auth.KeySet{}
+	if j.Secret != "" || len(j.PublicKeyFiles) > 0 || j.JWKSFile != "" {
+		var err error
+		if keys, err = auth.LoadKeySet(j.Secret, j.PublicKeyFiles, j.JWKSFile); err != nil {
+			return nil, err
+		}
+	}
+	opts := []auth.JWTOption{
+		auth.WithTokenHeader(j.Header, j.Prefix),
+		auth.WithIssuer(j.Issuer),
+		auth.WithAudience(j.Audience...),
+		auth.WithAlgorithms(j.Algorithms...),
+		auth.WithClockSkew(time.Duration(j.ClockSkew) * time.Second),
+	}
+	if j.RolesClaim != "" {
+		opts = append(opts, auth.WithRolesClaim(j.RolesClaim))
+	}
+	return auth.NewJWTAuth(keys, opts...), nil
+}
+
+func watchConfig(ctx context.Context, log *slog.Logger, so serveOptions, cfg *config.Config, srv reloader) {
+	var mu sync.Mutex
+	files := so.files(cfg)
+	// only the watch callback touches applied, one change at a time
+	applied := cfg
+	paths := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return files
+	}
+
+	log.Info("watching for changes", "files", len(files))
+	watch.NewPoller(watchInterval, paths).Run(ctx, func(changed []string) {
+		log.Info("change detected, reloading", "files", changed)
+
+		next, err := so.load()
+		if err != nil {
+			log.Error("reload rejected, keeping previous config", "path", so.cfgPath)
+			for _, e := range errx.List(err) {
+				log.Error("config error", "err", e)
+			}
+			return
+		}
+		if next.Server.Addr != applied.Server.Addr {
+			log.Warn("server.addr changed, restart required to apply", "addr", next.Server.Addr)
+		}
+
+		authOpts, err := authOptions(next)
+		if err != nil {
+			log.Error("reload rejected, keeping previous config", "err", err)
+			return
+		}
+		if err := srv.Reload(next, authOpts...); err != nil {
+			log.Error("reload rejected, keeping previous config", "err", err)
+			return
+		}
+
+		applied = next
+		mu.Lock()
+		files = so.files(next)
+		mu.Unlock()
+		log.Info("config reloaded", "endpoints", len(next.Endpoints))
+	})
+}
+
+// serveOptions are the serve flags that shape the loaded config, applied
+// again on every reload.
+type serveOptions struct {
+	// empty when serving an OpenAPI document without a config file
+	cfgPath string
+	addr    string
+	openapi string
+}
+
+func (o serveOptions) load() (*config.Config, error) {
+	var (
+		cfg *config.Config
+		err error
+	)
+	if o.cfgPath == "" {
+		cfg, err = specOnlyConfig(o.openapi)
+	} else {
+		cfg, err = loadConfig(o.cfgPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if o.addr != "" {
+		cfg.Server.Addr = o.addr
+	}
+	if o.openapi != "" && cfg.Server.OpenAPI != o.openapi {
+		cfg.Server.OpenAPI = o.openapi
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+func (o serveOptions) files(cfg *config.Config) []string {
+	if o.cfgPath == "" {
+		return cfg.Files()
+	}
+	return append([]string{o.cfgPath}, cfg.Files()...)
+}
+
+// specOnlyConfig serves an OpenAPI document with default settings, mounted
+// at the path of its first server URL.
+func specOnlyConfig(path string) (*config.Config, error) {
+	doc, err := openapi.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &config.Config{Server: config.ServerConfig{BasePath: doc.BasePath(), OpenAPI: path}}
+	cfg.ApplyDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
 func cmdValidate(args []string) int {
 	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
 	fs.Usage = func() {
@@ -201,6 +473,36 @@ Flags:
 	return 0
 }
 
+func flagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// scenarioFlag collects repeated --scenario name=state flags.
+type scenarioFlag map[string]string
+
+func (f scenarioFlag) String() string {
+	parts := make([]string, 0, len(f))
+	for _, k := range slices.Sorted(maps.Keys(f)) {
+		parts = append(parts, k+"="+f[k])
+	}
+	return strings.Join(parts, ",")
+}
+
+func (f scenarioFlag) Set(v string) error {
+	name, state, ok := strings.Cut(v, "=")
+	if !ok || strings.TrimSpace(name) == "" || strings.TrimSpace(state) == "" {
+		return fmt.Errorf("expected name=state, got %q", v)
+	}
+	f[strings.TrimSpace(name)] = strings.TrimSpace(state)
+	return nil
+}
+
 func parseLevel(s string) slog.Level {
 	switch strings.ToLower(s) {
 	case "debug":
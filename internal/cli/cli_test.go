@@ -11,6 +11,9 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -41,6 +44,23 @@ func capture(target **os.File, fn func()) string {
 	return out
 }
 
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
 type fakeServer struct {
 	cancel        context.CancelFunc
 	listenErr     error
@@ -162,6 +182,15 @@ var _ = Describe("cmdServer", func() {
 		Expect(stderr).To(ContainSubstring("flag provided"))
 	})
 
+	It("exits 2 on a malformed --scenario flag", func() {
+		var code int
+		stderr := capture(&os.Stderr, func() {
+			code = cmdServer("v", "c", "d", []string{"--scenario", "orders"})
+		})
+		Expect(code).To(Equal(2))
+		Expect(stderr).To(ContainSubstring("expected name=state"))
+	})
+
 	It("exits 1 and logs when config cannot be loaded", func() {
 		prev := loadConfig
 		loadConfig = func(string) (*config.Config, error) { return nil, errors.New("boom") }
@@ -236,6 +265,40 @@ var _ = Describe("cmdServer", func() {
 		Expect(stdout).NotTo(BeEmpty())
 	})
 
+	It("passes a fixed seed to the server", func() {
+		prevLoad := loadConfig
+		loadConfig = func(string) (*config.Config, error) {
+			return &config.Config{Server: config.ServerConfig{}, Auth: config.AuthConfig{Type: "none"}}, nil
+		}
+		defer func() { loadConfig = prevLoad }()
+
+		var cancel context.CancelFunc
+		prevNotify := notifyContext
+		notifyContext = func(ctx context.Context, _ ...os.Signal) (context.Context, context.CancelFunc) {
+			ctx, cancel = context.WithCancel(ctx)
+			return ctx, cancel
+		}
+		defer func() { notifyContext = prevNotify }()
+
+		fake := &fakeServer{listenErr: http.ErrServerClosed}
+		var gotOpts []httpx.Option
+		prevNew := newHTTPServer
+		newHTTPServer = func(ctx context.Context, c *config.Config, opts ...httpx.Option) (httpServer, error) {
+			gotOpts = append([]httpx.Option(nil), opts...)
+			fake.cancel = cancel
+			return fake, nil
+		}
+		defer func() { newHTTPServer = prevNew }()
+
+		var code int
+		stdout := capture(&os.Stdout, func() {
+			code = cmdServer("v", "c", "d", []string{"--seed", "42"})
+		})
+		Expect(code).To(Equal(0))
+		Expect(gotOpts).To(HaveLen(4))
+		Expect(stdout).To(ContainSubstring(`"seed":42`))
+	})
+
 	It("exits 0 and logs the error when ListenAndServe returns an unexpected error", func() {
 		prevLoad := loadConfig
 		loadConfig = func(string) (*config.Config, error) {
@@ -326,6 +389,113 @@ var _ = Describe("cmdServer", func() {
 	})
 })
 
+type fakeReloader struct {
+	mu      sync.Mutex
+	configs []*config.Config
+	err     error
+}
+
+func (f *fakeReloader) Reload(cfg *config.Config, _ ...httpx.Option) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.configs = append(f.configs, cfg)
+	return nil
+}
+
+func (f *fakeReloader) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.configs)
+}
+
+var _ = Describe("watchConfig", func() {
+	var (
+		cfgPath string
+		buf     *syncBuffer
+		log     *slog.Logger
+	)
+
+	BeforeEach(func() {
+		cfgPath = filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(cfgPath, []byte("a"), 0o600)).To(Succeed())
+		buf = &syncBuffer{}
+		log = slog.New(slog.NewTextHandler(buf, nil))
+
+		prev := watchInterval
+		watchInterval = 5 * time.Millisecond
+		DeferCleanup(func() { watchInterval = prev })
+	})
+
+	touch := func() {
+		Expect(os.WriteFile(cfgPath, []byte("changed"), 0o600)).To(Succeed())
+		future := time.Now().Add(time.Second)
+		Expect(os.Chtimes(cfgPath, future, future)).To(Succeed())
+	}
+
+	It("reloads the server when the config file changes", func() {
+		prev := loadConfig
+		loadConfig = func(string) (*config.Config, error) {
+			return &config.Config{Auth: config.AuthConfig{Type: "none"}}, nil
+		}
+		defer func() { loadConfig = prev }()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		rl := &fakeReloader{}
+		go watchConfig(ctx, log, serveOptions{cfgPath: cfgPath, addr: ":9999"}, &config.Config{}, rl)
+
+		Eventually(buf.String).Should(ContainSubstring("watching for changes"))
+		touch()
+		Eventually(rl.count).Should(Equal(1))
+		Expect(rl.configs[0].Server.Addr).To(Equal(":9999"))
+		Eventually(buf.String).Should(ContainSubstring("config reloaded"))
+	})
+
+	It("warns about a changed addr only once", func() {
+		prev := loadConfig
+		loadConfig = func(string) (*config.Config, error) {
+			return &config.Config{Auth: config.AuthConfig{Type: "none"}}, nil
+		}
+		defer func() { loadConfig = prev }()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		rl := &fakeReloader{}
+		start := &config.Config{Server: config.ServerConfig{Addr: ":8080"}}
+		go watchConfig(ctx, log, serveOptions{cfgPath: cfgPath, addr: ":9999"}, start, rl)
+
+		Eventually(buf.String).Should(ContainSubstring("watching for changes"))
+		touch()
+		Eventually(rl.count).Should(Equal(1))
+		future := time.Now().Add(2 * time.Second)
+		Expect(os.Chtimes(cfgPath, future, future)).To(Succeed())
+		Eventually(rl.count).Should(Equal(2))
+		Expect(strings.Count(buf.String(), "restart required")).To(Equal(1))
+	})
+
+	It("keeps the old config and logs each error when the new one is invalid", func() {
+		prev := loadConfig
+		loadConfig = func(string) (*config.Config, error) {
+			return nil, errors.Join(errors.New("first problem"), errors.New("second problem"))
+		}
+		defer func() { loadConfig = prev }()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		rl := &fakeReloader{}
+		go watchConfig(ctx, log, serveOptions{cfgPath: cfgPath}, &config.Config{}, rl)
+
+		Eventually(buf.String).Should(ContainSubstring("watching for changes"))
+		touch()
+		Eventually(buf.String).Should(ContainSubstring("second problem"))
+		Expect(buf.String()).To(ContainSubstring("first problem"))
+		Expect(rl.count()).To(Equal(0))
+	})
+})
+
 var _ = Describe("cmdValidate", func() {
 	It("exits 0 and prints 'config ok' on success", func() {
 		prev := loadConfig
@@ -383,3 +553,55 @@ var _ = Describe("parseLevel", func() {
 		Entry("info (default)", "info", slog.LevelInfo),
 	)
 })
+
+var _ = Describe("scenarioFlag", func() {
+	It("collects repeated name=state pairs", func() {
+		f := scenarioFlag{}
+		Expect(f.Set("orders=created")).To(Succeed())
+		Expect(f.Set(" carts = empty ")).To(Succeed())
+		Expect(f).To(Equal(scenarioFlag{"orders": "created", "carts": "empty"}))
+		Expect(f.String()).To(Equal("carts=empty,orders=created"))
+
+		Expect(f.Set("=x")).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("serveOptions", func() {
+	writeSpec := func() string {
+		p := filepath.Join(GinkgoT().TempDir(), "spec.yaml")
+		Expect(os.WriteFile(p, []byte(`openapi: 3.0.3
+info: { title: t, version: "1" }
+servers: [{ url: "https://api.example.com/v2" }]
+paths:
+  /ping: { get: { responses: { "200": { description: ok } } } }
+`), 0o600)).To(Succeed())
+		return p
+	}
+
+	It("serves a document without a config file", func() {
+		spec := writeSpec()
+		cfg, err := serveOptions{openapi: spec, addr: ":7000"}.load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Server.OpenAPI).To(Equal(spec))
+		Expect(cfg.Server.BasePath).To(Equal("/v2"))
+		Expect(cfg.Server.Addr).To(Equal(":7000"))
+		Expect(serveOptions{openapi: spec}.files(cfg)).To(Equal([]string{spec}))
+	})
+
+	It("overrides server.openapi of a loaded config", func() {
+		prevLoad := loadConfig
+		loadConfig = func(string) (*config.Config, error) {
+			return &config.Config{Server: config.ServerConfig{BasePath: "/"}, Auth: config.AuthConfig{Type: "none"}}, nil
+		}
+		defer func() { loadConfig = prevLoad }()
+
+		spec := writeSpec()
+		cfg, err := serveOptions{cfgPath: "cfg.yaml", openapi: spec}.load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Server.OpenAPI).To(Equal(spec))
+		Expect(cfg.Server.BasePath).To(Equal("/"))
+
+		_, err = serveOptions{cfgPath: "cfg.yaml", openapi: "missing.yaml"}.load()
+		Expect(err).To(MatchError(ContainSubstring("server.openapi")))
+	})
+})
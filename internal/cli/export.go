@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Bl4cky99/mocker/internal/errx"
+	"github.com/Bl4cky99/mocker/internal/openapi"
+	"github.com/Bl4cky99/mocker/internal/render"
+	"gopkg.in/yaml.v3"
+)
+
+func cmdExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), `Usage: mocker export openapi [flags]
+
+Writes an OpenAPI 3.1 document describing the configured endpoints.
+
+Flags:
+	-c, --config string		Path to config file (yaml|yml|json) (default "config.yaml")
+	-o, --out string		Output file, YAML for .yaml/.yml (default: JSON on stdout)
+`)
+	}
+	cfgPath := fs.String("config", "config.yaml", "")
+	fs.StringVar(cfgPath, "c", *cfgPath, "path to config file")
+	out := fs.String("out", "", "")
+	fs.StringVar(out, "o", *out, "output file")
+
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v", err.Error())
+		return 2
+	}
+	if len(pos) != 1 || pos[0] != "openapi" {
+		fs.Usage()
+		return 2
+	}
+
+	cfg, err := loadConfig(*cfgPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid config: %v\n", err)
+		return 1
+	}
+
+	doc, err := openapi.Export(cfg, render.New())
+	for _, e := range errx.List(err) {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", e)
+	}
+
+	var b []byte
+	switch strings.ToLower(filepath.Ext(*out)) {
+	case ".yaml", ".yml":
+		// round-trip through JSON so the YAML keys follow the json tags
+		var tree any
+		js, _ := json.Marshal(doc)
+		_ = json.Unmarshal(js, &tree)
+		b, err = yaml.Marshal(tree)
+	default:
+		b, err = json.MarshalIndent(doc, "", "  ")
+		b = append(b, '\n')
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "encode document: %v\n", err)
+		return 1
+	}
+
+	if *out == "" {
+		_, _ = os.Stdout.Write(b)
+		return 0
+	}
+	if err := os.WriteFile(*out, b, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "write %s: %v\n", *out, err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "wrote %s\n", *out)
+	return 0
+}
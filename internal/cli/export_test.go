@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/openapi"
+)
+
+var _ = Describe("cmdExport", func() {
+	run := func(args ...string) (int, string, string) {
+		var code int
+		var stdout string
+		stderr := capture(&os.Stderr, func() {
+			stdout = capture(&os.Stdout, func() {
+				code = cmdExport(args)
+			})
+		})
+		return code, stdout, stderr
+	}
+
+	writeConfig := func() string {
+		p := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(p, []byte(`server: { basePath: /api }
+endpoints:
+  - method: GET
+    path: /users/{id}
+    responses:
+      - status: 200
+        headers: { Content-Type: application/json }
+        body: '{"id": {{ .Path.id }}}'
+`), 0o600)).To(Succeed())
+		return p
+	}
+
+	It("exits 2 without the openapi format", func() {
+		code, _, _ := run("-c", writeConfig())
+		Expect(code).To(Equal(2))
+	})
+
+	It("exits 1 for an invalid config", func() {
+		code, _, stderr := run("openapi", "-c", filepath.Join(GinkgoT().TempDir(), "nope.yaml"))
+		Expect(code).To(Equal(1))
+		Expect(stderr).To(ContainSubstring("invalid config"))
+	})
+
+	It("prints JSON to stdout", func() {
+		code, stdout, _ := run("openapi", "-c", writeConfig())
+		Expect(code).To(Equal(0))
+
+		doc, err := openapi.Parse([]byte(stdout))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(doc.Paths["/users/{id}"].Get.Responses["200"].Content["application/json"].Example).
+			To(Equal(map[string]any{"id": 1.0}))
+	})
+
+	It("writes YAML when the output file asks for it", func() {
+		out := filepath.Join(GinkgoT().TempDir(), "openapi.yaml")
+		code, _, _ := run("openapi", "-c", writeConfig(), "-o", out)
+		Expect(code).To(Equal(0))
+
+		doc, err := openapi.Load(out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(doc.Servers[0].URL).To(Equal("/api"))
+	})
+})
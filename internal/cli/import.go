@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Bl4cky99/mocker/internal/openapi"
+)
+
+func cmdImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), `Usage: mocker import openapi <spec> [flags]
+
+Generates a config with one endpoint per operation of an OpenAPI 3.0/3.1
+document. Non-default responses are served for "Prefer: code=<status>".
+
+Flags:
+	-o, --out string		Config file to write (yaml|yml|json) (default "config.yaml")
+	    --bodies string		Directory for body files (default "<out dir>/bodies")
+	    --schemas string		Directory for request schemas (default "<out dir>/schemas")
+`)
+	}
+	out := fs.String("out", "config.yaml", "")
+	fs.StringVar(out, "o", *out, "config file to write")
+	bodies := fs.String("bodies", "", "directory for body files")
+	schemas := fs.String("schemas", "", "directory for request schemas")
+
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v", err.Error())
+		return 2
+	}
+	if len(pos) != 2 || pos[0] != "openapi" {
+		fs.Usage()
+		return 2
+	}
+	if *bodies == "" {
+		*bodies = filepath.Join(filepath.Dir(*out), "bodies")
+	}
+	if *schemas == "" {
+		*schemas = filepath.Join(filepath.Dir(*out), "schemas")
+	}
+
+	doc, err := openapi.Load(pos[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import failed: %v\n", err)
+		return 1
+	}
+
+	gen := openapi.Import(doc, *bodies, *schemas)
+	for _, w := range gen.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	if len(gen.Config.Endpoints) == 0 {
+		fmt.Fprintln(os.Stderr, "import failed: no operations to import")
+		return 1
+	}
+	if err := gen.Write(*out); err != nil {
+		fmt.Fprintf(os.Stderr, "write config: %v\n", err)
+		return 1
+	}
+	if _, err := loadConfig(*out); err != nil {
+		fmt.Fprintf(os.Stderr, "generated config does not validate: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stdout, "wrote %s: %d endpoints, %d files\n", *out, len(gen.Config.Endpoints), len(gen.Files))
+	return 0
+}
+
+// parseInterspersed parses flags that may follow positional arguments and
+// returns the positionals in order.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return pos, nil
+		}
+		pos = append(pos, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+)
+
+const importSpec = `openapi: 3.1.0
+info: { title: t, version: "1" }
+paths:
+  /users/{id}:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: { type: object, properties: { id: { type: integer } } }
+    trace:
+      responses:
+        "200": { description: ok }
+`
+
+var _ = Describe("cmdImport", func() {
+	run := func(args ...string) (int, string, string) {
+		var code int
+		var stdout string
+		stderr := capture(&os.Stderr, func() {
+			stdout = capture(&os.Stdout, func() {
+				code = cmdImport(args)
+			})
+		})
+		return code, stdout, stderr
+	}
+
+	It("exits 2 without a format and spec", func() {
+		code, _, _ := run()
+		Expect(code).To(Equal(2))
+
+		code, _, _ = run("swagger", "spec.yaml")
+		Expect(code).To(Equal(2))
+	})
+
+	It("exits 1 when the spec cannot be read", func() {
+		code, _, stderr := run("openapi", filepath.Join(GinkgoT().TempDir(), "nope.yaml"))
+		Expect(code).To(Equal(1))
+		Expect(stderr).To(ContainSubstring("import failed"))
+	})
+
+	It("writes a loadable config with flags after the spec", func() {
+		dir := GinkgoT().TempDir()
+		spec := filepath.Join(dir, "spec.yaml")
+		Expect(os.WriteFile(spec, []byte(importSpec), 0o600)).To(Succeed())
+		out := filepath.Join(dir, "mocks", "config.yaml")
+
+		code, stdout, stderr := run("openapi", spec, "-o", out)
+		Expect(code).To(Equal(0))
+		Expect(stdout).To(ContainSubstring("1 endpoints"))
+		Expect(stderr).To(ContainSubstring("warning: TRACE /users/{id}"))
+
+		cfg, err := config.Load(out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Endpoints[0].Path).To(Equal("/users/{id}"))
+		Expect(cfg.Endpoints[0].Responses[0].BodyFile).To(Equal(filepath.Join(dir, "mocks", "bodies", "GET_users_id_200.json")))
+	})
+})
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/Bl4cky99/mocker/internal/record"
+)
+
+// serveRecorder runs h on addr until ctx is done.
+var serveRecorder = func(ctx context.Context, log *slog.Logger, addr string, h http.Handler) error {
+	srv := &http.Server{Addr: addr, Handler: h}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func cmdRecord(args []string) int {
+	fs := flag.NewFlagSet("record", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), `Usage: mocker record --upstream <url> [flags]
+
+Proxies every request to the upstream and, on Ctrl+C, writes a config with
+one endpoint per method and path plus the recorded bodies.
+
+Flags:
+	-u, --upstream string		Upstream base URL (required)
+	-o, --out string		Config file to write (yaml|yml|json) (default "config.yaml")
+	    --bodies string		Directory for body files (default "<out dir>/bodies")
+	-a, --addr string		Proxy listen address (default ":8080")
+	-p, --pretty			Human-readable logs instead of JSON
+`)
+	}
+	upstream := fs.String("upstream", "", "")
+	fs.StringVar(upstream, "u", *upstream, "upstream base URL")
+	out := fs.String("out", "config.yaml", "")
+	fs.StringVar(out, "o", *out, "config file to write")
+	bodies := fs.String("bodies", "", "directory for body files")
+	addr := fs.String("addr", ":8080", "")
+	fs.StringVar(addr, "a", *addr, "proxy listen address")
+	pretty := fs.Bool("pretty", false, "")
+	fs.BoolVar(pretty, "p", *pretty, "human-readable logs")
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "%v", err.Error())
+		return 2
+	}
+
+	u, err := url.Parse(*upstream)
+	if *upstream == "" || err != nil || u.Scheme == "" || u.Host == "" {
+		fmt.Fprintln(os.Stderr, "--upstream must be an absolute URL such as https://api.example.com")
+		fs.Usage()
+		return 2
+	}
+	if *bodies == "" {
+		*bodies = filepath.Join(filepath.Dir(*out), "bodies")
+	}
+
+	var handler slog.Handler
+	if *pretty {
+		handler = slog.NewTextHandler(os.Stdout, nil)
+	} else {
+		handler = slog.NewJSONHandler(os.Stdout, nil)
+	}
+	log := slog.New(handler).With("svc", "mocker", "cmd", "record")
+
+	ctx, stop := notifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	rec := record.New(u, log)
+	log.Info("recording", "addr", *addr, "upstream", u.String(), "out", *out)
+	if err := serveRecorder(ctx, log, *addr, rec); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error("proxy error", "err", err)
+		return 1
+	}
+
+	exchanges := rec.Exchanges()
+	if len(exchanges) == 0 {
+		log.Warn("nothing recorded, no config written")
+		return 1
+	}
+
+	gen := record.Build(exchanges, *bodies)
+	if err := gen.Write(*out); err != nil {
+		log.Error("write config", "path", *out, "err", err)
+		return 1
+	}
+	if _, err := loadConfig(*out); err != nil {
+		log.Error("generated config does not validate", "path", *out, "err", err)
+		return 1
+	}
+
+	log.Info("config written", "path", *out, "endpoints", len(gen.Config.Endpoints), "bodies", len(gen.Files))
+	return 0
+}
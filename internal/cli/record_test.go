@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package cli
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+)
+
+var _ = Describe("cmdRecord", func() {
+	run := func(args ...string) (int, string) {
+		var code int
+		stderr := capture(&os.Stderr, func() {
+			_ = capture(&os.Stdout, func() {
+				code = cmdRecord(args)
+			})
+		})
+		return code, stderr
+	}
+
+	It("exits 2 without a valid upstream", func() {
+		code, stderr := run()
+		Expect(code).To(Equal(2))
+		Expect(stderr).To(ContainSubstring("--upstream"))
+
+		code, _ = run("--upstream", "not-a-url")
+		Expect(code).To(Equal(2))
+	})
+
+	It("writes a loadable config from the proxied traffic", func() {
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"path":"`+r.URL.Path+`"}`)
+		}))
+		defer upstream.Close()
+
+		prevServe := serveRecorder
+		serveRecorder = func(_ context.Context, _ *slog.Logger, addr string, h http.Handler) error {
+			Expect(addr).To(Equal(":9999"))
+			for _, p := range []string{"/a", "/b"} {
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, p, nil))
+				Expect(rec.Code).To(Equal(http.StatusOK))
+			}
+			return nil
+		}
+		defer func() { serveRecorder = prevServe }()
+
+		prevNotify := notifyContext
+		notifyContext = func(ctx context.Context, _ ...os.Signal) (context.Context, context.CancelFunc) {
+			return context.WithCancel(ctx)
+		}
+		defer func() { notifyContext = prevNotify }()
+
+		out := filepath.Join(GinkgoT().TempDir(), "mocks", "config.yaml")
+		code, _ := run("-u", upstream.URL, "-o", out, "-a", ":9999")
+		Expect(code).To(Equal(0))
+
+		cfg, err := config.Load(out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Endpoints).To(HaveLen(2))
+		Expect(cfg.Endpoints[1].Responses[0].BodyFile).To(Equal(filepath.Join(filepath.Dir(out), "bodies", "GET_b.json")))
+	})
+})
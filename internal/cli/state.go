@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/Bl4cky99/mocker/internal/errx"
+	"github.com/Bl4cky99/mocker/internal/state"
+)
+
+type stateful interface {
+	Snapshot() state.Snapshot
+	Restore(state.Snapshot) error
+}
+
+const defaultStateInterval = 30 * time.Second
+
+// restoreState loads path into srv. Entries that no longer match the config
+// are logged and skipped.
+func restoreState(log *slog.Logger, path string, srv stateful) error {
+	snap, err := state.Load(path)
+	if err != nil {
+		return err
+	}
+	for _, e := range errx.List(srv.Restore(snap)) {
+		log.Warn("state entry skipped", "path", path, "err", e)
+	}
+	log.Info("state restored", "path", path, "resources", len(snap.Resources), "scenarios", len(snap.Scenarios))
+	return nil
+}
+
+// persistState saves a snapshot of srv every interval until ctx is done.
+func persistState(ctx context.Context, log *slog.Logger, path string, interval time.Duration, srv stateful) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if err := state.Save(path, srv.Snapshot()); err != nil {
+				log.Error("state snapshot failed", "path", path, "err", err)
+			}
+		}
+	}
+}
+
+func cmdState(args []string) int {
+	fs := flag.NewFlagSet("state", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), `Usage: mocker state <export|import|clear> [flags] [file]
+
+Commands:
+	export			Write the saved state as JSON to stdout (or -o)
+	import <file>		Replace the saved state with file
+	clear			Delete the saved state
+
+Flags:
+	-c, --config string		Config file whose server.stateFile is used
+	    --state string		State file (overrides --config)
+	-o, --out string		Output file for export (default stdout)
+
+Run while the server is stopped; a running server overwrites the file on shutdown.
+`)
+	}
+	if len(args) == 0 {
+		fs.Usage()
+		return 2
+	}
+	sub, args := args[0], args[1:]
+
+	cfgPath := fs.String("config", "", "")
+	fs.StringVar(cfgPath, "c", *cfgPath, "config file")
+	statePath := fs.String("state", "", "state file")
+	out := fs.String("out", "", "")
+	fs.StringVar(out, "o", *out, "output file")
+
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(os.Stderr, "%v", err.Error())
+		return 2
+	}
+
+	path := *statePath
+	if path == "" && *cfgPath != "" {
+		cfg, err := loadConfig(*cfgPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid config: %v\n", err)
+			return 1
+		}
+		path = cfg.Server.StateFile
+	}
+	if path == "" {
+		fmt.Fprintln(os.Stderr, "no state file: pass --state or a config with server.stateFile")
+		return 2
+	}
+
+	switch sub {
+	case "export":
+		snap, err := state.Load(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "export: %v\n", err)
+			return 1
+		}
+		w := os.Stdout
+		if *out != "" {
+			f, err := os.Create(*out)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "export: %v\n", err)
+				return 1
+			}
+			defer f.Close()
+			w = f
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(snap); err != nil {
+			fmt.Fprintf(os.Stderr, "export: %v\n", err)
+			return 1
+		}
+	case "import":
+		if fs.NArg() != 1 {
+			fs.Usage()
+			return 2
+		}
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "import: %v\n", err)
+			return 1
+		}
+		snap, err := state.Decode(f)
+		_ = f.Close()
+		if err == nil {
+			err = state.Save(path, snap)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "import: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stdout, "state imported into %s\n", path)
+	case "clear":
+		if err := state.Clear(path); err != nil {
+			fmt.Fprintf(os.Stderr, "clear: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stdout, "state cleared: %s\n", path)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown state command %q\n", sub)
+		fs.Usage()
+		return 2
+	}
+	return 0
+}
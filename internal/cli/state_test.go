@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/httpx"
+	"github.com/Bl4cky99/mocker/internal/state"
+)
+
+type fakeStatefulServer struct {
+	fakeServer
+	restored *state.Snapshot
+	snap     state.Snapshot
+}
+
+func (f *fakeStatefulServer) Snapshot() state.Snapshot { return f.snap }
+
+func (f *fakeStatefulServer) Restore(s state.Snapshot) error {
+	f.restored = &s
+	return nil
+}
+
+var _ = Describe("cmdServer state persistence", func() {
+	It("restores the state on startup and saves it on shutdown", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "state.json")
+		Expect(state.Save(path, state.Snapshot{Scenarios: map[string]string{"orders": "created"}})).To(Succeed())
+
+		prevLoad := loadConfig
+		loadConfig = func(string) (*config.Config, error) {
+			return &config.Config{Auth: config.AuthConfig{Type: "none"}}, nil
+		}
+		defer func() { loadConfig = prevLoad }()
+
+		var cancel context.CancelFunc
+		prevNotify := notifyContext
+		notifyContext = func(ctx context.Context, _ ...os.Signal) (context.Context, context.CancelFunc) {
+			ctx, cancel = context.WithCancel(ctx)
+			return ctx, cancel
+		}
+		defer func() { notifyContext = prevNotify }()
+
+		fake := &fakeStatefulServer{snap: state.Snapshot{Sequences: map[string]int{"GET /jobs": 2}}}
+		prevNew := newHTTPServer
+		newHTTPServer = func(ctx context.Context, c *config.Config, opts ...httpx.Option) (httpServer, error) {
+			fake.cancel = cancel
+			return fake, nil
+		}
+		defer func() { newHTTPServer = prevNew }()
+
+		var code int
+		stdout := capture(&os.Stdout, func() {
+			code = cmdServer("v", "c", "d", []string{"--state", path})
+		})
+		Expect(code).To(Equal(0))
+		Expect(stdout).To(ContainSubstring("state saved"))
+		Expect(fake.restored).NotTo(BeNil())
+		Expect(fake.restored.Scenarios).To(HaveKeyWithValue("orders", "created"))
+
+		saved, err := state.Load(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(saved.Sequences).To(HaveKeyWithValue("GET /jobs", 2))
+		Expect(saved.Scenarios).To(BeEmpty())
+	})
+})
+
+var _ = Describe("cmdState", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "state.json")
+	})
+
+	run := func(args ...string) (int, string, string) {
+		var code int
+		var stdout string
+		stderr := capture(&os.Stderr, func() {
+			stdout = capture(&os.Stdout, func() {
+				code = cmdState(args)
+			})
+		})
+		return code, stdout, stderr
+	}
+
+	It("imports, exports and clears the state file", func() {
+		in := filepath.Join(filepath.Dir(path), "in.json")
+		Expect(os.WriteFile(in, []byte(`{"version":1,"savedAt":"2026-01-02T03:04:05Z","scenarios":{"orders":"created"}}`), 0o600)).To(Succeed())
+
+		code, _, _ := run("import", "--state", path, in)
+		Expect(code).To(Equal(0))
+
+		code, out, _ := run("export", "--state", path)
+		Expect(code).To(Equal(0))
+		Expect(out).To(MatchJSON(`{"version":1,"savedAt":"2026-01-02T03:04:05Z","scenarios":{"orders":"created"}}`))
+
+		code, _, _ = run("clear", "--state", path)
+		Expect(code).To(Equal(0))
+		Expect(path).NotTo(BeAnExistingFile())
+	})
+
+	It("takes the state file from the config", func() {
+		prevLoad := loadConfig
+		loadConfig = func(string) (*config.Config, error) {
+			return &config.Config{Server: config.ServerConfig{StateFile: path}}, nil
+		}
+		defer func() { loadConfig = prevLoad }()
+
+		Expect(state.Save(path, state.Snapshot{Scenarios: map[string]string{"a": "b"}})).To(Succeed())
+		code, out, _ := run("export", "-c", "cfg.yaml")
+		Expect(code).To(Equal(0))
+		Expect(out).To(ContainSubstring(`"a": "b"`))
+	})
+
+	It("rejects invalid snapshots on import", func() {
+		in := filepath.Join(filepath.Dir(path), "in.json")
+		Expect(os.WriteFile(in, []byte(`{"version":99}`), 0o600)).To(Succeed())
+		code, _, stderr := run("import", "--state", path, in)
+		Expect(code).To(Equal(1))
+		Expect(stderr).To(ContainSubstring("unsupported state version"))
+		Expect(path).NotTo(BeAnExistingFile())
+	})
+
+	It("exits 2 on misuse", func() {
+		code, _, _ := run()
+		Expect(code).To(Equal(2))
+		code, _, stderr := run("export")
+		Expect(code).To(Equal(2))
+		Expect(stderr).To(ContainSubstring("no state file"))
+		code, _, _ = run("rewind", "--state", path)
+		Expect(code).To(Equal(2))
+	})
+})
@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/Bl4cky99/mocker/internal/errx"
+	"gopkg.in/yaml.v3"
+)
+
+// AuthProviders lists the auth types backed by a provider block under auth.
+var AuthProviders = []string{"token", "basic", "jwt"}
+
+type plainEndpointAuth EndpointAuth
+
+func (a *EndpointAuth) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		*a = EndpointAuth{Type: n.Value}
+		return nil
+	}
+	var p plainEndpointAuth
+	if err := n.Decode(&p); err != nil {
+		return err
+	}
+	*a = EndpointAuth(p)
+	return nil
+}
+
+func (a *EndpointAuth) UnmarshalJSON(b []byte) error {
+	if t := bytes.TrimSpace(b); len(t) > 0 && t[0] == '"' {
+		var typ string
+		if err := json.Unmarshal(b, &typ); err != nil {
+			return err
+		}
+		*a = EndpointAuth{Type: typ}
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	var p plainEndpointAuth
+	if err := dec.Decode(&p); err != nil {
+		return err
+	}
+	*a = EndpointAuth(p)
+	return nil
+}
+
+// Configured reports whether the provider block for an auth type is set.
+func (a AuthConfig) Configured(typ string) bool {
+	switch typ {
+	case "token":
+		return a.Token != nil
+	case "basic":
+		return a.Basic != nil
+	case "jwt":
+		return a.JWT != nil
+	}
+	return false
+}
+
+// Includes reports whether auth type typ authenticates with provider,
+// either directly or as one of the combined providers of "any" and "all".
+func (a AuthConfig) Includes(typ, provider string) bool {
+	if typ == "any" || typ == "all" {
+		return slices.Contains(a.Providers, provider)
+	}
+	return typ == provider
+}
+
+// UsesAuth reports whether provider authenticates any request, through
+// auth.type or an endpoint auth override.
+func (c *Config) UsesAuth(provider string) bool {
+	if c.Auth.Includes(c.Auth.Type, provider) {
+		return true
+	}
+	return slices.ContainsFunc(c.Endpoints, func(ep Endpoint) bool {
+		return ep.Auth != nil && c.Auth.Includes(ep.Auth.Type, provider)
+	})
+}
+
+// AuthFor returns the auth type that protects an endpoint: its own
+// override, or the server's auth.type.
+func (c *Config) AuthFor(ep Endpoint) string {
+	if ep.Auth == nil || ep.Auth.Type == "" || ep.Auth.Type == "inherit" {
+		return c.Auth.Type
+	}
+	return ep.Auth.Type
+}
+
+func (c *Config) validateEndpointAuth(e *errx.Collector, scope string, ep Endpoint) {
+	a := ep.Auth
+	switch {
+	case a.Type == "" || a.Type == "inherit" || a.Type == "none":
+	case slices.Contains(AuthProviders, a.Type):
+		e.If(!c.Auth.Configured(a.Type), ErrAuthConfig, "%s.auth.type %q requires auth.%s to be configured", scope, a.Type, a.Type)
+	case a.Type == "any" || a.Type == "all":
+		e.If(len(c.Auth.Providers) == 0, ErrAuthConfig, "%s.auth.type %q requires auth.providers", scope, a.Type)
+	default:
+		e.Wrapf(ErrAuthConfig, "%s.auth.type %q invalid (use inherit|none|token|basic|jwt|any|all)", scope, a.Type)
+		return
+	}
+
+	typ := c.AuthFor(ep)
+	if len(a.Tokens) > 0 {
+		if !c.Auth.Includes(typ, "token") {
+			e.Wrapf(ErrAuthConfig, "%s.auth.tokens requires token auth, endpoint uses %q", scope, typ)
+		} else if c.Auth.Token != nil {
+			for i, t := range a.Tokens {
+				e.If(!slices.Contains(c.Auth.Token.Tokens, t), ErrAuthConfig, "%s.auth.tokens[%d] is not in auth.token.tokens", scope, i)
+			}
+		}
+	}
+	if len(a.Users) > 0 {
+		switch {
+		case c.Auth.Includes(typ, "jwt"):
+			// any subject may be listed
+		case c.Auth.Includes(typ, "basic"):
+			if c.Auth.Basic != nil {
+				for i, u := range a.Users {
+					known := slices.ContainsFunc(c.Auth.Basic.Users, func(b BasicUser) bool { return b.Username == u })
+					e.If(!known, ErrAuthConfig, "%s.auth.users[%d] %q is not in auth.basic.users", scope, i, u)
+				}
+			}
+		default:
+			e.Wrapf(ErrAuthConfig, "%s.auth.users requires basic or jwt auth, endpoint uses %q", scope, typ)
+		}
+	}
+}
+
+func (c *Config) validateRequires(e *errx.Collector, scope string, ep Endpoint) {
+	r := ep.Requires
+	e.If(len(r.Scopes) == 0 && len(r.Roles) == 0, ErrAuthConfig, "%s.requires needs scopes or roles", scope)
+	e.If(c.AuthFor(ep) == "none", ErrAuthConfig, "%s.requires needs auth, endpoint uses \"none\"", scope)
+}
+
+func (c *Config) validateAuth(e *errx.Collector) {
+	switch c.Auth.Type {
+	case "none":
+	case "token", "basic", "jwt":
+		e.If(!c.Auth.Configured(c.Auth.Type), ErrAuthConfig, "auth.type=%s but %s config missing", c.Auth.Type, c.Auth.Type)
+	case "any", "all":
+		e.If(len(c.Auth.Providers) == 0, ErrAuthConfig, "auth.type=%s requires auth.providers", c.Auth.Type)
+	default:
+		e.Wrapf(ErrAuthConfig, "auth.type %q invalid (use none|token|basic|jwt|any|all)", c.Auth.Type)
+	}
+
+	seen := map[string]bool{}
+	for i, typ := range c.Auth.Providers {
+		switch {
+		case !slices.Contains(AuthProviders, typ):
+			e.Wrapf(ErrAuthConfig, "auth.providers[%d] %q invalid (use token|basic|jwt)", i, typ)
+		case !c.Auth.Configured(typ):
+			e.Wrapf(ErrAuthConfig, "auth.providers[%d] %q requires auth.%s to be configured", i, typ, typ)
+		case seen[typ]:
+			e.Wrapf(ErrAuthConfig, "auth.providers[%d] duplicate %q", i, typ)
+		}
+		seen[typ] = true
+	}
+
+	// unused blocks are left alone, e.g. a jwt block kept around with type none
+	if t := c.Auth.Token; t != nil && c.UsesAuth("token") {
+		e.If(strings.TrimSpace(t.Header) == "", ErrAuthConfig, "auth.token.header must not be empty")
+		e.If(len(t.Tokens) == 0, ErrAuthConfig, "auth.token.tokens must not be empty")
+		for tok := range t.Grants {
+			e.If(!slices.Contains(t.Tokens, tok), ErrAuthConfig, "auth.token.grants has a token not in auth.token.tokens")
+		}
+	}
+	if b := c.Auth.Basic; b != nil && c.UsesAuth("basic") {
+		e.If(len(b.Users) == 0, ErrAuthConfig, "auth.basic.users must not be empty")
+		for i, u := range b.Users {
+			e.If(u.Username == "" || u.Password == "", ErrAuthConfig, "auth.basic.users[%d] requires username and password", i)
+		}
+	}
+	if c.Auth.JWT != nil && c.UsesAuth("jwt") {
+		validateJWT(e, "auth.jwt", c.Auth.JWT, c.OAuth != nil)
+	}
+}
+
+func validateJWT(e *errx.Collector, scope string, j *JWTAuthConfig, issuer bool) {
+	e.If(j.ClockSkew < 0, ErrAuthConfig, "%s.clockSkew must not be negative", scope)
+	e.If(j.Secret == "" && len(j.PublicKeyFiles) == 0 && j.JWKSFile == "" && !issuer, ErrAuthConfig,
+		"%s requires secret, publicKeyFiles or jwksFile", scope)
+}
@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package config
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+)
+
+var _ = Describe("EndpointAuth", func() {
+	It("decodes a plain string as the auth type", func() {
+		var eps []Endpoint
+		Expect(yaml.Unmarshal([]byte("- auth: none\n- auth: {type: basic, users: [alice]}\n"), &eps)).To(Succeed())
+		Expect(eps[0].Auth).To(Equal(&EndpointAuth{Type: "none"}))
+		Expect(eps[1].Auth).To(Equal(&EndpointAuth{Type: "basic", Users: []string{"alice"}}))
+
+		Expect(json.Unmarshal([]byte(`[{"auth":"jwt"},{"auth":{"tokens":["t"]}}]`), &eps)).To(Succeed())
+		Expect(eps[0].Auth).To(Equal(&EndpointAuth{Type: "jwt"}))
+		Expect(eps[1].Auth).To(Equal(&EndpointAuth{Tokens: []string{"t"}}))
+	})
+
+	It("resolves the effective auth type", func() {
+		cfg := Config{Auth: AuthConfig{Type: "token"}}
+		Expect(cfg.AuthFor(Endpoint{})).To(Equal("token"))
+		Expect(cfg.AuthFor(Endpoint{Auth: &EndpointAuth{Type: "inherit"}})).To(Equal("token"))
+		Expect(cfg.AuthFor(Endpoint{Auth: &EndpointAuth{Type: "none"}})).To(Equal("none"))
+	})
+
+	It("tells which providers an auth type authenticates with", func() {
+		a := AuthConfig{Type: "any", Providers: []string{"token", "basic"}}
+		Expect(a.Includes("any", "basic")).To(BeTrue())
+		Expect(a.Includes("all", "token")).To(BeTrue())
+		Expect(a.Includes("any", "jwt")).To(BeFalse())
+		Expect(a.Includes("jwt", "jwt")).To(BeTrue())
+		Expect(a.Includes("none", "token")).To(BeFalse())
+	})
+})
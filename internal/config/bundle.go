@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package config
+
+import (
+	"bytes"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Methods are the HTTP methods endpoints support.
+var Methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+
+// Bundle is a generated config plus the body and schema files it
+// references.
+type Bundle struct {
+	Config *Config
+	// file path -> content
+	Files map[string][]byte
+}
+
+// Write stores the files and the config at path, as YAML or JSON depending
+// on its extension.
+func (b *Bundle) Write(path string) error {
+	for _, f := range slices.Sorted(maps.Keys(b.Files)) {
+		if err := os.MkdirAll(filepath.Dir(f), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(f, b.Files[f], 0o644); err != nil {
+			return err
+		}
+	}
+	return b.Config.Save(path)
+}
+
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// FileName turns s into a file name by replacing runs of unsafe characters
+// with "_". It returns "" when nothing safe is left.
+func FileName(s string) string {
+	return strings.Trim(unsafeName.ReplaceAllString(s, "_"), "_")
+}
+
+// EscapeTemplate keeps generated bodies literal when mocker renders body
+// files as templates.
+func EscapeTemplate(b []byte) []byte {
+	return bytes.ReplaceAll(b, []byte("{{"), []byte(`{{"{{"}}`))
+}
@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bundle", func() {
+	It("writes its files next to the config", func() {
+		dir := GinkgoT().TempDir()
+		body := filepath.Join(dir, "bodies", "GET_users.json")
+		b := &Bundle{
+			Config: &Config{Endpoints: []Endpoint{{Method: "GET", Path: "/users", Responses: []ResponseVariant{{Status: 200, BodyFile: body}}}}},
+			Files:  map[string][]byte{body: EscapeTemplate([]byte(`{"t":"{{x}}"}`))},
+		}
+		Expect(b.Write(filepath.Join(dir, "mocker.yaml"))).To(Succeed())
+
+		raw, err := os.ReadFile(body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(raw)).To(Equal(`{"t":"{{"{{"}}x}}"}`))
+		cfg, err := Load(filepath.Join(dir, "mocker.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Endpoints[0].Responses[0].BodyFile).To(Equal(body))
+	})
+
+	It("derives file names from paths and ids", func() {
+		Expect(FileName("/users/{id}")).To(Equal("users_id"))
+		Expect(FileName("/")).To(BeEmpty())
+	})
+})
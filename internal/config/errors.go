@@ -12,4 +12,8 @@ var (
 	ErrAuthConfig     = errors.New("invalid auth config")
 	ErrEndpointConfig = errors.New("invalid endpoint config")
 	ErrSchemaRef      = errors.New("invalid schema reference")
+	ErrScenarioConfig = errors.New("invalid scenario config")
+	ErrResourceConfig = errors.New("invalid resource config")
+	ErrTemplate       = errors.New("invalid template")
+	ErrOAuthConfig    = errors.New("invalid oauth config")
 )
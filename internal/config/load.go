@@ -8,8 +8,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"mime"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/Bl4cky99/mocker/internal/errx"
@@ -55,6 +58,28 @@ func Load(path string) (*Config, error) {
 	return &cfg, nil
 }
 
+// Save writes the config to path as YAML, or as JSON for a .json
+// extension, creating missing parent directories.
+func (c *Config) Save(path string) error {
+	var (
+		b   []byte
+		err error
+	)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		b, err = json.MarshalIndent(c, "", "  ")
+	default:
+		b, err = yaml.Marshal(c)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
 func (c *Config) ApplyDefaults() {
 	if c.Server.Addr == "" {
 		c.Server.Addr = ":8080"
@@ -82,38 +107,129 @@ func (c *Config) ApplyDefaults() {
 		}
 	}
 
+	if c.Auth.Type == "" && len(c.Auth.Providers) > 0 {
+		c.Auth.Type = "any"
+	}
 	if c.Auth.Type == "" {
 		c.Auth.Type = "none"
 	}
+
+	if o := c.OAuth; o != nil {
+		if o.Prefix == "" {
+			o.Prefix = "/oauth"
+		}
+		if o.TokenTTL == 0 {
+			o.TokenTTL = 3600
+		}
+		if len(o.Users) == 0 && c.Auth.Basic != nil {
+			o.Users = c.Auth.Basic.Users
+		}
+		// the issuer's key is trusted without further jwt settings
+		usesJWT := c.Auth.Includes(c.Auth.Type, "jwt") || slices.ContainsFunc(c.Endpoints, func(ep Endpoint) bool {
+			return ep.Auth != nil && c.Auth.Includes(ep.Auth.Type, "jwt")
+		})
+		if usesJWT && c.Auth.JWT == nil {
+			c.Auth.JWT = &JWTAuthConfig{}
+		}
+	}
+
+	if j := c.Auth.JWT; j != nil && j.Header == "" {
+		j.Header = "Authorization"
+		if j.Prefix == "" {
+			j.Prefix = "Bearer "
+		}
+	}
+
+	for i := range c.Scenarios {
+		if c.Scenarios[i].Initial == "" {
+			c.Scenarios[i].Initial = "initial"
+		}
+	}
+
+	for i := range c.Resources {
+		if c.Resources[i].IDField == "" {
+			c.Resources[i].IDField = "id"
+		}
+		if c.Resources[i].IDStrategy == "" {
+			c.Resources[i].IDStrategy = "increment"
+		}
+	}
+
+	for i := range c.Endpoints {
+		if seq := c.Endpoints[i].Sequence; seq != nil && seq.Mode == "" {
+			seq.Mode = "stick"
+		}
+	}
 }
 
 func (c *Config) Validate() error {
 	e := errx.New()
 
-	switch c.Auth.Type {
-	case "none":
-	case "token":
-		if c.Auth.Token == nil {
-			e.Wrap(ErrAuthConfig, "auth.type=token but token config missing")
-		} else {
-			e.If(strings.TrimSpace(c.Auth.Token.Header) == "", ErrAuthConfig, "auth.token.header must not be empty")
-			e.If(len(c.Auth.Token.Tokens) == 0, ErrAuthConfig, "auth.token.tokens must not be empty")
-		}
-	case "basic":
-		if c.Auth.Basic == nil {
-			e.Wrap(ErrAuthConfig, "auth.type=basic but basic config missing")
-		} else {
-			e.If(len(c.Auth.Basic.Users) == 0, ErrAuthConfig, "auth.basic.users must not be empty")
-			for i, u := range c.Auth.Basic.Users {
-				e.If(u.Username == "" || u.Password == "", ErrAuthConfig, "auth.basic.users[%d] requires username and password", i)
+	c.validateAuth(e)
+	if c.OAuth != nil {
+		validateOAuth(e, c.OAuth, c.Server.BasePath)
+	}
+
+	e.If(!strings.HasPrefix(c.Server.BasePath, "/"), ErrServerConfig, "server.basePath must start with '/'")
+	e.If(c.Server.StateInterval < 0, ErrServerConfig, "server.stateInterval must not be negative")
+
+	if p := c.Server.FallbackProxy; p != nil {
+		u, err := url.Parse(p.Upstream)
+		e.If(err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "", ErrServerConfig,
+			"server.fallbackProxy.upstream %q must be an absolute http(s) URL", p.Upstream)
+		e.If(p.StripPrefix != "" && !strings.HasPrefix(p.StripPrefix, "/"), ErrServerConfig,
+			"server.fallbackProxy.stripPrefix must start with '/'")
+	}
+
+	if c.Server.OpenAPI != "" && !fileExists(c.Server.OpenAPI) {
+		e.Wrapf(ErrServerConfig, "server.openapi %q not found", c.Server.OpenAPI)
+	}
+
+	if c.Server.CORS != nil && c.Server.CORS.Enabled {
+		e.If(c.Server.CORS.MaxAge < 0, ErrServerConfig, "server.cors.maxAge must not be negative")
+		for i, o := range c.Server.CORS.AllowOrigins {
+			e.If(strings.TrimSpace(o) == "", ErrServerConfig, "server.cors.allowOrigins[%d] must not be empty", i)
+		}
+	}
+
+	e.If(len(c.Endpoints) == 0 && len(c.Resources) == 0 && c.Server.OpenAPI == "", ErrEndpointConfig,
+		"at least one endpoint, resource or server.openapi required")
+
+	routes := map[string]string{}
+	for i, res := range c.Resources {
+		scope := fmt.Sprintf("resources[%d]", i)
+		e.If(!strings.HasPrefix(res.Path, "/") || res.Path == "/", ErrResourceConfig, "%s.path must start with '/' and name a collection", scope)
+		e.If(strings.HasSuffix(res.Path, "/") || strings.ContainsAny(res.Path, "{}*"), ErrResourceConfig,
+			"%s.path %q must not end with '/' or contain parameters", scope, res.Path)
+		e.If(strings.TrimSpace(res.IDField) == "", ErrResourceConfig, "%s.idField must not be empty", scope)
+		e.If(res.IDStrategy != "increment" && res.IDStrategy != "uuid", ErrResourceConfig,
+			"%s.idStrategy %q invalid (use increment|uuid)", scope, res.IDStrategy)
+		if res.SeedFile != "" && !fileExists(res.SeedFile) {
+			e.Wrapf(ErrResourceConfig, "%s.seedFile %q not found", scope, res.SeedFile)
+		}
+		if res.SchemaFile != "" && !fileExists(res.SchemaFile) {
+			e.Wrapf(ErrSchemaRef, "%s.schemaFile %q not found", scope, res.SchemaFile)
+		}
+		for _, rt := range ResourceRoutes(res.Path) {
+			if prev, dup := routes[rt]; dup {
+				e.Wrapf(ErrResourceConfig, "%s: route %s already used by %s", scope, rt, prev)
+				break
 			}
+			routes[rt] = scope
 		}
-	default:
-		e.Wrapf(ErrAuthConfig, "auth.type %q invalid (use none|token|basic)", c.Auth.Type)
 	}
 
-	e.If(!strings.HasPrefix(c.Server.BasePath, "/"), ErrServerConfig, "server.basePath must start with '/'")
-	e.If(len(c.Endpoints) == 0, ErrEndpointConfig, "at least one endpoint required")
+	scenarios := map[string]Scenario{}
+	for i, sc := range c.Scenarios {
+		scope := fmt.Sprintf("scenarios[%d]", i)
+		e.If(strings.TrimSpace(sc.Name) == "", ErrScenarioConfig, "%s.name must not be empty", scope)
+		if _, dup := scenarios[sc.Name]; dup {
+			e.Wrapf(ErrScenarioConfig, "%s duplicate scenario %q", scope, sc.Name)
+		}
+		e.If(len(sc.States) > 0 && !slices.Contains(sc.States, sc.Initial), ErrScenarioConfig,
+			"%s.initial %q not in states", scope, sc.Initial)
+		scenarios[sc.Name] = sc
+	}
 
 	seen := map[string]struct{}{}
 	for i, ep := range c.Endpoints {
@@ -122,6 +238,11 @@ func (c *Config) Validate() error {
 		e.If(!isHTTPMethod(ep.Method), ErrEndpointConfig, "%s.method %q invalid", scope, ep.Method)
 		e.If(!strings.HasPrefix(ep.Path, "/"), ErrEndpointConfig, "%s.path must start with '/'", scope)
 		e.If(len(ep.Responses) == 0, ErrEndpointConfig, "%s must have at least one response variant", scope)
+		e.If(ep.MaxBodyBytes < 0, ErrEndpointConfig, "%s.maxBodyBytes must not be negative", scope)
+
+		if prev, ok := routes[RouteKey(ep.Method, ep.Path)]; ok {
+			e.Wrapf(ErrEndpointConfig, "%s %s %s conflicts with the routes of %s", scope, strings.ToUpper(ep.Method), ep.Path, prev)
+		}
 
 		if epHasNoWhen(ep) {
 			key := strings.ToUpper(ep.Method) + " " + ep.Path
@@ -131,6 +252,51 @@ func (c *Config) Validate() error {
 			seen[key] = struct{}{}
 		}
 
+		if ep.Auth != nil {
+			c.validateEndpointAuth(e, scope, ep)
+		}
+		if ep.Requires != nil {
+			c.validateRequires(e, scope, ep)
+		}
+
+		if ep.Sequence != nil {
+			e.If(ep.Sequence.Mode != "stick" && ep.Sequence.Mode != "cycle", ErrEndpointConfig,
+				"%s.sequence.mode %q invalid (use stick|cycle)", scope, ep.Sequence.Mode)
+		}
+
+		switch ep.Selection {
+		case "", "first":
+		case "random":
+			e.If(ep.Sequence != nil, ErrEndpointConfig, "%s: selection random cannot be combined with sequence", scope)
+		default:
+			e.Wrapf(ErrEndpointConfig, "%s.selection %q invalid (use first|random)", scope, ep.Selection)
+		}
+
+		if epUsesState(ep) {
+			name := c.ScenarioFor(ep)
+			sc, ok := scenarios[name]
+			switch {
+			case name == "":
+				e.Wrapf(ErrScenarioConfig, "%s uses when.state/setState but no scenario is set (declare one or set %s.scenario)", scope, scope)
+			case !ok:
+				e.Wrapf(ErrScenarioConfig, "%s.scenario %q not declared", scope, name)
+			case len(sc.States) > 0:
+				for j, rv := range ep.Responses {
+					rscope := fmt.Sprintf("%s.responses[%d]", scope, j)
+					e.If(rv.SetState != "" && !slices.Contains(sc.States, rv.SetState), ErrScenarioConfig,
+						"%s.setState %q not in states of scenario %q", rscope, rv.SetState, name)
+					if rv.When != nil && rv.When.State != nil && rv.When.State.Equals != nil {
+						st := rv.When.State.Text()
+						e.If(!slices.Contains(sc.States, st), ErrScenarioConfig,
+							"%s.when.state %q not in states of scenario %q", rscope, st, name)
+					}
+				}
+			}
+		} else if ep.Scenario != "" {
+			_, ok := scenarios[ep.Scenario]
+			e.If(!ok, ErrScenarioConfig, "%s.scenario %q not declared", scope, ep.Scenario)
+		}
+
 		if ep.Validate != nil {
 			if ep.Validate.ContentType != "" && !validContentType(ep.Validate.ContentType) {
 				e.Wrapf(ErrEndpointConfig, "%s.validate.contentType %q invalid", scope, ep.Validate.ContentType)
@@ -142,13 +308,36 @@ func (c *Config) Validate() error {
 
 		for j, rv := range ep.Responses {
 			rscope := fmt.Sprintf("%s.responses[%d]", scope, j)
-			e.If(rv.Status < 100 || rv.Status > 599, ErrEndpointConfig, "%s.status %d out of range", rscope, rv.Status)
-
-			both := (rv.Body != "" && rv.BodyFile != "") || (rv.Body == "" && rv.BodyFile == "")
-			e.If(both, ErrEndpointConfig, "%s: set exactly one of body or bodyFile", rscope)
+			e.If(rv.Weight < 0, ErrEndpointConfig, "%s.weight must not be negative", rscope)
+			e.If(rv.Engine != "" && rv.Engine != "text" && rv.Engine != "html" && rv.Engine != "none", ErrEndpointConfig,
+				"%s.engine %q invalid (use text|html|none)", rscope, rv.Engine)
+
+			if rv.Proxy {
+				e.If(c.Server.FallbackProxy == nil, ErrEndpointConfig, "%s.proxy requires server.fallbackProxy", rscope)
+				e.If(rv.Body != "" || rv.BodyFile != "" || rv.BodySchema != "", ErrEndpointConfig,
+					"%s: proxy variants cannot set body, bodyFile or bodySchema", rscope)
+				e.If(rv.Status != 0 && (rv.Status < 100 || rv.Status > 599), ErrEndpointConfig, "%s.status %d out of range", rscope, rv.Status)
+			} else {
+				e.If(rv.Status < 100 || rv.Status > 599, ErrEndpointConfig, "%s.status %d out of range", rscope, rv.Status)
+
+				sources := 0
+				for _, src := range []string{rv.Body, rv.BodyFile, rv.BodySchema} {
+					if src != "" {
+						sources++
+					}
+				}
+				e.If(sources != 1, ErrEndpointConfig, "%s: set exactly one of body, bodyFile or bodySchema", rscope)
+
+				if rv.BodyFile != "" && !fileExists(rv.BodyFile) {
+					e.Wrapf(ErrEndpointConfig, "%s.bodyFile %q not found", rscope, rv.BodyFile)
+				}
+				if rv.BodySchema != "" && !fileExists(rv.BodySchema) {
+					e.Wrapf(ErrSchemaRef, "%s.bodySchema %q not found", rscope, rv.BodySchema)
+				}
+			}
 
-			if rv.BodyFile != "" && !fileExists(rv.BodyFile) {
-				e.Wrapf(ErrEndpointConfig, "%s.bodyFile %q not found", rscope, rv.BodyFile)
+			if rv.When != nil {
+				validateWhen(e, rscope+".when", ep.Path, rv.When)
 			}
 		}
 	}
@@ -156,13 +345,49 @@ func (c *Config) Validate() error {
 	return e.Err()
 }
 
-func isHTTPMethod(s string) bool {
-	switch strings.ToUpper(s) {
-	case "GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS":
-		return true
-	default:
-		return false
+// OAuthGrantTypes lists the grant types the built-in issuer supports.
+var OAuthGrantTypes = []string{"authorization_code", "client_credentials", "password", "refresh_token"}
+
+func validateOAuth(e *errx.Collector, o *OAuthConfig, basePath string) {
+	e.If(!strings.HasPrefix(o.Prefix, "/") || o.Prefix == "/" || strings.HasSuffix(o.Prefix, "/"), ErrOAuthConfig,
+		"oauth.prefix %q must start with '/', not end with '/' and not be the root", o.Prefix)
+	e.If(o.Prefix == strings.TrimRight(basePath, "/") || o.Prefix == "/__mocker", ErrOAuthConfig,
+		"oauth.prefix %q collides with server.basePath or the admin endpoints", o.Prefix)
+	if o.Issuer != "" {
+		u, err := url.Parse(o.Issuer)
+		e.If(err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "", ErrOAuthConfig,
+			"oauth.issuer %q must be an absolute http(s) URL", o.Issuer)
+	}
+	e.If(o.TokenTTL < 0, ErrOAuthConfig, "oauth.tokenTTL must not be negative")
+	e.If(len(o.Clients) == 0, ErrOAuthConfig, "oauth.clients must not be empty")
+
+	ids := map[string]bool{}
+	for i, cl := range o.Clients {
+		scope := fmt.Sprintf("oauth.clients[%d]", i)
+		e.If(strings.TrimSpace(cl.ClientID) == "", ErrOAuthConfig, "%s.clientId must not be empty", scope)
+		e.If(ids[cl.ClientID], ErrOAuthConfig, "%s duplicate clientId %q", scope, cl.ClientID)
+		ids[cl.ClientID] = true
+		for j, gt := range cl.GrantTypes {
+			e.If(!slices.Contains(OAuthGrantTypes, gt), ErrOAuthConfig,
+				"%s.grantTypes[%d] %q invalid (use %s)", scope, j, gt, strings.Join(OAuthGrantTypes, "|"))
+		}
+		e.If(cl.ClientSecret == "" && slices.Contains(cl.GrantTypes, "client_credentials"), ErrOAuthConfig,
+			"%s: client_credentials requires a clientSecret", scope)
+		e.If(len(cl.RedirectURIs) == 0 && (len(cl.GrantTypes) == 0 || slices.Contains(cl.GrantTypes, "authorization_code")), ErrOAuthConfig,
+			"%s: authorization_code requires redirectUris (list grantTypes without it otherwise)", scope)
+		for j, uri := range cl.RedirectURIs {
+			u, err := url.Parse(uri)
+			e.If(err != nil || u.Scheme == "" || u.Fragment != "", ErrOAuthConfig,
+				"%s.redirectUris[%d] %q must be an absolute URI without fragment", scope, j, uri)
+		}
 	}
+	for i, u := range o.Users {
+		e.If(u.Username == "" || u.Password == "", ErrOAuthConfig, "oauth.users[%d] requires username and password", i)
+	}
+}
+
+func isHTTPMethod(s string) bool {
+	return slices.Contains(Methods, strings.ToUpper(s))
 }
 
 func validContentType(ct string) bool {
@@ -182,12 +407,103 @@ func fileExists(p string) bool {
 	return true
 }
 
+// ResourceRoutes lists the method and path pairs generated for a resource,
+// with path parameters normalised to {}.
+func ResourceRoutes(path string) []string {
+	item := path + "/{}"
+	return []string{
+		"GET " + path, "POST " + path,
+		"GET " + item, "PUT " + item, "PATCH " + item, "DELETE " + item,
+	}
+}
+
+var routeParamRe = regexp.MustCompile(`\{[^}]*\}`)
+
+// RouteKey identifies a route by method and path, ignoring parameter names.
+func RouteKey(method, path string) string {
+	return strings.ToUpper(method) + " " + routeParamRe.ReplaceAllString(path, "{}")
+}
+
+// ContentType returns the Content-Type a variant is served with: its own
+// header, else the server default, else "".
+func (c *Config) ContentType(rv ResponseVariant) string {
+	for _, headers := range []map[string]string{rv.Headers, c.Server.DefaultHeaders} {
+		for k, v := range headers {
+			if strings.EqualFold(k, "Content-Type") {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// ScenarioFor returns the scenario an endpoint's state conditions refer to:
+// its explicit scenario, or the only declared one.
+func (c *Config) ScenarioFor(ep Endpoint) string {
+	if ep.Scenario != "" {
+		return ep.Scenario
+	}
+	if len(c.Scenarios) == 1 {
+		return c.Scenarios[0].Name
+	}
+	return ""
+}
+
+func epUsesState(ep Endpoint) bool {
+	for _, r := range ep.Responses {
+		if r.SetState != "" || (r.When != nil && r.When.State != nil) {
+			return true
+		}
+	}
+	return false
+}
+
 func epHasNoWhen(ep Endpoint) bool {
 	for _, r := range ep.Responses {
-		if r.When != nil && (len(r.When.Query) > 0 || len(r.When.Header) > 0) {
+		if !r.When.IsEmpty() {
 			return false
 		}
 	}
 
 	return true
 }
+
+// Files returns every file the config references (body files and schemas),
+// deduplicated and in declaration order.
+func (c *Config) Files() []string {
+	var out []string
+	seen := map[string]struct{}{}
+	add := func(p string) {
+		if p == "" {
+			return
+		}
+		if _, ok := seen[p]; ok {
+			return
+		}
+		seen[p] = struct{}{}
+		out = append(out, p)
+	}
+
+	for _, ep := range c.Endpoints {
+		if ep.Validate != nil {
+			add(ep.Validate.SchemaFile)
+		}
+		for _, rv := range ep.Responses {
+			add(rv.BodyFile)
+			add(rv.BodySchema)
+		}
+	}
+	for _, res := range c.Resources {
+		add(res.SeedFile)
+		add(res.SchemaFile)
+	}
+	add(c.Server.OpenAPI)
+	if j := c.Auth.JWT; j != nil {
+		for _, f := range j.PublicKeyFiles {
+			add(f)
+		}
+		add(j.JWKSFile)
+	}
+
+	return out
+}
@@ -61,13 +61,13 @@ func cloneResponse(rv ResponseVariant) ResponseVariant {
 	if rv.When != nil {
 		wc := *rv.When
 		if rv.When.Query != nil {
-			wc.Query = map[string]string{}
+			wc.Query = map[string]Matcher{}
 			for k, v := range rv.When.Query {
 				wc.Query[k] = v
 			}
 		}
 		if rv.When.Header != nil {
-			wc.Header = map[string]string{}
+			wc.Header = map[string]Matcher{}
 			for k, v := range rv.When.Header {
 				wc.Header[k] = v
 			}
@@ -202,6 +202,39 @@ var _ = Describe("Config.ApplyDefaults", func() {
 		Expect(cfg.Server.CORS.AllowOrigins).NotTo(BeEmpty())
 		Expect(cfg.Auth.Type).To(Equal("none"))
 	})
+
+	It("defaults the scenario initial state", func() {
+		cfg := Config{Scenarios: []Scenario{{Name: "orders"}}}
+		cfg.ApplyDefaults()
+		Expect(cfg.Scenarios[0].Initial).To(Equal("initial"))
+	})
+
+	It("defaults the resource id field and strategy", func() {
+		cfg := Config{Resources: []Resource{{Path: "/users"}}}
+		cfg.ApplyDefaults()
+		Expect(cfg.Resources[0].IDField).To(Equal("id"))
+		Expect(cfg.Resources[0].IDStrategy).To(Equal("increment"))
+	})
+
+	It("defaults the sequence mode to stick", func() {
+		cfg := Config{Endpoints: []Endpoint{{Sequence: &SequenceSpec{}}}}
+		cfg.ApplyDefaults()
+		Expect(cfg.Endpoints[0].Sequence.Mode).To(Equal("stick"))
+	})
+
+	It("defaults the oauth issuer and lets jwt auth trust it", func() {
+		users := []BasicUser{{Username: "alice", Password: "pw"}}
+		cfg := Config{
+			Auth:  AuthConfig{Type: "jwt", Basic: &BasicAuthConfig{Users: users}},
+			OAuth: &OAuthConfig{},
+		}
+		cfg.ApplyDefaults()
+		Expect(cfg.OAuth.Prefix).To(Equal("/oauth"))
+		Expect(cfg.OAuth.TokenTTL).To(Equal(3600))
+		Expect(cfg.OAuth.Users).To(Equal(users))
+		Expect(cfg.Auth.JWT).NotTo(BeNil())
+		Expect(cfg.Auth.JWT.Prefix).To(Equal("Bearer "))
+	})
 })
 
 var _ = Describe("Config.Validate", func() {
@@ -229,7 +262,7 @@ var _ = Describe("Config.Validate", func() {
 				Responses: []ResponseVariant{{
 					Status:   200,
 					BodyFile: bodyFile,
-					When:     &WhenClause{Query: map[string]string{"foo": "bar"}},
+					When:     &WhenClause{Query: map[string]Matcher{"foo": Eq("bar")}},
 				}},
 			}},
 		}
@@ -239,6 +272,67 @@ var _ = Describe("Config.Validate", func() {
 		Expect(valid.Validate()).To(Succeed())
 	})
 
+	It("accepts templates using helpers and unparsed bodies with engine none", func() {
+		c := cloneConfig(valid)
+		c.Endpoints[0].Sequence = &SequenceSpec{Mode: "stick", Key: "{{ upper .Path.id }}"}
+		c.Endpoints[0].Responses = append(c.Endpoints[0].Responses,
+			ResponseVariant{Status: 200, Body: `{"id": "{{ uuid }}", "at": "{{ now "DateOnly" }}"}`},
+			ResponseVariant{Status: 200, Body: "{{ not a template", Engine: "none"},
+		)
+		Expect(c.Validate()).To(Succeed())
+	})
+
+	It("accepts jwt auth with a secret", func() {
+		c := cloneConfig(valid)
+		c.Auth = AuthConfig{Type: "jwt", JWT: &JWTAuthConfig{Secret: "s3cret", Algorithms: []string{"HS256"}}}
+		c.ApplyDefaults()
+		Expect(c.Auth.JWT.Header).To(Equal("Authorization"))
+		Expect(c.Auth.JWT.Prefix).To(Equal("Bearer "))
+		Expect(c.Validate()).To(Succeed())
+	})
+
+	It("combines several providers and defaults to any of them", func() {
+		c := cloneConfig(valid)
+		c.Auth = AuthConfig{
+			Providers: []string{"token", "jwt"},
+			Token:     &TokenAuthConfig{Header: "X-API-Key", Tokens: []string{"k1"}},
+		}
+		c.OAuth = &OAuthConfig{Clients: []OAuthClient{{ClientID: "svc", ClientSecret: "pw", GrantTypes: []string{"client_credentials"}}}}
+		c.ApplyDefaults()
+		Expect(c.Auth.Type).To(Equal("any"))
+		Expect(c.Auth.JWT).NotTo(BeNil(), "jwt among the providers trusts the issuer")
+		Expect(c.Validate()).To(Succeed())
+	})
+
+	It("accepts jwt auth without keys when the oauth issuer is enabled", func() {
+		c := cloneConfig(valid)
+		c.Auth = AuthConfig{Type: "jwt"}
+		c.OAuth = &OAuthConfig{Clients: []OAuthClient{{ClientID: "web", RedirectURIs: []string{"http://localhost:3000/cb"}}}}
+		c.ApplyDefaults()
+		Expect(c.Validate()).To(Succeed())
+	})
+
+	It("loads an unused partial auth block and validates it once an endpoint uses it", func() {
+		path := writeTemp("config.yaml", `auth:
+  type: none
+  jwt:
+    header: Authorization
+endpoints:
+  - method: GET
+    path: /ok
+    responses:
+      - status: 200
+        body: ok
+`)
+		cfg, err := Load(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.UsesAuth("jwt")).To(BeFalse())
+
+		cfg.Endpoints[0].Auth = &EndpointAuth{Type: "jwt"}
+		Expect(cfg.UsesAuth("jwt")).To(BeTrue())
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("secret, publicKeyFiles or jwksFile")))
+	})
+
 	DescribeTable("rejects invalid configs",
 		func(makeCfg func() Config, wantSubs []string) {
 			cfg := makeCfg()
@@ -252,6 +346,31 @@ var _ = Describe("Config.Validate", func() {
 			func() Config { c := cloneConfig(valid); c.Auth.Type = "oauth"; return c },
 			[]string{"auth.type"},
 		),
+		Entry("combined auth without valid providers",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Auth = AuthConfig{
+					Type:      "all",
+					Providers: []string{"token", "oauth", "basic", "token"},
+					Token:     &TokenAuthConfig{Header: "Authorization", Tokens: []string{"t1"}},
+				}
+				return c
+			},
+			[]string{
+				`auth.providers[1] "oauth" invalid`,
+				`auth.providers[2] "basic" requires auth.basic`,
+				`auth.providers[3] duplicate "token"`,
+			},
+		),
+		Entry("combined auth without providers",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Auth.Type = "any"
+				c.Endpoints[0].Auth = &EndpointAuth{Type: "all"}
+				return c
+			},
+			[]string{"auth.type=any requires auth.providers", `endpoints[0].auth.type "all" requires auth.providers`},
+		),
 		Entry("token header empty",
 			func() Config {
 				c := cloneConfig(valid)
@@ -280,10 +399,138 @@ var _ = Describe("Config.Validate", func() {
 			},
 			[]string{"auth.basic.users[0]"},
 		),
+		Entry("jwt config missing",
+			func() Config { c := cloneConfig(valid); c.Auth.Type = "jwt"; return c },
+			[]string{"auth.type=jwt"},
+		),
+		Entry("jwt without keys",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Auth.Type = "jwt"
+				c.Auth.JWT = &JWTAuthConfig{Header: "Authorization"}
+				return c
+			},
+			[]string{"auth.jwt", "secret, publicKeyFiles or jwksFile"},
+		),
+		Entry("jwt negative skew",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Auth.Type = "jwt"
+				c.Auth.JWT = &JWTAuthConfig{Header: "Authorization", Secret: "s", ClockSkew: -1}
+				return c
+			},
+			[]string{"auth.jwt.clockSkew"},
+		),
+		Entry("endpoint auth with unconfigured provider",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Endpoints[0].Auth = &EndpointAuth{Type: "basic"}
+				return c
+			},
+			[]string{`endpoints[0].auth.type "basic" requires auth.basic`},
+		),
+		Entry("invalid endpoint auth type",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Endpoints[0].Auth = &EndpointAuth{Type: "oauth"}
+				return c
+			},
+			[]string{"endpoints[0].auth.type", "inherit|none"},
+		),
+		Entry("endpoint allow lists that cannot match",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Auth = AuthConfig{
+					Type:  "token",
+					Token: &TokenAuthConfig{Header: "Authorization", Tokens: []string{"t1"}},
+					Basic: &BasicAuthConfig{Users: []BasicUser{{Username: "alice", Password: "a"}}},
+				}
+				c.Endpoints[0].Auth = &EndpointAuth{Tokens: []string{"t2"}, Users: []string{"alice"}}
+				c.Endpoints = append(c.Endpoints, Endpoint{
+					Method: "GET", Path: "/users", Auth: &EndpointAuth{Type: "basic", Users: []string{"mallory"}, Tokens: []string{"t1"}},
+					Responses: []ResponseVariant{{Status: 200, Body: "ok"}},
+				})
+				return c
+			},
+			[]string{
+				"endpoints[0].auth.tokens[0] is not in auth.token.tokens",
+				`endpoints[0].auth.users requires basic or jwt auth, endpoint uses "token"`,
+				`endpoints[1].auth.users[0] "mallory"`,
+				`endpoints[1].auth.tokens requires token auth`,
+			},
+		),
+		Entry("requirements that cannot be met",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Auth = AuthConfig{
+					Type: "token",
+					Token: &TokenAuthConfig{Header: "Authorization", Tokens: []string{"t1"}, Grants: map[string]Grants{
+						"t2": {Roles: []string{"admin"}},
+					}},
+				}
+				c.Endpoints[0].Requires = &Requirement{}
+				c.Endpoints = append(c.Endpoints, Endpoint{
+					Method: "GET", Path: "/users", Auth: &EndpointAuth{Type: "none"}, Requires: &Requirement{Roles: []string{"admin"}},
+					Responses: []ResponseVariant{{Status: 200, Body: "ok"}},
+				})
+				return c
+			},
+			[]string{
+				"auth.token.grants has a token not in auth.token.tokens",
+				"endpoints[0].requires needs scopes or roles",
+				`endpoints[1].requires needs auth, endpoint uses "none"`,
+			},
+		),
+		Entry("invalid oauth prefix and issuer",
+			func() Config {
+				c := cloneConfig(valid)
+				c.OAuth = &OAuthConfig{Prefix: "oauth/", Issuer: "id.example.com", Clients: []OAuthClient{{ClientID: "a"}}}
+				return c
+			},
+			[]string{"oauth.prefix", "oauth.issuer"},
+		),
+		Entry("oauth prefix colliding with base path",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Server.BasePath = "/oauth"
+				c.OAuth = &OAuthConfig{Prefix: "/oauth", Clients: []OAuthClient{{ClientID: "a"}}}
+				return c
+			},
+			[]string{"collides with server.basePath"},
+		),
+		Entry("oauth without clients",
+			func() Config { c := cloneConfig(valid); c.OAuth = &OAuthConfig{Prefix: "/oauth"}; return c },
+			[]string{"oauth.clients must not be empty"},
+		),
+		Entry("invalid oauth clients",
+			func() Config {
+				c := cloneConfig(valid)
+				c.OAuth = &OAuthConfig{Prefix: "/oauth", Clients: []OAuthClient{
+					{ClientID: "a", GrantTypes: []string{"implicit", "client_credentials"}},
+					{ClientID: "a", ClientSecret: "s", RedirectURIs: []string{"/relative"}},
+					{ClientID: "b", ClientSecret: "s"},
+				}}
+				return c
+			},
+			[]string{"oauth.clients[0].grantTypes[0]", "client_credentials requires a clientSecret", "duplicate clientId", "oauth.clients[1].redirectUris[0]",
+				"oauth.clients[2]: authorization_code requires redirectUris"},
+		),
+		Entry("oauth user without password",
+			func() Config {
+				c := cloneConfig(valid)
+				c.OAuth = &OAuthConfig{Prefix: "/oauth", Clients: []OAuthClient{{ClientID: "a"}}, Users: []BasicUser{{Username: "x"}}}
+				return c
+			},
+			[]string{"oauth.users[0]"},
+		),
 		Entry("base path missing leading slash",
 			func() Config { c := cloneConfig(valid); c.Server.BasePath = "api"; return c },
 			[]string{"server.basePath"},
 		),
+		Entry("negative state interval",
+			func() Config { c := cloneConfig(valid); c.Server.StateInterval = -1; return c },
+			[]string{"server.stateInterval"},
+		),
 		Entry("no endpoints",
 			func() Config { c := cloneConfig(valid); c.Endpoints = nil; return c },
 			[]string{"at least one endpoint"},
@@ -316,7 +563,7 @@ var _ = Describe("Config.Validate", func() {
 				c.Endpoints[0].Responses[0].BodyFile = ""
 				return c
 			},
-			[]string{"set exactly one of body or bodyFile"},
+			[]string{"set exactly one of body, bodyFile or bodySchema"},
 		),
 		Entry("body file does not exist",
 			func() Config {
@@ -326,6 +573,189 @@ var _ = Describe("Config.Validate", func() {
 			},
 			[]string{"bodyFile"},
 		),
+		Entry("body and bodySchema both set",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Endpoints[0].Responses[0].BodySchema = c.Endpoints[0].Validate.SchemaFile
+				return c
+			},
+			[]string{"set exactly one of body, bodyFile or bodySchema"},
+		),
+		Entry("body schema does not exist",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Endpoints[0].Responses[0].Body = ""
+				c.Endpoints[0].Responses[0].BodyFile = ""
+				c.Endpoints[0].Responses[0].BodySchema = filepath.Join(shared, "nope.json")
+				return c
+			},
+			[]string{"bodySchema"},
+		),
+		Entry("invalid template engine",
+			func() Config { c := cloneConfig(valid); c.Endpoints[0].Responses[0].Engine = "jinja"; return c },
+			[]string{`engine "jinja" invalid`},
+		),
+		Entry("invalid body path",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Endpoints[0].Responses[0].When = &WhenClause{Body: map[string]Matcher{"type": {Equals: "x"}}}
+				return c
+			},
+			[]string{"when.body", "must start with '$'"},
+		),
+		Entry("invalid body regex",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Endpoints[0].Responses[0].When = &WhenClause{Body: map[string]Matcher{"$.type": {Regex: "("}}}
+				return c
+			},
+			[]string{`when.body["$.type"].regex invalid`},
+		),
+		Entry("invalid query regex",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Endpoints[0].Responses[0].When = &WhenClause{Query: map[string]Matcher{"q": {Regex: "[a-"}}}
+				return c
+			},
+			[]string{`when.query["q"].regex invalid`},
+		),
+		Entry("invalid nested header regex",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Endpoints[0].Responses[0].When = &WhenClause{Header: map[string]Matcher{"X": {Not: &Matcher{Regex: "("}}}}
+				return c
+			},
+			[]string{`when.header["X"].not.regex invalid`},
+		),
+		Entry("unknown path parameter",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Endpoints[0].Responses[0].When = &WhenClause{Path: map[string]Matcher{"id": Eq("1")}}
+				return c
+			},
+			[]string{`when.path: "id" is not a parameter of "/ok"`},
+		),
+		Entry("exists false with value operators",
+			func() Config {
+				c := cloneConfig(valid)
+				no := false
+				c.Endpoints[0].Responses[0].When = &WhenClause{Query: map[string]Matcher{"q": {Exists: &no, Equals: "x"}}}
+				return c
+			},
+			[]string{"exists: false cannot be combined with value operators"},
+		),
+		Entry("invalid sequence mode",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Endpoints[0].Sequence = &SequenceSpec{Mode: "loop"}
+				return c
+			},
+			[]string{"sequence.mode"},
+		),
+		Entry("state condition without a scenario",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Endpoints[0].Responses[0].SetState = "created"
+				return c
+			},
+			[]string{"no scenario is set"},
+		),
+		Entry("undeclared endpoint scenario",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Scenarios = []Scenario{{Name: "orders", Initial: "empty"}}
+				c.Endpoints[0].Scenario = "carts"
+				return c
+			},
+			[]string{`scenario "carts" not declared`},
+		),
+		Entry("duplicate scenario",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Scenarios = []Scenario{{Name: "orders", Initial: "a"}, {Name: "orders", Initial: "a"}}
+				return c
+			},
+			[]string{`duplicate scenario "orders"`},
+		),
+		Entry("initial and transition outside the declared states",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Scenarios = []Scenario{{Name: "orders", Initial: "new", States: []string{"empty", "created"}}}
+				c.Endpoints[0].Responses[0].SetState = "deleted"
+				st := Eq("gone")
+				c.Endpoints[0].Responses[0].When = &WhenClause{State: &st}
+				return c
+			},
+			[]string{`initial "new" not in states`, `setState "deleted" not in states`, `when.state "gone" not in states`},
+		),
+		Entry("invalid selection",
+			func() Config { c := cloneConfig(valid); c.Endpoints[0].Selection = "roundrobin"; return c },
+			[]string{`selection "roundrobin" invalid`},
+		),
+		Entry("random selection with sequence",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Endpoints[0].Selection = "random"
+				c.Endpoints[0].Sequence = &SequenceSpec{Mode: "stick"}
+				return c
+			},
+			[]string{"cannot be combined with sequence"},
+		),
+		Entry("negative weight",
+			func() Config { c := cloneConfig(valid); c.Endpoints[0].Responses[0].Weight = -1; return c },
+			[]string{"weight must not be negative"},
+		),
+		Entry("invalid resource",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Resources = []Resource{{Path: "/users/{id}", IDField: "id", IDStrategy: "random", SeedFile: filepath.Join(shared, "nope.json")}}
+				return c
+			},
+			[]string{"must not end with '/' or contain parameters", `idStrategy "random" invalid`, "seedFile"},
+		),
+		Entry("endpoint colliding with a resource route",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Resources = []Resource{{Path: "/ok", IDField: "id", IDStrategy: "increment"}}
+				return c
+			},
+			[]string{"GET /ok conflicts with the routes of resources[0]"},
+		),
+		Entry("duplicate resource",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Resources = []Resource{
+					{Path: "/users", IDField: "id", IDStrategy: "increment"},
+					{Path: "/users", IDField: "id", IDStrategy: "uuid"},
+				}
+				return c
+			},
+			[]string{"resources[1]: route GET /users already used by resources[0]"},
+		),
+		Entry("invalid fallback proxy",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Server.FallbackProxy = &ProxyConfig{Upstream: "localhost:9000", StripPrefix: "v1"}
+				return c
+			},
+			[]string{"server.fallbackProxy.upstream", "stripPrefix must start with '/'"},
+		),
+		Entry("proxy variant without fallback proxy",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Endpoints[0].Responses[0].Proxy = true
+				return c
+			},
+			[]string{"proxy requires server.fallbackProxy", "proxy variants cannot set body, bodyFile or bodySchema"},
+		),
+		Entry("missing openapi document",
+			func() Config {
+				c := cloneConfig(valid)
+				c.Server.OpenAPI = filepath.Join(shared, "nope.yaml")
+				return c
+			},
+			[]string{"server.openapi"},
+		),
 		Entry("invalid content type",
 			func() Config {
 				c := cloneConfig(valid)
@@ -337,9 +767,47 @@ var _ = Describe("Config.Validate", func() {
 	)
 })
 
+var _ = Describe("Config.ScenarioFor", func() {
+	It("prefers the endpoint scenario and falls back to the only declared one", func() {
+		cfg := Config{Scenarios: []Scenario{{Name: "orders"}}}
+		Expect(cfg.ScenarioFor(Endpoint{})).To(Equal("orders"))
+		Expect(cfg.ScenarioFor(Endpoint{Scenario: "carts"})).To(Equal("carts"))
+
+		cfg.Scenarios = append(cfg.Scenarios, Scenario{Name: "carts"})
+		Expect(cfg.ScenarioFor(Endpoint{})).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Config.Files", func() {
+	It("lists referenced body and schema files once", func() {
+		cfg := Config{Endpoints: []Endpoint{
+			{
+				Validate:  &ValidateSpec{SchemaFile: "s.json"},
+				Responses: []ResponseVariant{{BodyFile: "a.json"}, {Body: "inline"}},
+			},
+			{Responses: []ResponseVariant{{BodyFile: "a.json"}, {BodyFile: "b.json"}, {BodySchema: "s.json"}}},
+		}}
+		cfg.Resources = []Resource{{SeedFile: "users.json", SchemaFile: "s.json"}}
+		Expect(cfg.Files()).To(Equal([]string{"s.json", "a.json", "b.json", "users.json"}))
+	})
+})
+
+var _ = Describe("PathParams", func() {
+	It("extracts chi parameter names including regex params and wildcards", func() {
+		Expect(PathParams("/users/{id}/orders/{oid:[0-9]+}")).To(Equal([]string{"id", "oid"}))
+		Expect(PathParams("/static/*")).To(Equal([]string{"*"}))
+		Expect(PathParams("/plain")).To(BeEmpty())
+	})
+})
+
 var _ = Describe("epHasNoWhen", func() {
 	It("returns false when at least one response has a when clause", func() {
-		ep := Endpoint{Responses: []ResponseVariant{{When: &WhenClause{Header: map[string]string{"x": "1"}}}}}
+		ep := Endpoint{Responses: []ResponseVariant{{When: &WhenClause{Header: map[string]Matcher{"x": Eq("1")}}}}}
+		Expect(epHasNoWhen(ep)).To(BeFalse())
+	})
+
+	It("returns false when a response only has body conditions", func() {
+		ep := Endpoint{Responses: []ResponseVariant{{When: &WhenClause{Body: map[string]Matcher{"$.type": {Equals: "refund"}}}}}}
 		Expect(epHasNoWhen(ep)).To(BeFalse())
 	})
 
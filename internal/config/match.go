@@ -0,0 +1,245 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Bl4cky99/mocker/internal/errx"
+	"github.com/Bl4cky99/mocker/internal/jsonpath"
+	"gopkg.in/yaml.v3"
+)
+
+// Matcher is a single condition in a when clause. A plain scalar in the
+// config is shorthand for {equals: <scalar>}; all operators set on one
+// matcher must hold.
+type Matcher struct {
+	Equals     any      `yaml:"equals,omitempty"     json:"equals,omitempty"`
+	Exists     *bool    `yaml:"exists,omitempty"     json:"exists,omitempty"`
+	Regex      string   `yaml:"regex,omitempty"      json:"regex,omitempty"`
+	OneOf      []any    `yaml:"oneOf,omitempty"      json:"oneOf,omitempty"`
+	Contains   string   `yaml:"contains,omitempty"   json:"contains,omitempty"`
+	Gt         *float64 `yaml:"gt,omitempty"         json:"gt,omitempty"`
+	Gte        *float64 `yaml:"gte,omitempty"        json:"gte,omitempty"`
+	Lt         *float64 `yaml:"lt,omitempty"         json:"lt,omitempty"`
+	Lte        *float64 `yaml:"lte,omitempty"        json:"lte,omitempty"`
+	IgnoreCase bool     `yaml:"ignoreCase,omitempty" json:"ignoreCase,omitempty"`
+	Not        *Matcher `yaml:"not,omitempty"        json:"not,omitempty"`
+
+	// literal scalar text from the config, so `page: 02` still compares as "02"
+	text string
+}
+
+type plainMatcher Matcher
+
+// decodedMatcher accepts present and absent as spellings of exists.
+type decodedMatcher struct {
+	plainMatcher `yaml:",inline"`
+	Present      *bool `yaml:"present" json:"present"`
+	Absent       *bool `yaml:"absent"  json:"absent"`
+}
+
+func (d decodedMatcher) matcher() (Matcher, error) {
+	m := Matcher(d.plainMatcher)
+	if d.Present != nil || d.Absent != nil {
+		if m.Exists != nil || (d.Present != nil && d.Absent != nil) {
+			return Matcher{}, errors.New("use only one of exists, present and absent")
+		}
+		exists := (d.Present != nil && *d.Present) || (d.Absent != nil && !*d.Absent)
+		m.Exists = &exists
+	}
+	return m, nil
+}
+
+var matcherKeys = map[string]struct{}{
+	"equals":     {},
+	"exists":     {},
+	"present":    {},
+	"absent":     {},
+	"regex":      {},
+	"oneOf":      {},
+	"contains":   {},
+	"gt":         {},
+	"gte":        {},
+	"lt":         {},
+	"lte":        {},
+	"ignoreCase": {},
+	"not":        {},
+}
+
+// Eq returns a plain equality matcher, the equivalent of a scalar in the config.
+func Eq(v string) Matcher {
+	return Matcher{Equals: v, text: v}
+}
+
+// Text returns the literal config text of an equality matcher, falling back
+// to the formatted Equals value.
+func (m Matcher) Text() string {
+	if m.text != "" {
+		return m.text
+	}
+	switch v := m.Equals.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func (m *Matcher) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		var v any
+		if err := n.Decode(&v); err != nil {
+			return err
+		}
+		*m = Matcher{Equals: v}
+		if n.Kind == yaml.ScalarNode && v != nil {
+			m.text = n.Value
+		}
+		return nil
+	}
+
+	for i := 0; i < len(n.Content); i += 2 {
+		if _, ok := matcherKeys[n.Content[i].Value]; !ok {
+			return fmt.Errorf("line %d: unknown matcher operator %q", n.Content[i].Line, n.Content[i].Value)
+		}
+	}
+
+	var d decodedMatcher
+	if err := n.Decode(&d); err != nil {
+		return err
+	}
+	out, err := d.matcher()
+	if err != nil {
+		return fmt.Errorf("line %d: %w", n.Line, err)
+	}
+	*m = out
+	return nil
+}
+
+func (m *Matcher) UnmarshalJSON(b []byte) error {
+	if t := bytes.TrimSpace(b); len(t) == 0 || t[0] != '{' {
+		var v any
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		*m = Matcher{Equals: v}
+		if str, ok := v.(string); ok {
+			m.text = str
+		}
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	var d decodedMatcher
+	if err := dec.Decode(&d); err != nil {
+		return err
+	}
+	out, err := d.matcher()
+	if err != nil {
+		return err
+	}
+	*m = out
+	return nil
+}
+
+func (m Matcher) MarshalYAML() (any, error) {
+	if m.isShorthand() {
+		return m.Equals, nil
+	}
+	return plainMatcher(m), nil
+}
+
+func (m Matcher) MarshalJSON() ([]byte, error) {
+	if m.isShorthand() {
+		return json.Marshal(m.Equals)
+	}
+	return json.Marshal(plainMatcher(m))
+}
+
+func (m Matcher) isShorthand() bool {
+	cp := m
+	cp.Equals, cp.text = nil, ""
+	if !reflect.DeepEqual(cp, Matcher{}) {
+		return false
+	}
+	switch m.Equals.(type) {
+	case map[string]any, []any:
+		return false
+	}
+	return true
+}
+
+// IsEmpty reports whether the clause has no conditions at all, i.e. the
+// variant acts as a fallback.
+func (w *WhenClause) IsEmpty() bool {
+	return w == nil || (len(w.Query) == 0 && len(w.Header) == 0 && len(w.Path) == 0 &&
+		len(w.Cookie) == 0 && len(w.Body) == 0 && w.State == nil)
+}
+
+var pathParamRe = regexp.MustCompile(`\{([^}:]+)(?::[^}]*)?\}`)
+
+// PathParams returns the names of the chi URL parameters declared in path.
+func PathParams(path string) []string {
+	var out []string
+	for _, m := range pathParamRe.FindAllStringSubmatch(path, -1) {
+		out = append(out, m[1])
+	}
+	if strings.HasSuffix(path, "*") {
+		out = append(out, "*")
+	}
+	return out
+}
+
+func validateWhen(e *errx.Collector, scope, path string, w *WhenClause) {
+	for _, k := range slices.Sorted(maps.Keys(w.Query)) {
+		validateMatcher(e, fmt.Sprintf("%s.query[%q]", scope, k), w.Query[k])
+	}
+	for _, k := range slices.Sorted(maps.Keys(w.Header)) {
+		validateMatcher(e, fmt.Sprintf("%s.header[%q]", scope, k), w.Header[k])
+	}
+	params := PathParams(path)
+	for _, k := range slices.Sorted(maps.Keys(w.Path)) {
+		e.If(!slices.Contains(params, k), ErrEndpointConfig, "%s.path: %q is not a parameter of %q", scope, k, path)
+		validateMatcher(e, fmt.Sprintf("%s.path[%q]", scope, k), w.Path[k])
+	}
+	for _, k := range slices.Sorted(maps.Keys(w.Cookie)) {
+		validateMatcher(e, fmt.Sprintf("%s.cookie[%q]", scope, k), w.Cookie[k])
+	}
+	if w.State != nil {
+		validateMatcher(e, scope+".state", *w.State)
+	}
+	for _, expr := range slices.Sorted(maps.Keys(w.Body)) {
+		m := w.Body[expr]
+		if _, err := jsonpath.Compile(expr); err != nil {
+			e.Wrapf(ErrEndpointConfig, "%s.body: %v", scope, err)
+		}
+		validateMatcher(e, fmt.Sprintf("%s.body[%q]", scope, expr), m)
+	}
+}
+
+func validateMatcher(e *errx.Collector, scope string, m Matcher) {
+	if m.Regex != "" {
+		if _, err := regexp.Compile(m.Regex); err != nil {
+			e.Wrapf(ErrEndpointConfig, "%s.regex invalid: %v", scope, err)
+		}
+	}
+	e.If(m.Exists != nil && !*m.Exists && (m.Equals != nil || m.Regex != "" || len(m.OneOf) > 0 || m.Contains != "" ||
+		m.Gt != nil || m.Gte != nil || m.Lt != nil || m.Lte != nil),
+		ErrEndpointConfig, "%s: exists: false cannot be combined with value operators", scope)
+	if m.Not != nil {
+		validateMatcher(e, scope+".not", *m.Not)
+	}
+}
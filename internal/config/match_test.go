@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package config
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v3"
+)
+
+var _ = Describe("Matcher", func() {
+	It("decodes yaml scalars as equality and maps as operators", func() {
+		var w WhenClause
+		src := `
+body:
+  "$.type": refund
+  "$.amount": { exists: true }
+  "/user/email": { regex: "@corp\\.test$" }
+`
+		Expect(yaml.Unmarshal([]byte(src), &w)).To(Succeed())
+		Expect(w.Body["$.type"].Equals).To(Equal("refund"))
+		Expect(*w.Body["$.amount"].Exists).To(BeTrue())
+		Expect(w.Body["/user/email"].Regex).To(Equal(`@corp\.test$`))
+	})
+
+	It("decodes query/header operator objects and keeps scalar text", func() {
+		var w WhenClause
+		src := `
+query:
+  page: 02
+  search: { regex: "^a", ignoreCase: true }
+  sort: { oneOf: [asc, desc] }
+  limit: { gte: 1, lte: 100 }
+header:
+  X-Debug: { present: true }
+  X-Skip: { absent: true }
+  Accept: { not: { contains: xml } }
+`
+		Expect(yaml.Unmarshal([]byte(src), &w)).To(Succeed())
+		Expect(w.Query["page"].Text()).To(Equal("02"))
+		Expect(w.Query["search"].IgnoreCase).To(BeTrue())
+		Expect(w.Query["sort"].OneOf).To(Equal([]any{"asc", "desc"}))
+		Expect(*w.Query["limit"].Lte).To(Equal(100.0))
+		Expect(*w.Header["X-Debug"].Exists).To(BeTrue(), "present is an alias of exists")
+		Expect(*w.Header["X-Skip"].Exists).To(BeFalse(), "absent is the negated alias")
+		Expect(w.Header["Accept"].Not.Contains).To(Equal("xml"))
+	})
+
+	It("rejects unknown yaml operators", func() {
+		var w WhenClause
+		err := yaml.Unmarshal([]byte("body:\n  \"$.a\": { bogus: 1 }\n"), &w)
+		Expect(err).To(MatchError(ContainSubstring("unknown matcher operator")))
+	})
+
+	It("rejects more than one presence operator", func() {
+		var w WhenClause
+		err := yaml.Unmarshal([]byte("header:\n  X-Debug: { present: true, exists: false }\n"), &w)
+		Expect(err).To(MatchError(ContainSubstring("only one of exists, present and absent")))
+		Expect(json.Unmarshal([]byte(`{"header":{"X":{"present":true,"absent":true}}}`), &w)).NotTo(Succeed())
+	})
+
+	It("decodes json scalars and operator objects", func() {
+		var w WhenClause
+		Expect(json.Unmarshal([]byte(`{"body":{"$.n":3,"$.s":{"regex":"^a"}}}`), &w)).To(Succeed())
+		Expect(w.Body["$.n"].Equals).To(BeEquivalentTo(3))
+		Expect(w.Body["$.s"].Regex).To(Equal("^a"))
+
+		Expect(json.Unmarshal([]byte(`{"body":{"$.s":{"nope":true}}}`), &w)).NotTo(Succeed())
+	})
+
+	It("marshals equality-only matchers back to scalars", func() {
+		out, err := yaml.Marshal(map[string]Matcher{"$.a": {Equals: "x"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(Equal("$.a: x\n"))
+
+		b, err := json.Marshal(Matcher{Regex: "^a"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal(`{"regex":"^a"}`))
+	})
+})
+
+var _ = Describe("WhenClause.IsEmpty", func() {
+	It("treats nil and condition-free clauses as empty", func() {
+		var w *WhenClause
+		Expect(w.IsEmpty()).To(BeTrue())
+		Expect((&WhenClause{}).IsEmpty()).To(BeTrue())
+		Expect((&WhenClause{Body: map[string]Matcher{"$.a": {Equals: 1}}}).IsEmpty()).To(BeFalse())
+	})
+})
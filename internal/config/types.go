@@ -6,6 +6,9 @@ package config
 type Config struct {
 	Server    ServerConfig `yaml:"server" json:"server"`
 	Auth      AuthConfig   `yaml:"auth" json:"auth"`
+	OAuth     *OAuthConfig `yaml:"oauth,omitempty" json:"oauth,omitempty"`
+	Scenarios []Scenario   `yaml:"scenarios,omitempty" json:"scenarios,omitempty"`
+	Resources []Resource   `yaml:"resources,omitempty" json:"resources,omitempty"`
 	Endpoints []Endpoint   `yaml:"endpoints" json:"endpoints"`
 }
 
@@ -14,43 +17,201 @@ type ServerConfig struct {
 	BasePath       string            `yaml:"basePath" json:"basePath"`
 	DefaultHeaders map[string]string `yaml:"defaultHeaders" json:"defaultHeaders"`
 	CORS           *CORSConfig       `yaml:"cors,omitempty" json:"cors,omitempty"`
+	FallbackProxy  *ProxyConfig      `yaml:"fallbackProxy,omitempty" json:"fallbackProxy,omitempty"`
+	// OpenAPI document whose operations are served unless an endpoint overrides them
+	OpenAPI string `yaml:"openapi,omitempty" json:"openapi,omitempty"`
+	// JSON file runtime state is restored from on startup and saved to
+	StateFile string `yaml:"stateFile,omitempty" json:"stateFile,omitempty"`
+	// seconds between periodic state snapshots, 0 means 30
+	StateInterval int `yaml:"stateInterval,omitempty" json:"stateInterval,omitempty"`
+}
+
+// ProxyConfig forwards requests to a real service: unmatched routes when
+// set as server.fallbackProxy, and variants with proxy: true.
+type ProxyConfig struct {
+	Upstream string `yaml:"upstream" json:"upstream"`
+	// headers set on the forwarded request
+	SetHeaders    map[string]string `yaml:"setHeaders,omitempty" json:"setHeaders,omitempty"`
+	RemoveHeaders []string          `yaml:"removeHeaders,omitempty" json:"removeHeaders,omitempty"`
+	// remove server.basePath from the path before forwarding
+	StripBasePath bool `yaml:"stripBasePath,omitempty" json:"stripBasePath,omitempty"`
+	// additional prefix removed from the path before forwarding
+	StripPrefix string `yaml:"stripPrefix,omitempty" json:"stripPrefix,omitempty"`
 }
 
 type CORSConfig struct {
-	Enabled      bool     `yaml:"enabled" json:"enabled"`
-	AllowOrigins []string `yaml:"allowOrigins" json:"allowOrigins"`
-	AllowMethods []string `yaml:"allowMethods" json:"allowMethods"`
-	AllowHeaders []string `yaml:"allowHeaders" json:"allowHeaders"`
+	Enabled          bool     `yaml:"enabled" json:"enabled"`
+	AllowOrigins     []string `yaml:"allowOrigins" json:"allowOrigins"`
+	AllowMethods     []string `yaml:"allowMethods" json:"allowMethods"`
+	AllowHeaders     []string `yaml:"allowHeaders" json:"allowHeaders"`
+	ExposeHeaders    []string `yaml:"exposeHeaders,omitempty" json:"exposeHeaders,omitempty"`
+	AllowCredentials bool     `yaml:"allowCredentials,omitempty" json:"allowCredentials,omitempty"`
+	// preflight cache duration in seconds, 0 omits Access-Control-Max-Age
+	MaxAge int `yaml:"maxAge,omitempty" json:"maxAge,omitempty"`
 }
 
 type AuthConfig struct {
-	// "none" | "token" | "basic"
+	// "none" | "token" | "basic" | "jwt", or "any" | "all" of Providers
 	Type  string           `yaml:"type"  json:"type"`
 	Token *TokenAuthConfig `yaml:"token,omitempty" json:"token,omitempty"`
 	Basic *BasicAuthConfig `yaml:"basic,omitempty" json:"basic,omitempty"`
+	JWT   *JWTAuthConfig   `yaml:"jwt,omitempty" json:"jwt,omitempty"`
+	// provider types combined by "any" (default when set) or "all"
+	Providers []string `yaml:"providers,omitempty" json:"providers,omitempty"`
+	// body of 403 responses to endpoints whose requires is not met,
+	// default "forbidden"
+	ForbiddenBody string `yaml:"forbiddenBody,omitempty" json:"forbiddenBody,omitempty"`
 }
 
 type TokenAuthConfig struct {
 	Header string   `yaml:"header" json:"header"`
 	Prefix string   `yaml:"prefix" json:"prefix"`
 	Tokens []string `yaml:"tokens" json:"tokens"`
+	// roles and scopes per token
+	Grants map[string]Grants `yaml:"grants,omitempty" json:"grants,omitempty"`
+}
+
+// Grants are the roles and scopes a token or user is authorized with.
+type Grants struct {
+	Roles  []string `yaml:"roles,omitempty" json:"roles,omitempty"`
+	Scopes []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+}
+
+// JWTAuthConfig verifies bearer JWTs. At least one of Secret,
+// PublicKeyFiles or JWKSFile must be set unless the built-in oauth issuer
+// signs the tokens.
+type JWTAuthConfig struct {
+	// HMAC secret for HS256/384/512
+	Secret string `yaml:"secret,omitempty" json:"secret,omitempty"`
+	// PEM files with RSA or EC public keys or certificates
+	PublicKeyFiles []string `yaml:"publicKeyFiles,omitempty" json:"publicKeyFiles,omitempty"`
+	// local JSON Web Key Set file
+	JWKSFile string `yaml:"jwksFile,omitempty" json:"jwksFile,omitempty"`
+	// accepted "alg" values, all supported ones when empty
+	Algorithms []string `yaml:"algorithms,omitempty" json:"algorithms,omitempty"`
+	// required "iss" claim, unchecked when empty
+	Issuer string `yaml:"issuer,omitempty" json:"issuer,omitempty"`
+	// accepted "aud" values, unchecked when empty
+	Audience []string `yaml:"audience,omitempty" json:"audience,omitempty"`
+	// seconds of tolerance for exp and nbf
+	ClockSkew int `yaml:"clockSkew,omitempty" json:"clockSkew,omitempty"`
+	// header carrying the token, default "Authorization"
+	Header string `yaml:"header,omitempty" json:"header,omitempty"`
+	// prefix before the token, default "Bearer "
+	Prefix string `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+	// dotted path of the claim holding roles, default "roles"
+	RolesClaim string `yaml:"rolesClaim,omitempty" json:"rolesClaim,omitempty"`
 }
 
 type BasicAuthConfig struct {
 	Users []BasicUser `yaml:"users" json:"users"`
 }
 type BasicUser struct {
-	Username string `yaml:"username" json:"username"`
-	Password string `yaml:"password" json:"password"`
+	Username string   `yaml:"username" json:"username"`
+	Password string   `yaml:"password" json:"password"`
+	Roles    []string `yaml:"roles,omitempty" json:"roles,omitempty"`
+	Scopes   []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+	// extra claims in tokens and userinfo from the oauth issuer
+	Claims map[string]any `yaml:"claims,omitempty" json:"claims,omitempty"`
+}
+
+// OAuthConfig enables the built-in OAuth2/OIDC issuer. It signs tokens
+// with an RSA key generated at startup, which auth.type jwt trusts.
+type OAuthConfig struct {
+	// mount point outside basePath, default "/oauth"
+	Prefix string `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+	// "iss" of issued tokens, default the request's scheme and host plus prefix
+	Issuer string `yaml:"issuer,omitempty" json:"issuer,omitempty"`
+	// "aud" of access tokens, default the client id
+	Audience string `yaml:"audience,omitempty" json:"audience,omitempty"`
+	// access and id token lifetime in seconds, default 3600
+	TokenTTL int           `yaml:"tokenTTL,omitempty" json:"tokenTTL,omitempty"`
+	Clients  []OAuthClient `yaml:"clients" json:"clients"`
+	// resource owners for the password and authorization_code grants,
+	// default auth.basic.users
+	Users []BasicUser `yaml:"users,omitempty" json:"users,omitempty"`
+}
+
+type OAuthClient struct {
+	ClientID string `yaml:"clientId" json:"clientId"`
+	// empty for public clients, which must use PKCE
+	ClientSecret string `yaml:"clientSecret,omitempty" json:"clientSecret,omitempty"`
+	// allowed redirect URIs, required for the authorization_code grant
+	RedirectURIs []string `yaml:"redirectUris,omitempty" json:"redirectUris,omitempty"`
+	// scopes the client may request besides openid, any when empty
+	Scopes []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+	// allowed grant types, all when empty
+	GrantTypes []string `yaml:"grantTypes,omitempty" json:"grantTypes,omitempty"`
+}
+
+// Scenario is a named state machine shared by endpoints. Variants can
+// require a state via when.state and switch it via setState.
+type Scenario struct {
+	Name    string `yaml:"name" json:"name"`
+	Initial string `yaml:"initial" json:"initial"`
+	// optional whitelist of allowed states
+	States []string `yaml:"states,omitempty" json:"states,omitempty"`
+}
+
+// Resource is an in-memory collection served with generated list, get,
+// create, replace, patch and delete routes on Path and Path/{id}.
+type Resource struct {
+	Path string `yaml:"path" json:"path"`
+	// field holding the item id, default "id"
+	IDField string `yaml:"idField,omitempty" json:"idField,omitempty"`
+	// "increment" (default) or "uuid"
+	IDStrategy string `yaml:"idStrategy,omitempty" json:"idStrategy,omitempty"`
+	// optional JSON array with the initial items
+	SeedFile string `yaml:"seedFile,omitempty" json:"seedFile,omitempty"`
+	// optional JSON schema that created and updated items must satisfy
+	SchemaFile string `yaml:"schemaFile,omitempty" json:"schemaFile,omitempty"`
 }
 
 type Endpoint struct {
-	Method    string            `yaml:"method"    json:"method"`
-	Path      string            `yaml:"path"      json:"path"`
-	Validate  *ValidateSpec     `yaml:"validate,omitempty" json:"validate,omitempty"`
+	Method string `yaml:"method"    json:"method"`
+	Path   string `yaml:"path"      json:"path"`
+	// scenario consulted by when.state/setState, optional if exactly one is declared
+	Scenario string        `yaml:"scenario,omitempty" json:"scenario,omitempty"`
+	Auth     *EndpointAuth `yaml:"auth,omitempty" json:"auth,omitempty"`
+	Requires *Requirement  `yaml:"requires,omitempty" json:"requires,omitempty"`
+	Validate *ValidateSpec `yaml:"validate,omitempty" json:"validate,omitempty"`
+	// request bodies above this size are rejected with 413, 0 means 1 MiB
+	MaxBodyBytes int64         `yaml:"maxBodyBytes,omitempty" json:"maxBodyBytes,omitempty"`
+	Sequence     *SequenceSpec `yaml:"sequence,omitempty" json:"sequence,omitempty"`
+	// "first" (default) or "random", which picks among matching variants by weight
+	Selection string            `yaml:"selection,omitempty" json:"selection,omitempty"`
 	Responses []ResponseVariant `yaml:"responses" json:"responses"`
 }
 
+// EndpointAuth overrides the server auth for one endpoint. A plain string
+// is shorthand for {type: <string>}.
+type EndpointAuth struct {
+	// "inherit" (default), "none", or another provider configured under
+	// auth: "token" | "basic" | "jwt"
+	Type string `yaml:"type,omitempty" json:"type,omitempty"`
+	// accepted tokens, a subset of auth.token.tokens
+	Tokens []string `yaml:"tokens,omitempty" json:"tokens,omitempty"`
+	// accepted basic auth users or JWT subjects
+	Users []string `yaml:"users,omitempty" json:"users,omitempty"`
+}
+
+// Requirement lets only principals holding every listed scope and role
+// through; others get 403.
+type Requirement struct {
+	Scopes []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+	Roles  []string `yaml:"roles,omitempty" json:"roles,omitempty"`
+	// 403 body, default auth.forbiddenBody
+	Body string `yaml:"body,omitempty" json:"body,omitempty"`
+}
+
+// SequenceSpec serves the matching responses in order, one per call.
+type SequenceSpec struct {
+	// "stick" (default) repeats the last response, "cycle" starts over
+	Mode string `yaml:"mode,omitempty" json:"mode,omitempty"`
+	// optional template for separate counters, e.g. "{{ .Path.id }}"
+	Key string `yaml:"key,omitempty" json:"key,omitempty"`
+}
+
 type ValidateSpec struct {
 	ContentType string `yaml:"contentType" json:"contentType"`
 	SchemaFile  string `yaml:"schemaFile"  json:"schemaFile"`
@@ -62,10 +223,26 @@ type ResponseVariant struct {
 	Headers  map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
 	Body     string            `yaml:"body,omitempty"    json:"body,omitempty"`
 	BodyFile string            `yaml:"bodyFile,omitempty" json:"bodyFile,omitempty"`
-	DelayMs  int               `yaml:"delayMs,omitempty" json:"delayMs,omitempty"`
+	// JSON Schema file the body is generated from
+	BodySchema string `yaml:"bodySchema,omitempty" json:"bodySchema,omitempty"`
+	// "text", "html" or "none"; by default html for HTML content types, text otherwise
+	Engine   string `yaml:"engine,omitempty" json:"engine,omitempty"`
+	DelayMs  int    `yaml:"delayMs,omitempty" json:"delayMs,omitempty"`
+	SetState string `yaml:"setState,omitempty" json:"setState,omitempty"`
+	// relative weight for selection: random; 0 disables the variant once
+	// another one is weighted, without any weights all count as 1
+	Weight int `yaml:"weight,omitempty" json:"weight,omitempty"`
+	// forward to server.fallbackProxy; status and headers, if set, override the upstream's
+	Proxy bool `yaml:"proxy,omitempty" json:"proxy,omitempty"`
 }
 
 type WhenClause struct {
-	Query  map[string]string `yaml:"query,omitempty"  json:"query,omitempty"`
-	Header map[string]string `yaml:"header,omitempty" json:"header,omitempty"`
+	Query  map[string]Matcher `yaml:"query,omitempty"  json:"query,omitempty"`
+	Header map[string]Matcher `yaml:"header,omitempty" json:"header,omitempty"`
+	Path   map[string]Matcher `yaml:"path,omitempty"   json:"path,omitempty"`
+	Cookie map[string]Matcher `yaml:"cookie,omitempty" json:"cookie,omitempty"`
+	// keyed by JSONPath ($.type) or JSON Pointer (/type) into the parsed request body
+	Body map[string]Matcher `yaml:"body,omitempty" json:"body,omitempty"`
+	// current state of the endpoint's scenario
+	State *Matcher `yaml:"state,omitempty" json:"state,omitempty"`
 }
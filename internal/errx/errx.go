@@ -62,3 +62,27 @@ func (s *Scope) If(cond bool, sentinel error, format string, args ...any) {
 		s.Wrapf(sentinel, format, args...)
 	}
 }
+
+// List flattens joined and wrapped-joined errors into their leaf errors so
+// each collected problem can be reported on its own.
+func List(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		var out []error
+		for _, e := range j.Unwrap() {
+			out = append(out, List(e)...)
+		}
+		return out
+	}
+
+	if inner := errors.Unwrap(err); inner != nil {
+		if _, ok := inner.(interface{ Unwrap() []error }); ok {
+			return List(inner)
+		}
+	}
+
+	return []error{err}
+}
@@ -5,6 +5,7 @@ package errx
 
 import (
 	"errors"
+	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -47,6 +48,28 @@ var _ = Describe("Collector", func() {
 	})
 })
 
+var _ = Describe("List", func() {
+	It("returns nil for a nil error", func() {
+		Expect(List(nil)).To(BeNil())
+	})
+
+	It("flattens collected errors behind a wrapping error", func() {
+		c := New()
+		c.Wrapf(errFoo, "first")
+		c.Wrapf(errBar, "second")
+		wrapped := fmt.Errorf("invalid config %q: %w", "x.yaml", c.Err())
+
+		list := List(wrapped)
+		Expect(list).To(HaveLen(2))
+		Expect(errors.Is(list[0], errFoo)).To(BeTrue())
+		Expect(errors.Is(list[1], errBar)).To(BeTrue())
+	})
+
+	It("returns a plain error as a single entry", func() {
+		Expect(List(errFoo)).To(Equal([]error{errFoo}))
+	})
+})
+
 var _ = Describe("ErrContainsAll", func() {
 	It("returns false for nil error", func() {
 		Expect(ErrContainsAll(nil, "anything")).To(BeFalse())
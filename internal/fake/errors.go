@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package fake
+
+import "errors"
+
+var (
+	ErrRead   = errors.New("fake: read schema")
+	ErrDecode = errors.New("fake: decode schema")
+)
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+// Package fake generates random but reproducible mock data.
+package fake
+
+import (
+	"math/rand/v2"
+	"strings"
+	"sync"
+)
+
+// Generator produces fake values from a seeded source. It is safe for
+// concurrent use; each call draws from the shared sequence, so the same
+// calls in the same order yield the same values.
+type Generator struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func New(seed uint64) *Generator {
+	return &Generator{r: rand.New(rand.NewPCG(seed, seed))}
+}
+
+// NewRandom returns a generator with a random seed.
+func NewRandom() *Generator {
+	return New(rand.Uint64())
+}
+
+// do runs fn with exclusive access to the source so one value is drawn
+// as an uninterrupted run.
+func (g *Generator) do(fn func(r *rand.Rand)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	fn(g.r)
+}
+
+func pick[T any](r *rand.Rand, vs []T) T {
+	return vs[r.IntN(len(vs))]
+}
+
+// between returns an int in [lo, hi].
+func between(r *rand.Rand, lo, hi int) int {
+	if hi <= lo {
+		return lo
+	}
+	return lo + r.IntN(hi-lo+1)
+}
+
+func words(r *rand.Rand, n int) string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = pick(r, loremWords)
+	}
+	return strings.Join(out, " ")
+}
+
+var loremWords = []string{
+	"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit",
+	"sed", "do", "eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore",
+	"magna", "aliqua", "enim", "ad", "minim", "veniam", "quis", "nostrud",
+	"exercitation", "ullamco", "laboris", "nisi", "aliquip", "ex", "ea", "commodo",
+	"consequat", "duis", "aute", "irure", "in", "reprehenderit", "voluptate",
+	"velit", "esse", "cillum", "fugiat", "nulla", "pariatur", "excepteur", "sint",
+	"occaecat", "cupidatat", "non", "proident", "sunt", "culpa", "qui", "officia",
+	"deserunt", "mollit", "anim", "id", "est", "laborum",
+}
+
+var firstNames = []string{
+	"Ada", "Alan", "Alice", "Ben", "Carla", "Chen", "Dana", "Emil", "Fatima", "Grace",
+	"Hugo", "Ines", "Jonas", "Kai", "Lena", "Liam", "Maya", "Noah", "Olga", "Priya",
+	"Quinn", "Rosa", "Sam", "Tariq", "Uma", "Victor", "Wen", "Yara", "Zoe",
+}
+
+var lastNames = []string{
+	"Adams", "Berg", "Costa", "Dubois", "Eriksen", "Fischer", "Garcia", "Hansen",
+	"Ito", "Jensen", "Kowalski", "Lopez", "Meyer", "Nakamura", "Okafor", "Petrov",
+	"Quinn", "Rossi", "Schmidt", "Tanaka", "Uddin", "Varga", "Weber", "Young", "Zhang",
+}
+
+var domains = []string{"example.com", "example.org", "example.net"}
@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package fake
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// IntBetween returns an int in [lo, hi].
+func (g *Generator) IntBetween(lo, hi int) int {
+	var v int
+	g.do(func(r *rand.Rand) { v = between(r, lo, hi) })
+	return v
+}
+
+func (g *Generator) UUID() string {
+	var v string
+	g.do(func(r *rand.Rand) { v = uuid(r) })
+	return v
+}
+
+// ULID returns a ULID whose timestamp part is t and whose entropy comes
+// from the generator.
+func (g *Generator) ULID(t time.Time) string {
+	var b [16]byte
+	ms := uint64(t.UnixMilli())
+	for i := range 6 {
+		b[i] = byte(ms >> (40 - 8*i))
+	}
+	g.do(func(r *rand.Rand) {
+		for i := 6; i < len(b); i++ {
+			b[i] = byte(r.UintN(256))
+		}
+	})
+	return crockford(b)
+}
+
+func (g *Generator) FirstName() string { return g.pick(firstNames) }
+func (g *Generator) LastName() string  { return g.pick(lastNames) }
+func (g *Generator) City() string      { return g.pick(cities) }
+func (g *Generator) Country() string   { return g.pick(countries) }
+
+func (g *Generator) Name() string {
+	var v string
+	g.do(func(r *rand.Rand) { v = pick(r, firstNames) + " " + pick(r, lastNames) })
+	return v
+}
+
+func (g *Generator) Email() string {
+	var v string
+	g.do(func(r *rand.Rand) { v = email(r) })
+	return v
+}
+
+func (g *Generator) Street() string {
+	var v string
+	g.do(func(r *rand.Rand) { v = fmt.Sprintf("%d %s", 1+r.IntN(999), pick(r, streets)) })
+	return v
+}
+
+func (g *Generator) Zip() string {
+	var v string
+	g.do(func(r *rand.Rand) { v = fmt.Sprintf("%05d", r.IntN(100000)) })
+	return v
+}
+
+// Address returns a one-line postal address.
+func (g *Generator) Address() string {
+	var v string
+	g.do(func(r *rand.Rand) {
+		v = fmt.Sprintf("%d %s, %05d %s, %s", 1+r.IntN(999), pick(r, streets), r.IntN(100000), pick(r, cities), pick(r, countries))
+	})
+	return v
+}
+
+// Words returns n lorem ipsum words.
+func (g *Generator) Words(n int) string {
+	var v string
+	g.do(func(r *rand.Rand) { v = words(r, max(n, 0)) })
+	return v
+}
+
+func (g *Generator) Sentence() string {
+	var v string
+	g.do(func(r *rand.Rand) { v = sentence(r) })
+	return v
+}
+
+func (g *Generator) Paragraph() string {
+	var v string
+	g.do(func(r *rand.Rand) {
+		out := make([]string, between(r, 3, 6))
+		for i := range out {
+			out[i] = sentence(r)
+		}
+		v = strings.Join(out, " ")
+	})
+	return v
+}
+
+func (g *Generator) pick(vs []string) string {
+	var v string
+	g.do(func(r *rand.Rand) { v = pick(r, vs) })
+	return v
+}
+
+func sentence(r *rand.Rand) string {
+	s := words(r, between(r, 4, 12))
+	return strings.ToUpper(s[:1]) + s[1:] + "."
+}
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// crockford encodes 128 bits as 26 base32 characters, the ULID text form.
+func crockford(b [16]byte) string {
+	out := make([]byte, 26)
+	var acc uint64
+	bits := 2 // 130 bits of output for 128 of input, pad the front
+	i := 0
+	for _, c := range b {
+		acc = acc<<8 | uint64(c)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out[i] = crockfordAlphabet[(acc>>bits)&31]
+			i++
+		}
+	}
+	return string(out)
+}
+
+var streets = []string{
+	"Main Street", "Oak Avenue", "Maple Road", "Elm Street", "Station Road",
+	"Harbor Lane", "Park Avenue", "Mill Street", "Church Road", "River Drive",
+}
+
+var cities = []string{
+	"Springfield", "Riverton", "Lakeside", "Fairview", "Brookfield", "Georgetown",
+	"Kingsport", "Oakdale", "Westport", "Clayton",
+}
+
+var countries = []string{
+	"Germany", "France", "Spain", "Italy", "Netherlands", "Sweden", "Canada",
+	"United States", "Japan", "Brazil",
+}
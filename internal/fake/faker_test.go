@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package fake
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("faker", func() {
+	It("encodes the ULID timestamp in its first ten characters", func() {
+		g := New(1)
+		t := time.UnixMilli(1469918176385)
+		id := g.ULID(t)
+		Expect(id).To(HaveLen(26))
+		Expect(id[:10]).To(Equal("01ARYZ6S41"))
+		Expect(g.ULID(t)).NotTo(Equal(id))
+	})
+
+	It("returns the same values for the same seed", func() {
+		values := func(g *Generator) []string {
+			return []string{g.Name(), g.Email(), g.Address(), g.Sentence(), g.UUID(), g.Zip()}
+		}
+		Expect(values(New(4))).To(Equal(values(New(4))))
+	})
+
+	It("keeps values within bounds", func() {
+		g := New(2)
+		for range 100 {
+			Expect(g.IntBetween(-2, 2)).To(BeNumerically("~", 0, 2))
+		}
+		Expect(g.Words(3)).To(MatchRegexp(`^\w+ \w+ \w+$`))
+		Expect(g.Sentence()).To(MatchRegexp(`^[A-Z][a-z ]+\.$`))
+		Expect(g.Zip()).To(MatchRegexp(`^\d{5}$`))
+	})
+})
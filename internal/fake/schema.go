@@ -0,0 +1,432 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package fake
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxDepth = 8
+
+// SchemaFile generates a value for the JSON Schema stored in path.
+func (g *Generator) SchemaFile(path string) (any, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRead, err)
+	}
+	var s map[string]any
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrDecode, path, err)
+	}
+	return g.Schema(s), nil
+}
+
+// Resolver returns the schema a $ref points to, or nil when it is unknown.
+type Resolver func(ref string) map[string]any
+
+// Schema generates a value conforming to the JSON Schema s. Local $refs
+// are resolved against s; pattern and not are ignored.
+func (g *Generator) Schema(s map[string]any) any {
+	return g.SchemaWith(s, nil)
+}
+
+// SchemaWith is like Schema but resolves $refs with resolve, falling back
+// to local references when resolve is nil.
+func (g *Generator) SchemaWith(s map[string]any, resolve Resolver) any {
+	var out any
+	g.do(func(r *rand.Rand) {
+		sg := &schemaGen{r: r, root: s, ref: resolve}
+		out = sg.value(s, 0)
+	})
+	return out
+}
+
+type schemaGen struct {
+	r    *rand.Rand
+	root map[string]any
+	ref  Resolver
+}
+
+func (g *schemaGen) value(s map[string]any, depth int) any {
+	if s == nil || depth > maxDepth {
+		return nil
+	}
+	s = g.resolve(s)
+	for _, k := range []string{"const", "example"} {
+		if v, ok := s[k]; ok {
+			return v
+		}
+	}
+	for _, k := range []string{"enum", "examples"} {
+		if vs, ok := s[k].([]any); ok && len(vs) > 0 {
+			return pick(g.r, vs)
+		}
+	}
+	if all, ok := s["allOf"].([]any); ok {
+		base := without(s, "allOf")
+		for _, sub := range all {
+			base = merge(base, g.resolve(asSchema(sub)))
+		}
+		return g.value(base, depth+1)
+	}
+	for _, k := range []string{"oneOf", "anyOf"} {
+		if alts, ok := s[k].([]any); ok && len(alts) > 0 {
+			alt := g.resolve(asSchema(pick(g.r, alts)))
+			return g.value(merge(without(s, k), alt), depth+1)
+		}
+	}
+
+	switch g.schemaType(s) {
+	case "object":
+		return g.object(s, depth)
+	case "array":
+		return g.array(s, depth)
+	case "integer":
+		return g.integer(s)
+	case "number":
+		return g.number(s)
+	case "boolean":
+		return g.r.IntN(2) == 0
+	case "null":
+		return nil
+	}
+	return g.str(s)
+}
+
+// resolve follows references through the resolver or, without one, local
+// "#/..." pointers; unknown refs yield an empty schema.
+func (g *schemaGen) resolve(s map[string]any) map[string]any {
+	for range maxDepth {
+		ref, ok := s["$ref"].(string)
+		if !ok {
+			return s
+		}
+		if g.ref == nil {
+			s = g.pointer(ref)
+		} else if s = g.ref(ref); s == nil {
+			s = map[string]any{}
+		}
+	}
+	return s
+}
+
+func (g *schemaGen) pointer(ref string) map[string]any {
+	frag, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return map[string]any{}
+	}
+	var cur any = g.root
+	for _, tok := range strings.Split(strings.TrimPrefix(frag, "/"), "/") {
+		if tok == "" {
+			continue
+		}
+		tok = strings.NewReplacer("~1", "/", "~0", "~").Replace(tok)
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return map[string]any{}
+		}
+		cur = m[tok]
+	}
+	if m, ok := cur.(map[string]any); ok {
+		return m
+	}
+	return map[string]any{}
+}
+
+// schemaType returns the type of s, choosing among non-null entries of a
+// type list and inferring it from keywords when absent.
+func (g *schemaGen) schemaType(s map[string]any) string {
+	switch t := s["type"].(type) {
+	case string:
+		return t
+	case []any:
+		var types []string
+		for _, v := range t {
+			if str, ok := v.(string); ok && str != "null" {
+				types = append(types, str)
+			}
+		}
+		if len(types) > 0 {
+			return pick(g.r, types)
+		}
+		return "null"
+	}
+	switch {
+	case s["properties"] != nil || s["required"] != nil || s["additionalProperties"] != nil:
+		return "object"
+	case s["items"] != nil || s["prefixItems"] != nil:
+		return "array"
+	case s["minimum"] != nil || s["maximum"] != nil || s["multipleOf"] != nil:
+		return "number"
+	}
+	return "string"
+}
+
+// object fills every required property and roughly half of the optional
+// ones; writeOnly properties are left out.
+func (g *schemaGen) object(s map[string]any, depth int) map[string]any {
+	out := map[string]any{}
+	props, _ := s["properties"].(map[string]any)
+	required := map[string]bool{}
+	if req, ok := s["required"].([]any); ok {
+		for _, v := range req {
+			if name, ok := v.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(props)) {
+		ps := g.resolve(asSchema(props[name]))
+		if ps["writeOnly"] == true {
+			delete(required, name)
+			continue
+		}
+		if !required[name] && g.r.IntN(2) == 0 {
+			continue
+		}
+		out[name] = g.value(ps, depth+1)
+	}
+	for _, name := range slices.Sorted(maps.Keys(required)) {
+		if _, ok := out[name]; !ok {
+			out[name] = pick(g.r, loremWords)
+		}
+	}
+	return out
+}
+
+func (g *schemaGen) array(s map[string]any, depth int) []any {
+	lo, hasLo := intKeyword(s, "minItems")
+	hi, hasHi := intKeyword(s, "maxItems")
+	switch {
+	case !hasLo && !hasHi:
+		lo, hi = 1, 3
+	case !hasLo:
+		lo = min(1, hi)
+	case !hasHi:
+		hi = lo + 2
+	}
+	n := between(g.r, lo, hi)
+
+	prefix, _ := s["prefixItems"].([]any)
+	items := asSchema(s["items"])
+	if items == nil {
+		items = map[string]any{}
+	}
+	unique := s["uniqueItems"] == true
+	seen := map[string]bool{}
+
+	out := make([]any, 0, n)
+	for i := range n {
+		item := items
+		if i < len(prefix) {
+			item = asSchema(prefix[i])
+		}
+		var v any
+		for range 10 {
+			v = g.value(item, depth+1)
+			if !unique {
+				break
+			}
+			key, _ := json.Marshal(v)
+			if !seen[string(key)] {
+				seen[string(key)] = true
+				break
+			}
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+func (g *schemaGen) integer(s map[string]any) int64 {
+	lo, hi := bounds(s, 1)
+	ilo, ihi := int64(math.Ceil(lo)), int64(math.Floor(hi))
+	if m, ok := s["multipleOf"].(float64); ok && m >= 1 && m == math.Trunc(m) {
+		step := int64(m)
+		klo, khi := ceilDiv(ilo, step), floorDiv(ihi, step)
+		return int64(between(g.r, int(klo), int(khi))) * step
+	}
+	if ihi < ilo {
+		return ilo
+	}
+	return ilo + g.r.Int64N(ihi-ilo+1)
+}
+
+func (g *schemaGen) number(s map[string]any) float64 {
+	lo, hi := bounds(s, 0.01)
+	if m, ok := s["multipleOf"].(float64); ok && m > 0 {
+		klo, khi := math.Ceil(lo/m), math.Floor(hi/m)
+		return float64(between(g.r, int(klo), int(khi))) * m
+	}
+	v := math.Round((lo+g.r.Float64()*(hi-lo))*100) / 100
+	if v < lo || v > hi {
+		return lo
+	}
+	return v
+}
+
+// bounds returns the inclusive range allowed by s, moving exclusive limits
+// inward by step and spanning 1000 when a side is open.
+func bounds(s map[string]any, step float64) (float64, float64) {
+	lo, hasLo := s["minimum"].(float64)
+	hi, hasHi := s["maximum"].(float64)
+	if hasLo && s["exclusiveMinimum"] == true {
+		lo += step
+	}
+	if hasHi && s["exclusiveMaximum"] == true {
+		hi -= step
+	}
+	if ex, ok := s["exclusiveMinimum"].(float64); ok && (!hasLo || ex+step > lo) {
+		lo, hasLo = ex+step, true
+	}
+	if ex, ok := s["exclusiveMaximum"].(float64); ok && (!hasHi || ex-step < hi) {
+		hi, hasHi = ex-step, true
+	}
+	switch {
+	case !hasLo && !hasHi:
+		return 0, 1000
+	case !hasLo:
+		return math.Min(0, hi), hi
+	case !hasHi:
+		return lo, lo + 1000
+	}
+	return lo, hi
+}
+
+func (g *schemaGen) str(s map[string]any) string {
+	format, _ := s["format"].(string)
+	if v, ok := g.format(format); ok {
+		return v
+	}
+
+	lo, hasLo := intKeyword(s, "minLength")
+	hi, hasHi := intKeyword(s, "maxLength")
+	v := words(g.r, between(g.r, 1, 3))
+	for hasLo && len(v) < lo {
+		v += " " + pick(g.r, loremWords)
+	}
+	if hasHi && len(v) > hi {
+		v = strings.TrimRight(v[:hi], " ")
+		for len(v) < lo {
+			v += "x"
+		}
+	}
+	return v
+}
+
+var epoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func (g *schemaGen) format(format string) (string, bool) {
+	r := g.r
+	switch format {
+	case "email", "idn-email":
+		return email(r), true
+	case "uuid":
+		return uuid(r), true
+	case "date-time":
+		return randomTime(r).Format(time.RFC3339), true
+	case "date":
+		return randomTime(r).Format(time.DateOnly), true
+	case "time":
+		return randomTime(r).Format("15:04:05Z"), true
+	case "uri", "url", "iri":
+		return "https://" + pick(r, domains) + "/" + pick(r, loremWords), true
+	case "hostname", "idn-hostname":
+		return pick(r, loremWords) + "." + pick(r, domains), true
+	case "ipv4":
+		return fmt.Sprintf("10.%d.%d.%d", r.IntN(256), r.IntN(256), 1+r.IntN(254)), true
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x:%x", r.IntN(0x10000), r.IntN(0x10000)), true
+	case "byte":
+		return base64.StdEncoding.EncodeToString([]byte(words(r, 2))), true
+	case "int32", "int64":
+		return strconv.Itoa(r.IntN(1000)), true
+	}
+	return "", false
+}
+
+func email(r *rand.Rand) string {
+	first, last := pick(r, firstNames), pick(r, lastNames)
+	return strings.ToLower(first+"."+last) + "@" + pick(r, domains)
+}
+
+func uuid(r *rand.Rand) string {
+	var b [16]byte
+	for i := range b {
+		b[i] = byte(r.UintN(256))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// randomTime returns a second-precision time within five years of 2020.
+func randomTime(r *rand.Rand) time.Time {
+	return epoch.Add(time.Duration(r.Int64N(5*365*24*3600)) * time.Second)
+}
+
+func intKeyword(s map[string]any, key string) (int, bool) {
+	v, ok := s[key].(float64)
+	if !ok || v < 0 {
+		return 0, false
+	}
+	return int(v), true
+}
+
+func asSchema(v any) map[string]any {
+	s, _ := v.(map[string]any)
+	return s
+}
+
+func without(s map[string]any, key string) map[string]any {
+	out := maps.Clone(s)
+	delete(out, key)
+	return out
+}
+
+// merge combines two schemas for allOf/oneOf: properties and required are
+// unioned, other keywords of b override a.
+func merge(a, b map[string]any) map[string]any {
+	out := maps.Clone(a)
+	if out == nil {
+		out = map[string]any{}
+	}
+	for k, v := range b {
+		switch k {
+		case "properties":
+			props := maps.Clone(asSchema(out[k]))
+			if props == nil {
+				props = map[string]any{}
+			}
+			maps.Copy(props, asSchema(v))
+			out[k] = props
+		case "required":
+			req, _ := out[k].([]any)
+			extra, _ := v.([]any)
+			out[k] = append(slices.Clone(req), extra...)
+		default:
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func ceilDiv(a, b int64) int64 {
+	return int64(math.Ceil(float64(a) / float64(b)))
+}
+
+func floorDiv(a, b int64) int64 {
+	return int64(math.Floor(float64(a) / float64(b)))
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package fake
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/validate"
+)
+
+const userSchema = `{
+  "type": "object",
+  "required": ["id", "email", "createdAt", "role", "age", "tags"],
+  "properties": {
+    "id": {"type": "string", "format": "uuid"},
+    "email": {"type": "string", "format": "email"},
+    "createdAt": {"type": "string", "format": "date-time"},
+    "role": {"enum": ["admin", "member", "guest"]},
+    "age": {"type": "integer", "minimum": 18, "maximum": 99},
+    "score": {"type": "number", "exclusiveMinimum": 0, "maximum": 5},
+    "nickname": {"type": "string", "minLength": 3, "maxLength": 8},
+    "tags": {"type": "array", "items": {"type": "string"}, "minItems": 2, "maxItems": 4, "uniqueItems": true},
+    "address": {"$ref": "#/$defs/address"}
+  },
+  "$defs": {
+    "address": {
+      "type": "object",
+      "required": ["city", "zip"],
+      "properties": {"city": {"type": "string"}, "zip": {"type": "string", "minLength": 5, "maxLength": 5}}
+    }
+  }
+}`
+
+func decode(src string) map[string]any {
+	var s map[string]any
+	Expect(json.Unmarshal([]byte(src), &s)).To(Succeed())
+	return s
+}
+
+// roundTrip converts a generated value into its decoded JSON form.
+func roundTrip(v any) any {
+	b, err := json.Marshal(v)
+	Expect(err).NotTo(HaveOccurred())
+	var out any
+	Expect(json.Unmarshal(b, &out)).To(Succeed())
+	return out
+}
+
+var _ = Describe("Generator.Schema", func() {
+	It("generates values that validate against the schema", func() {
+		v, err := validate.CompileValue("user.json", decode(userSchema), validate.JSONSchemaValidatorOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		g := New(1)
+		for range 200 {
+			Expect(v.ValidateValue(roundTrip(g.Schema(decode(userSchema))))).To(Succeed())
+		}
+	})
+
+	It("is reproducible with the same seed", func() {
+		a, b := New(42), New(42)
+		for range 10 {
+			Expect(roundTrip(a.Schema(decode(userSchema)))).To(Equal(roundTrip(b.Schema(decode(userSchema)))))
+		}
+		Expect(roundTrip(New(1).Schema(decode(userSchema)))).NotTo(Equal(roundTrip(New(2).Schema(decode(userSchema)))))
+	})
+
+	It("always fills required properties and omits some optional ones", func() {
+		g := New(3)
+		optional := map[string]int{}
+		for range 50 {
+			obj := g.Schema(decode(userSchema)).(map[string]any)
+			Expect(obj).To(HaveKey("id"))
+			Expect(obj).To(HaveKey("tags"))
+			if _, ok := obj["nickname"]; ok {
+				optional["nickname"]++
+			}
+		}
+		Expect(optional["nickname"]).To(BeNumerically(">", 0))
+		Expect(optional["nickname"]).To(BeNumerically("<", 50))
+	})
+
+	It("produces well-formed formats", func() {
+		g := New(5)
+		Expect(g.Schema(map[string]any{"type": "string", "format": "uuid"})).To(
+			MatchRegexp(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`))
+		Expect(g.Schema(map[string]any{"type": "string", "format": "email"})).To(MatchRegexp(`^[a-z]+\.[a-z]+@example\.(com|org|net)$`))
+
+		ts := g.Schema(map[string]any{"type": "string", "format": "date-time"}).(string)
+		_, err := time.Parse(time.RFC3339, ts)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("respects bounds, multiples and array lengths", func() {
+		g := New(9)
+		for range 100 {
+			Expect(g.Schema(map[string]any{"type": "integer", "minimum": 3.0, "exclusiveMaximum": 6.0})).To(BeElementOf(int64(3), int64(4), int64(5)))
+			Expect(g.Schema(map[string]any{"type": "integer", "multipleOf": 5.0, "minimum": 1.0, "maximum": 20.0})).To(BeElementOf(int64(5), int64(10), int64(15), int64(20)))
+			Expect(g.Schema(map[string]any{"type": "array", "minItems": 1.0, "maxItems": 2.0})).To(SatisfyAny(HaveLen(1), HaveLen(2)))
+			Expect(g.Schema(map[string]any{"type": "array", "maxItems": 0.0})).To(BeEmpty())
+		}
+	})
+
+	It("merges allOf and picks one of oneOf", func() {
+		g := New(11)
+		v := g.Schema(decode(`{
+		  "allOf": [
+		    {"type": "object", "required": ["a"], "properties": {"a": {"const": 1}}},
+		    {"required": ["b"], "properties": {"b": {"oneOf": [{"const": "x"}, {"const": "y"}]}}}
+		  ]
+		}`))
+		Expect(v).To(HaveKeyWithValue("a", BeNumerically("==", 1)))
+		Expect(v).To(HaveKeyWithValue("b", BeElementOf("x", "y")))
+	})
+
+	It("resolves refs through a resolver and prefers examples", func() {
+		defs := map[string]map[string]any{
+			"#/components/schemas/Pet": decode(`{
+			  "type": "object",
+			  "required": ["name", "secret"],
+			  "properties": {"name": {"type": "string", "example": "Rex"}, "secret": {"type": "string", "writeOnly": true}}
+			}`),
+		}
+		v := New(13).SchemaWith(map[string]any{"$ref": "#/components/schemas/Pet"}, func(ref string) map[string]any { return defs[ref] })
+		Expect(v).To(Equal(map[string]any{"name": "Rex"}))
+		Expect(New(13).SchemaWith(map[string]any{"$ref": "#/missing"}, func(string) map[string]any { return nil })).To(BeAssignableToTypeOf(""))
+	})
+})
+
+var _ = Describe("Generator.SchemaFile", func() {
+	It("reads the schema from disk", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "user.json")
+		Expect(os.WriteFile(path, []byte(userSchema), 0o600)).To(Succeed())
+
+		v, err := New(1).SchemaFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v).To(HaveKey("email"))
+	})
+
+	It("reports missing and malformed files", func() {
+		_, err := New(1).SchemaFile(filepath.Join(GinkgoT().TempDir(), "nope.json"))
+		Expect(err).To(MatchError(ErrRead))
+
+		path := filepath.Join(GinkgoT().TempDir(), "bad.json")
+		Expect(os.WriteFile(path, []byte("{"), 0o600)).To(Succeed())
+		_, err = New(1).SchemaFile(path)
+		Expect(err).To(MatchError(ErrDecode))
+	})
+})
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Bl4cky99/mocker/internal/errx"
+	"github.com/Bl4cky99/mocker/internal/openapi"
+	"github.com/Bl4cky99/mocker/internal/resource"
+	"github.com/Bl4cky99/mocker/internal/state"
+	"github.com/go-chi/chi/v5"
+)
+
+// AdminPrefix is where mocker mounts its own control endpoints. They bypass
+// the mock auth provider and the configured basePath.
+const AdminPrefix = "/__mocker"
+
+func adminRoutes(s *Server) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/sequences", func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, http.StatusOK, map[string]any{"counters": s.seq.snapshot()})
+		})
+
+		// DELETE /__mocker/sequences[?endpoint=GET /jobs/{id}[&key=42]]
+		r.Delete("/sequences", func(w http.ResponseWriter, r *http.Request) {
+			endpoint, key := strings.TrimSpace(r.URL.Query().Get("endpoint")), ""
+			if endpoint != "" {
+				method, path, _ := strings.Cut(endpoint, " ")
+				endpoint = strings.ToUpper(method) + " " + path
+				key = r.URL.Query().Get("key")
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"reset": s.seq.reset(endpoint, key)})
+		})
+
+		r.Get("/resources", func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, http.StatusOK, map[string]any{"resources": resourceCounts(s.res.stores())})
+		})
+
+		// DELETE /__mocker/resources[?path=/users] restores the seeded items
+		r.Delete("/resources", func(w http.ResponseWriter, r *http.Request) {
+			stores := s.res.stores()
+			if p := r.URL.Query().Get("path"); p != "" {
+				st, ok := stores[p]
+				if !ok {
+					writeJSON(w, http.StatusNotFound, map[string]any{"error": "unknown resource " + p})
+					return
+				}
+				stores = map[string]*resource.Store{p: st}
+			}
+			for _, st := range stores {
+				st.Reset()
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"resources": resourceCounts(s.res.stores())})
+		})
+
+		r.Get("/state", func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, http.StatusOK, s.Snapshot())
+		})
+
+		r.Put("/state", func(w http.ResponseWriter, r *http.Request) {
+			snap, err := state.Decode(r.Body)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+				return
+			}
+			skipped := []string{}
+			for _, e := range errx.List(s.Restore(snap)) {
+				skipped = append(skipped, e.Error())
+			}
+			writeJSON(w, http.StatusOK, map[string]any{"skipped": skipped})
+		})
+
+		r.Get("/openapi.json", func(w http.ResponseWriter, _ *http.Request) {
+			doc, err := openapi.Export(s.cfg, s.templates())
+			if err != nil {
+				s.log.Warn("openapi export incomplete", "err", err)
+			}
+			writeJSON(w, http.StatusOK, doc)
+		})
+
+		r.Get("/scenarios", func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, http.StatusOK, map[string]any{"scenarios": s.scn.snapshot()})
+		})
+
+		// PUT /__mocker/scenarios/{name} with {"state": "..."} or ?state=...
+		r.Put("/scenarios/{name}", func(w http.ResponseWriter, r *http.Request) {
+			state := r.URL.Query().Get("state")
+			if state == "" {
+				var in struct {
+					State string `json:"state"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+					writeJSON(w, http.StatusBadRequest, map[string]any{"error": "expected {\"state\": \"...\"} or ?state="})
+					return
+				}
+				state = in.State
+			}
+			writeScenarioResult(w, s, s.scn.set(chi.URLParam(r, "name"), state))
+		})
+
+		r.Delete("/scenarios", func(w http.ResponseWriter, _ *http.Request) {
+			writeScenarioResult(w, s, s.scn.reset(""))
+		})
+
+		r.Delete("/scenarios/{name}", func(w http.ResponseWriter, r *http.Request) {
+			writeScenarioResult(w, s, s.scn.reset(chi.URLParam(r, "name")))
+		})
+	}
+}
+
+func writeScenarioResult(w http.ResponseWriter, s *Server, err error) {
+	switch {
+	case errors.Is(err, ErrUnknownScenario):
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": err.Error()})
+	case err != nil:
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+	default:
+		writeJSON(w, http.StatusOK, map[string]any{"scenarios": s.scn.snapshot()})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+)
+
+// corsMW writes Access-Control-* headers for allowed origins and answers
+// preflight requests with 204 before any auth middleware runs.
+func corsMW(c *config.CORSConfig) func(http.Handler) http.Handler {
+	if c == nil || !c.Enabled {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	allowMethods := strings.Join(c.AllowMethods, ", ")
+	allowHeaders := strings.Join(c.AllowHeaders, ", ")
+	exposeHeaders := strings.Join(c.ExposeHeaders, ", ")
+	wildcard := false
+	for _, o := range c.AllowOrigins {
+		if strings.TrimSpace(o) == "*" {
+			wildcard = true
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Add("Vary", "Origin")
+			if preflight {
+				h.Add("Vary", "Access-Control-Request-Method")
+				h.Add("Vary", "Access-Control-Request-Headers")
+			}
+
+			if !originAllowed(origin, c.AllowOrigins) {
+				if preflight {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if wildcard && !c.AllowCredentials {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			if c.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				if exposeHeaders != "" {
+					h.Set("Access-Control-Expose-Headers", exposeHeaders)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if allowMethods != "" {
+				h.Set("Access-Control-Allow-Methods", allowMethods)
+			}
+			switch {
+			case allowHeaders != "":
+				h.Set("Access-Control-Allow-Headers", allowHeaders)
+			case r.Header.Get("Access-Control-Request-Headers") != "":
+				h.Set("Access-Control-Allow-Headers", r.Header.Get("Access-Control-Request-Headers"))
+			}
+			if c.MaxAge > 0 {
+				h.Set("Access-Control-Max-Age", strconv.Itoa(c.MaxAge))
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// originAllowed matches an Origin header against the configured patterns.
+// Patterns with a scheme ("https://*.example.com") are compared against the
+// full origin, bare patterns ("localhost", "*.example.com") against its host.
+func originAllowed(origin string, patterns []string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	origin = strings.ToLower(u.Scheme + "://" + u.Host)
+	host := strings.ToLower(u.Host)
+	hostname := strings.ToLower(u.Hostname())
+
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimSpace(p))
+		switch {
+		case p == "":
+			continue
+		case p == "*":
+			return true
+		case strings.Contains(p, "://"):
+			if wildcardMatch(strings.TrimRight(p, "/"), origin) {
+				return true
+			}
+		case strings.Contains(p, ":"):
+			if wildcardMatch(p, host) {
+				return true
+			}
+		default:
+			if wildcardMatch(p, hostname) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// wildcardMatch reports whether s matches pattern, where each '*' in pattern
+// matches any (possibly empty) sequence of characters.
+func wildcardMatch(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+
+	return strings.HasSuffix(s, last)
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+)
+
+var _ = Describe("corsMW middleware", func() {
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	preflight := func(origin string) *http.Request {
+		req := httptest.NewRequest(http.MethodOptions, "/users", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		req.Header.Set("Access-Control-Request-Headers", "X-Custom")
+		return req
+	}
+
+	It("is a no-op when cors is disabled", func() {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Origin", "http://localhost:3000")
+		corsMW(&config.CORSConfig{Enabled: false})(okHandler).ServeHTTP(rec, req)
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Access-Control-Allow-Origin")).To(BeEmpty())
+	})
+
+	It("answers preflights with 204 and the configured headers", func() {
+		c := &config.CORSConfig{
+			Enabled:      true,
+			AllowOrigins: []string{"https://*.example.com"},
+			AllowMethods: []string{"GET", "POST"},
+			AllowHeaders: []string{"Authorization"},
+			MaxAge:       600,
+		}
+		rec := httptest.NewRecorder()
+		corsMW(c)(okHandler).ServeHTTP(rec, preflight("https://app.example.com"))
+
+		Expect(rec.Code).To(Equal(http.StatusNoContent))
+		Expect(rec.Header().Get("Access-Control-Allow-Origin")).To(Equal("https://app.example.com"))
+		Expect(rec.Header().Get("Access-Control-Allow-Methods")).To(Equal("GET, POST"))
+		Expect(rec.Header().Get("Access-Control-Allow-Headers")).To(Equal("Authorization"))
+		Expect(rec.Header().Get("Access-Control-Max-Age")).To(Equal("600"))
+		Expect(rec.Header().Values("Vary")).To(ContainElement("Origin"))
+	})
+
+	It("echoes requested headers when allowHeaders is empty", func() {
+		c := &config.CORSConfig{Enabled: true, AllowOrigins: []string{"*"}, AllowHeaders: []string{}}
+		rec := httptest.NewRecorder()
+		corsMW(c)(okHandler).ServeHTTP(rec, preflight("http://anything.test"))
+
+		Expect(rec.Header().Get("Access-Control-Allow-Origin")).To(Equal("*"))
+		Expect(rec.Header().Get("Access-Control-Allow-Headers")).To(Equal("X-Custom"))
+	})
+
+	It("echoes the origin and sets credentials for credentialed requests", func() {
+		c := &config.CORSConfig{
+			Enabled:          true,
+			AllowOrigins:     []string{"*"},
+			ExposeHeaders:    []string{"X-Request-ID"},
+			AllowCredentials: true,
+		}
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Origin", "http://localhost:5173")
+		corsMW(c)(okHandler).ServeHTTP(rec, req)
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Access-Control-Allow-Origin")).To(Equal("http://localhost:5173"))
+		Expect(rec.Header().Get("Access-Control-Allow-Credentials")).To(Equal("true"))
+		Expect(rec.Header().Get("Access-Control-Expose-Headers")).To(Equal("X-Request-ID"))
+	})
+
+	It("omits cors headers for disallowed origins", func() {
+		c := &config.CORSConfig{Enabled: true, AllowOrigins: []string{"localhost"}}
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Origin", "https://evil.test")
+		corsMW(c)(okHandler).ServeHTTP(rec, req)
+
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Access-Control-Allow-Origin")).To(BeEmpty())
+	})
+
+	It("answers preflights without running auth when wired through buildRouter", func() {
+		cfg := &config.Config{
+			Server: config.ServerConfig{
+				BasePath: "/",
+				CORS:     &config.CORSConfig{Enabled: true, AllowOrigins: []string{"localhost"}, AllowMethods: []string{"POST"}},
+			},
+			Endpoints: []config.Endpoint{{
+				Method:    "POST",
+				Path:      "/users",
+				Responses: []config.ResponseVariant{{Status: 201, Body: "{}"}},
+			}},
+		}
+		srv, err := New(context.Background(), cfg, WithLogger(discardLogger()), WithAuth(stubProvider{ok: false}, "token"))
+		Expect(err).NotTo(HaveOccurred())
+
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, preflight("http://localhost:3000"))
+		Expect(rec.Code).To(Equal(http.StatusNoContent))
+		Expect(rec.Header().Get("Access-Control-Allow-Origin")).To(Equal("http://localhost:3000"))
+
+		rec = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/users", nil)
+		req.Header.Set("Origin", "http://localhost:3000")
+		srv.Handler().ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+		Expect(rec.Header().Get("Access-Control-Allow-Origin")).To(Equal("http://localhost:3000"))
+	})
+})
+
+var _ = Describe("originAllowed", func() {
+	DescribeTable("matches origins against patterns",
+		func(origin string, patterns []string, want bool) {
+			Expect(originAllowed(origin, patterns)).To(Equal(want))
+		},
+		Entry("wildcard", "https://a.test", []string{"*"}, true),
+		Entry("exact origin", "https://app.test", []string{"https://app.test"}, true),
+		Entry("scheme mismatch", "http://app.test", []string{"https://app.test"}, false),
+		Entry("bare host ignores port", "http://localhost:3000", []string{"localhost"}, true),
+		Entry("host with port", "http://localhost:3000", []string{"localhost:4000"}, false),
+		Entry("subdomain pattern", "https://a.b.example.com", []string{"*.example.com"}, true),
+		Entry("subdomain pattern excludes apex", "https://example.com", []string{"*.example.com"}, false),
+		Entry("scheme subdomain pattern", "https://api.example.com", []string{"https://*.example.com"}, true),
+		Entry("case insensitive", "https://APP.test", []string{"app.test"}, true),
+		Entry("garbage origin", "null", []string{"localhost"}, false),
+	)
+})
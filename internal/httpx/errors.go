@@ -7,4 +7,8 @@ import "errors"
 
 var (
 	ErrBodyFileNotFound = errors.New("body file not found")
+	ErrUnknownScenario  = errors.New("unknown scenario")
+	ErrUnknownState     = errors.New("unknown scenario state")
+	ErrUnknownResource  = errors.New("unknown resource")
+	ErrTemplate         = errors.New("invalid body template")
 )
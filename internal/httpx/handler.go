@@ -4,6 +4,7 @@
 package httpx
 
 import (
+	"fmt"
 	"net/http"
 	"os"
 	"time"
@@ -14,17 +15,18 @@ import (
 
 func endpointHandler(s *Server, ep config.Endpoint) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		v := pickVariant(ep, r)
-
-		for k, val := range s.cfg.Server.DefaultHeaders {
-			if w.Header().Get(k) == "" {
-				w.Header().Set(k, val)
-			}
+		r, reqBody, err := bufferBody(w, r, ep.MaxBodyBytes)
+		if err != nil {
+			writeBodyError(w, err)
+			return
 		}
 
-		for k, val := range v.Headers {
-			w.Header().Set(k, val)
-		}
+		now := time.Now().UTC().Format(time.RFC3339)
+		data := render.BuildData(r, now, reqBody)
+
+		r = s.withScenarioState(ep, r)
+		v := s.selectVariant(ep, r, data)
+		s.applyState(ep, v)
 
 		if v.DelayMs > 0 {
 			d := time.Duration(v.DelayMs) * time.Millisecond
@@ -38,15 +40,28 @@ func endpointHandler(s *Server, ep config.Endpoint) http.HandlerFunc {
 			}
 		}
 
-		now := time.Now().UTC().Format(time.RFC3339)
-		data := render.BuildData(r, now)
+		// upstream headers are kept, only the variant's own headers override them
+		if v.Proxy && s.proxy != nil {
+			s.proxy.ServeHTTP(&overrideWriter{ResponseWriter: w, status: v.Status, headers: v.Headers}, r)
+			return
+		}
 
+		for k, val := range s.cfg.Server.DefaultHeaders {
+			if w.Header().Get(k) == "" {
+				w.Header().Set(k, val)
+			}
+		}
+
+		for k, val := range v.Headers {
+			w.Header().Set(k, val)
+		}
+
+		engine := render.EngineFor(v.Engine, s.cfg.ContentType(v))
 		var body []byte
-		var err error
 		switch {
 		case v.Body != "":
 			if s.renderer != nil {
-				body, err = s.renderer.RenderString(v.Body, data)
+				body, err = s.renderer.RenderString(engine, v.Body, data)
 				if err != nil {
 					s.log.Error("template render (inline) failed", "err", err)
 					http.Error(w, "template error", http.StatusInternalServerError)
@@ -57,7 +72,7 @@ func endpointHandler(s *Server, ep config.Endpoint) http.HandlerFunc {
 			}
 		case v.BodyFile != "":
 			if s.renderer != nil {
-				body, err = s.renderer.RenderFile(v.BodyFile, data)
+				body, err = s.renderer.RenderFile(engine, v.BodyFile, data)
 				if err != nil {
 					s.log.Error("template render (file) failed", "file", v.BodyFile, "err", err)
 					http.Error(w, "template error", http.StatusInternalServerError)
@@ -71,6 +86,13 @@ func endpointHandler(s *Server, ep config.Endpoint) http.HandlerFunc {
 					return
 				}
 			}
+		case v.BodySchema != "":
+			body, err = s.templates().RenderSchema(v.BodySchema)
+			if err != nil {
+				s.log.Error("generate body from schema failed", "file", v.BodySchema, "err", err)
+				http.Error(w, "template error", http.StatusInternalServerError)
+				return
+			}
 		}
 
 		if v.Status == 0 {
@@ -83,3 +105,29 @@ func endpointHandler(s *Server, ep config.Endpoint) http.HandlerFunc {
 		}
 	}
 }
+
+// compileTemplates parses every body template up front, so requests reuse
+// the renderer's cache and syntax errors fail the build instead of a request.
+// The inline cache is rebuilt, so edited bodies do not pile up on reload.
+func (s *Server) compileTemplates() error {
+	if s.renderer == nil {
+		return nil
+	}
+	s.renderer.ClearInline()
+	for i, ep := range s.cfg.Endpoints {
+		for j, rv := range ep.Responses {
+			engine := render.EngineFor(rv.Engine, s.cfg.ContentType(rv))
+			var err error
+			switch {
+			case rv.Body != "":
+				err = s.renderer.ParseString(engine, rv.Body)
+			case rv.BodyFile != "":
+				err = s.renderer.ParseFile(engine, rv.BodyFile)
+			}
+			if err != nil {
+				return fmt.Errorf("%w: endpoints[%d].responses[%d] (%s %s): %v", ErrTemplate, i, j, ep.Method, ep.Path, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/jsonpath"
+)
+
+// matchText evaluates a matcher against a single textual request value
+// (query, header, path param, cookie). A missing value is matched as "" so
+// plain `key: ""` conditions keep their historical meaning.
+func matchText(val string, present bool, m config.Matcher) bool {
+	return matchValues([]any{val}, present, true, m)
+}
+
+// matchValues evaluates m against the values a request attribute resolved
+// to. Presence operators look at present, value operators pass if any value
+// satisfies all of them. In text mode equality compares literal strings.
+func matchValues(found []any, present, text bool, m config.Matcher) bool {
+	if m.Not != nil && matchValues(found, present, text, *m.Not) {
+		return false
+	}
+	if m.Exists != nil && present != *m.Exists {
+		return false
+	}
+
+	if !hasValueOps(m) {
+		if m.Exists != nil || m.Not != nil {
+			return true
+		}
+		return present
+	}
+
+	for _, v := range found {
+		if valueMatches(v, text, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasValueOps(m config.Matcher) bool {
+	return m.Equals != nil || m.Regex != "" || len(m.OneOf) > 0 || m.Contains != "" ||
+		m.Gt != nil || m.Gte != nil || m.Lt != nil || m.Lte != nil
+}
+
+func valueMatches(v any, text bool, m config.Matcher) bool {
+	if m.Equals != nil {
+		want := m.Equals
+		if text {
+			want = m.Text()
+		}
+		if !equalValue(v, want, text, m.IgnoreCase) {
+			return false
+		}
+	}
+
+	if len(m.OneOf) > 0 {
+		hit := false
+		for _, want := range m.OneOf {
+			if equalValue(v, want, text, m.IgnoreCase) {
+				hit = true
+				break
+			}
+		}
+		if !hit {
+			return false
+		}
+	}
+
+	if m.Regex != "" {
+		pattern := m.Regex
+		if m.IgnoreCase {
+			pattern = "(?i)" + pattern
+		}
+		re, err := cachedRegexp(pattern)
+		if err != nil || !re.MatchString(scalarString(v)) {
+			return false
+		}
+	}
+
+	if m.Contains != "" && !containsValue(v, m.Contains, m.IgnoreCase) {
+		return false
+	}
+
+	if m.Gt != nil || m.Gte != nil || m.Lt != nil || m.Lte != nil {
+		n, ok := toFloat(v)
+		if !ok {
+			return false
+		}
+		if (m.Gt != nil && !(n > *m.Gt)) || (m.Gte != nil && !(n >= *m.Gte)) ||
+			(m.Lt != nil && !(n < *m.Lt)) || (m.Lte != nil && !(n <= *m.Lte)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalValue(got, want any, text, ignoreCase bool) bool {
+	if text {
+		return foldEqual(scalarString(got), scalarString(want), ignoreCase)
+	}
+	if gs, ok := got.(string); ok && ignoreCase {
+		ws, ok := want.(string)
+		return ok && strings.EqualFold(gs, ws)
+	}
+	return jsonEqual(got, want)
+}
+
+func containsValue(v any, sub string, ignoreCase bool) bool {
+	if arr, ok := v.([]any); ok {
+		for _, el := range arr {
+			if foldEqual(scalarString(el), sub, ignoreCase) {
+				return true
+			}
+		}
+		return false
+	}
+
+	s := scalarString(v)
+	if ignoreCase {
+		return strings.Contains(strings.ToLower(s), strings.ToLower(sub))
+	}
+	return strings.Contains(s, sub)
+}
+
+func foldEqual(a, b string, ignoreCase bool) bool {
+	if ignoreCase {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+var (
+	regexCache sync.Map
+	pathCache  sync.Map
+)
+
+func cachedRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexCache.Store(pattern, re)
+	return re, nil
+}
+
+func cachedPath(expr string) (*jsonpath.Path, error) {
+	if p, ok := pathCache.Load(expr); ok {
+		return p.(*jsonpath.Path), nil
+	}
+	p, err := jsonpath.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	pathCache.Store(expr, p)
+	return p, nil
+}
+
+// jsonEqual compares a decoded body value with a config value. Numbers are
+// compared numerically, everything else after a JSON round trip.
+func jsonEqual(got, want any) bool {
+	if gf, ok := toFloat(got); ok {
+		if _, isStr := got.(string); !isStr {
+			wf, ok := toFloat(want)
+			_, wantStr := want.(string)
+			return ok && !wantStr && gf == wf
+		}
+	}
+
+	gb, err := json.Marshal(got)
+	if err != nil {
+		return false
+	}
+	wb, err := json.Marshal(want)
+	if err != nil {
+		return false
+	}
+
+	var gv, wv any
+	if json.Unmarshal(gb, &gv) != nil || json.Unmarshal(wb, &wv) != nil {
+		return false
+	}
+	return reflect.DeepEqual(gv, wv)
+}
+
+// toFloat converts numbers and numeric strings to float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func scalarString(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	case bool:
+		return strconv.FormatBool(t)
+	case nil:
+		return ""
+	default:
+		b, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Sprint(t)
+		}
+		return string(b)
+	}
+}
@@ -6,15 +6,20 @@ package httpx
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"io"
 	"log/slog"
 	"mime"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Bl4cky99/mocker/internal/auth"
+	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/render"
 	"github.com/Bl4cky99/mocker/internal/validate"
 )
 
@@ -85,15 +90,10 @@ func requireAuth(p auth.Provider, mode string) func(http.Handler) http.Handler {
 
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			pr, ok, err := p.Authenticate(r)
-			if err != nil {
-				http.Error(w, "unauthorized", http.StatusUnauthorized)
-				return
-			}
-			if !ok {
-				if mode == "basic" {
-					w.Header().Set("WWW-Authenticate", `Basic realm="mocker"`)
+			if err != nil || !ok {
+				for _, c := range challenges(p, mode, err) {
+					w.Header().Add("WWW-Authenticate", c)
 				}
-
 				http.Error(w, "unauthorized", http.StatusUnauthorized)
 				return
 			}
@@ -104,19 +104,129 @@ func requireAuth(p auth.Provider, mode string) func(http.Handler) http.Handler {
 	}
 }
 
+// challenges returns the WWW-Authenticate challenges for a failed request.
+// Providers may name their own, all of them when several are combined;
+// otherwise they follow from mode.
+func challenges(p auth.Provider, mode string, err error) []string {
+	if c, ok := p.(auth.Challenger); ok {
+		return c.Challenges(err)
+	}
+	switch {
+	case mode == "jwt" && err != nil:
+		return []string{`Bearer realm="mocker", error="invalid_token"`}
+	case mode == "jwt":
+		return []string{`Bearer realm="mocker"`}
+	case mode == "basic" && err == nil:
+		return []string{`Basic realm="mocker"`}
+	}
+	return nil
+}
+
+// requireGrants answers 403 to principals lacking a required scope or role
+// and 401 when requireAuth put no principal in the context.
+func requireGrants(req config.Requirement, forbiddenBody string) func(http.Handler) http.Handler {
+	body := req.Body
+	if body == "" {
+		body = forbiddenBody
+	}
+	if body == "" {
+		body = "forbidden"
+	}
+	ctype := "text/plain; charset=utf-8"
+	if json.Valid([]byte(body)) {
+		ctype = "application/json"
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pr, ok := principalFrom(r.Context())
+			if !ok {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			if !pr.Satisfies(req.Scopes, req.Roles) {
+				w.Header().Set("Content-Type", ctype)
+				w.Header().Set("X-Content-Type-Options", "nosniff")
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = io.WriteString(w, body)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 func skipAuthForOPTIONS(mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
-		return mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		guarded := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if r.Method == http.MethodOptions {
 				next.ServeHTTP(w, r)
 				return
 			}
-			next.ServeHTTP(w, r)
-		}))
+			guarded.ServeHTTP(w, r)
+		})
 	}
 }
 
-func validateBody(wantCT string, v *validate.JSONSchemaValidator) func(http.Handler) http.Handler {
+const defaultMaxBody = 1 << 20
+
+type ctxKeyBody struct{}
+
+type capturedBody struct {
+	raw    []byte
+	once   sync.Once
+	parsed any
+}
+
+// bufferBody reads the request body once (up to limit bytes) and keeps it in
+// the request context so validation, matching and rendering share the same buffer.
+func bufferBody(w http.ResponseWriter, r *http.Request, limit int64) (*http.Request, []byte, error) {
+	if cb, ok := r.Context().Value(ctxKeyBody{}).(*capturedBody); ok {
+		return r, cb.raw, nil
+	}
+
+	if limit <= 0 {
+		limit = defaultMaxBody
+	}
+
+	var buf bytes.Buffer
+	if r.Body != nil {
+		limited := http.MaxBytesReader(w, r.Body, limit)
+		if _, err := io.Copy(&buf, limited); err != nil && err != io.EOF {
+			return r, nil, err
+		}
+	}
+	body := buf.Bytes()
+
+	r = r.WithContext(context.WithValue(r.Context(), ctxKeyBody{}, &capturedBody{raw: body}))
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return r, body, nil
+}
+
+// parsedBody returns the captured request body decoded according to its
+// content type, or nil when no body was captured.
+func parsedBody(r *http.Request) any {
+	cb, ok := r.Context().Value(ctxKeyBody{}).(*capturedBody)
+	if !ok {
+		return nil
+	}
+	cb.once.Do(func() {
+		cb.parsed, _ = render.ParseBody(r.Header.Get("Content-Type"), cb.raw)
+	})
+	return cb.parsed
+}
+
+func writeBodyError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	http.Error(w, "failed to read body", http.StatusBadRequest)
+}
+
+func validateBody(wantCT string, v *validate.JSONSchemaValidator, maxBody int64) func(http.Handler) http.Handler {
 	if strings.TrimSpace(wantCT) == "" && v == nil {
 		return func(next http.Handler) http.Handler { return next }
 	}
@@ -136,15 +246,11 @@ func validateBody(wantCT string, v *validate.JSONSchemaValidator) func(http.Hand
 				return
 			}
 
-			const maxBody = 1 << 20
-			var buf bytes.Buffer
-			limited := http.MaxBytesReader(w, r.Body, maxBody)
-			if _, err := io.Copy(&buf, limited); err != nil && err != io.EOF {
-				http.Error(w, "failed to read body", http.StatusBadRequest)
+			r, body, err := bufferBody(w, r, maxBody)
+			if err != nil {
+				writeBodyError(w, err)
 				return
 			}
-			body := buf.Bytes()
-			r.Body = io.NopCloser(bytes.NewReader(body))
 
 			if err := v.Validate(body); err != nil {
 				http.Error(w, err.Error(), http.StatusBadRequest)
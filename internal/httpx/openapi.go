@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Bl4cky99/mocker/internal/errx"
+	"github.com/Bl4cky99/mocker/internal/openapi"
+	"github.com/go-chi/chi/v5"
+)
+
+// loadSpec compiles the operations of server.openapi. Operations that fail
+// to compile are logged and left out.
+func (s *Server) loadSpec() error {
+	s.spec = nil
+	if s.cfg.Server.OpenAPI == "" {
+		return nil
+	}
+	doc, err := openapi.Load(s.cfg.Server.OpenAPI)
+	if err != nil {
+		return err
+	}
+	routes, err := doc.Routes(s.fake)
+	for _, e := range errx.List(err) {
+		s.log.Warn("openapi operation skipped", "err", e)
+	}
+	s.spec = routes
+	return nil
+}
+
+func specHandler(s *Server, rt *openapi.Route) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r, body, err := bufferBody(w, r, 0)
+		if err != nil {
+			writeBodyError(w, err)
+			return
+		}
+
+		if err := rt.Validate(r, func(k string) string { return chi.URLParam(r, k) }, body); err != nil {
+			var re *openapi.RequestError
+			if errors.As(err, &re) {
+				writeJSON(w, re.Status, map[string]any{"error": "request does not match the OpenAPI document", "details": re.Issues})
+				return
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		reply := rt.Reply(r)
+		for k, val := range s.cfg.Server.DefaultHeaders {
+			if w.Header().Get(k) == "" {
+				w.Header().Set(k, val)
+			}
+		}
+		for k, val := range reply.Headers {
+			w.Header().Set(k, val)
+		}
+		w.WriteHeader(reply.Status)
+		if len(reply.Body) > 0 {
+			_, _ = w.Write(reply.Body)
+		}
+	}
+}
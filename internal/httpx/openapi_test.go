@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+)
+
+const specDoc = `openapi: 3.1.0
+info: { title: t, version: "1" }
+paths:
+  /users:
+    get:
+      parameters:
+        - { name: limit, in: query, schema: { type: integer, maximum: 50 } }
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: { type: array, items: { type: object, properties: { id: { type: integer } } } }
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: { type: object, required: [name], properties: { name: { type: string } } }
+      responses:
+        "201": { description: created, content: { application/json: { example: { id: 1 } } } }
+        "409": { description: conflict }
+  /users/{id}:
+    get:
+      parameters:
+        - { name: id, in: path, required: true, schema: { type: integer } }
+      responses:
+        "200": { description: ok, content: { application/json: { example: { id: 1, from: spec } } } }
+`
+
+var _ = Describe("openapi routes", func() {
+	var cfg *config.Config
+
+	BeforeEach(func() {
+		spec := filepath.Join(GinkgoT().TempDir(), "spec.yaml")
+		Expect(os.WriteFile(spec, []byte(specDoc), 0o600)).To(Succeed())
+		cfg = &config.Config{
+			Server: config.ServerConfig{BasePath: "/api", OpenAPI: spec, DefaultHeaders: map[string]string{"X-Mock": "1"}},
+			Endpoints: []config.Endpoint{{
+				Method: "GET", Path: "/users/{userId}",
+				Responses: []config.ResponseVariant{{Status: 200, Body: `{"from":"config"}`}},
+			}},
+		}
+		cfg.ApplyDefaults()
+	})
+
+	newServer := func(opts ...Option) *Server {
+		srv, err := New(context.Background(), cfg, append([]Option{WithLogger(discardLogger())}, opts...)...)
+		Expect(err).NotTo(HaveOccurred())
+		return srv
+	}
+
+	It("serves spec operations with synthesized bodies", func() {
+		rec := call(newServer(), "GET", "/api/users?limit=5", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("X-Mock")).To(Equal("1"))
+		Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+		var users []map[string]any
+		Expect(json.Unmarshal(rec.Body.Bytes(), &users)).To(Succeed())
+	})
+
+	It("synthesizes the same bodies for the same seed", func() {
+		a := call(newServer(WithSeed(42)), "GET", "/api/users?limit=5", "")
+		b := call(newServer(WithSeed(42)), "GET", "/api/users?limit=5", "")
+		Expect(a.Body.String()).To(Equal(b.Body.String()))
+	})
+
+	It("rejects requests that do not match the spec", func() {
+		srv := newServer()
+		rec := call(srv, "GET", "/api/users?limit=500", "")
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		Expect(rec.Body.String()).To(ContainSubstring(`query parameter \"limit\"`))
+
+		Expect(call(srv, "POST", "/api/users", `{}`).Code).To(Equal(http.StatusBadRequest))
+		Expect(call(srv, "POST", "/api/users", `{"name":"a"}`).Code).To(Equal(http.StatusCreated))
+		Expect(call(srv, "POST", "/api/users", `{"name":"a"}`, header("Prefer", "code=409")).Code).To(Equal(http.StatusConflict))
+	})
+
+	It("lets hand-written endpoints override spec operations", func() {
+		rec := call(newServer(), "GET", "/api/users/abc", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal(`{"from":"config"}`))
+	})
+
+	It("fails when the document cannot be loaded", func() {
+		cfg.Server.OpenAPI = filepath.Join(GinkgoT().TempDir(), "missing.yaml")
+		_, err := New(context.Background(), cfg, WithLogger(discardLogger()))
+		Expect(err).To(HaveOccurred())
+	})
+})
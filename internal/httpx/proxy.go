@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+)
+
+// newProxy builds the reverse proxy for server.fallbackProxy.
+func newProxy(pc *config.ProxyConfig, basePath string, log *slog.Logger) (*httputil.ReverseProxy, error) {
+	target, err := url.Parse(pc.Upstream)
+	if err != nil {
+		return nil, err
+	}
+
+	var strip []string
+	if base := strings.TrimRight(basePath, "/"); pc.StripBasePath && base != "" {
+		strip = append(strip, base)
+	}
+	if p := strings.TrimRight(pc.StripPrefix, "/"); p != "" {
+		strip = append(strip, p)
+	}
+
+	return &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			for _, prefix := range strip {
+				pr.Out.URL.Path = stripPathPrefix(pr.Out.URL.Path, prefix)
+				pr.Out.URL.RawPath = ""
+			}
+			pr.SetURL(target)
+			pr.SetXForwarded()
+			for _, h := range pc.RemoveHeaders {
+				pr.Out.Header.Del(h)
+			}
+			for k, v := range pc.SetHeaders {
+				pr.Out.Header.Set(k, v)
+			}
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			log.Error("proxy request failed", "method", r.Method, "path", r.URL.Path, "upstream", target.String(), "err", err)
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		},
+	}, nil
+}
+
+func stripPathPrefix(path, prefix string) string {
+	if path == prefix {
+		return "/"
+	}
+	if rest, ok := strings.CutPrefix(path, prefix+"/"); ok {
+		return "/" + rest
+	}
+	return path
+}
+
+// overrideWriter replaces the status and sets headers of a proxied
+// response right before they are written.
+type overrideWriter struct {
+	http.ResponseWriter
+	status  int
+	headers map[string]string
+	wrote   bool
+}
+
+func (o *overrideWriter) WriteHeader(code int) {
+	if o.wrote {
+		return
+	}
+	o.wrote = true
+	for k, v := range o.headers {
+		o.Header().Set(k, v)
+	}
+	if o.status != 0 {
+		code = o.status
+	}
+	o.ResponseWriter.WriteHeader(code)
+}
+
+func (o *overrideWriter) Write(b []byte) (int, error) {
+	if !o.wrote {
+		o.WriteHeader(http.StatusOK)
+	}
+	return o.ResponseWriter.Write(b)
+}
+
+func (o *overrideWriter) Unwrap() http.ResponseWriter {
+	return o.ResponseWriter
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+)
+
+var _ = Describe("fallback proxy", func() {
+	var (
+		upstream *httptest.Server
+		cfg      *config.Config
+	)
+
+	BeforeEach(func() {
+		upstream = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("X-Upstream", "1")
+			w.WriteHeader(http.StatusTeapot)
+			_, _ = fmt.Fprintf(w, "%s %s key=%s drop=%s", r.Method, r.URL.RequestURI(), r.Header.Get("X-Key"), r.Header.Get("X-Drop"))
+		}))
+		DeferCleanup(upstream.Close)
+
+		cfg = &config.Config{
+			Server: config.ServerConfig{
+				BasePath:       "/api",
+				DefaultHeaders: map[string]string{"Content-Type": "application/json"},
+				FallbackProxy: &config.ProxyConfig{
+					Upstream:      upstream.URL,
+					SetHeaders:    map[string]string{"X-Key": "secret"},
+					RemoveHeaders: []string{"X-Drop"},
+				},
+			},
+			Endpoints: []config.Endpoint{
+				{Method: "GET", Path: "/mocked", Responses: []config.ResponseVariant{{Status: 200, Body: `{"mock":true}`}}},
+				{Method: "GET", Path: "/passthrough", Responses: []config.ResponseVariant{{
+					Proxy:   true,
+					Status:  201,
+					Headers: map[string]string{"X-Variant": "1"},
+				}}},
+			},
+		}
+		cfg.ApplyDefaults()
+	})
+
+	get := func(method, target string) *httptest.ResponseRecorder {
+		srv, err := New(context.Background(), cfg, WithLogger(discardLogger()))
+		Expect(err).NotTo(HaveOccurred())
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, target, nil)
+		req.Header.Set("X-Drop", "x")
+		srv.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	It("serves matched endpoints locally", func() {
+		rec := get("GET", "/api/mocked")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(Equal(`{"mock":true}`))
+	})
+
+	It("forwards unmatched routes and methods with rewritten headers", func() {
+		rec := get("GET", "/api/other?q=1")
+		Expect(rec.Code).To(Equal(http.StatusTeapot))
+		Expect(rec.Header().Get("X-Upstream")).To(Equal("1"))
+		Expect(rec.Body.String()).To(Equal("GET /api/other?q=1 key=secret drop="))
+
+		rec = get("POST", "/api/mocked")
+		Expect(rec.Body.String()).To(HavePrefix("POST /api/mocked "))
+	})
+
+	It("strips the base path and prefix when configured", func() {
+		cfg.Server.FallbackProxy.StripBasePath = true
+		cfg.Server.FallbackProxy.StripPrefix = "/v1"
+		Expect(get("GET", "/api/v1/users").Body.String()).To(HavePrefix("GET /users "))
+		Expect(get("GET", "/api/v1").Body.String()).To(HavePrefix("GET / "))
+		Expect(get("GET", "/api/v10").Body.String()).To(HavePrefix("GET /v10 "))
+	})
+
+	It("lets proxy variants override status and headers", func() {
+		rec := get("GET", "/api/passthrough")
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+		Expect(rec.Header().Get("X-Variant")).To(Equal("1"))
+		Expect(rec.Header().Values("Content-Type")).To(Equal([]string{"text/plain"}))
+		Expect(rec.Body.String()).To(HavePrefix("GET /api/passthrough "))
+	})
+
+	It("keeps the upstream status when the variant sets none", func() {
+		cfg.Endpoints[1].Responses[0].Status = 0
+		Expect(get("GET", "/api/passthrough").Code).To(Equal(http.StatusTeapot))
+	})
+
+	It("answers 502 when the upstream is unreachable", func() {
+		upstream.Close()
+		Expect(get("GET", "/api/other").Code).To(Equal(http.StatusBadGateway))
+	})
+})
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sync"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/fake"
+)
+
+// lockedRand is a seeded RNG safe for concurrent use. It is shared across
+// reloads so a seeded run stays reproducible.
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func newLockedRand(seed uint64) *lockedRand {
+	return &lockedRand{r: rand.New(rand.NewPCG(seed, seed))}
+}
+
+func (l *lockedRand) IntN(n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.IntN(n)
+}
+
+// WithSeed makes random variant selection and the bodies sampled from the
+// OpenAPI document deterministic.
+func WithSeed(seed uint64) Option {
+	return func(s *Server) {
+		s.rnd = newLockedRand(seed)
+		s.fake = fake.New(seed)
+	}
+}
+
+// weightedPick picks one of cands with probability proportional to its
+// weight. Without any weights every candidate counts as 1; once one is
+// weighted, candidates with weight 0 are never picked.
+func weightedPick(cands []*config.ResponseVariant, rnd *lockedRand) *config.ResponseVariant {
+	weighted := slices.ContainsFunc(cands, func(v *config.ResponseVariant) bool { return v.Weight > 0 })
+	weight := func(v *config.ResponseVariant) int {
+		if !weighted {
+			return 1
+		}
+		return max(v.Weight, 0)
+	}
+
+	total := 0
+	for _, v := range cands {
+		total += weight(v)
+	}
+
+	n := rnd.IntN(total)
+	for _, v := range cands {
+		n -= weight(v)
+		if n < 0 {
+			return v
+		}
+	}
+	return cands[len(cands)-1]
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+)
+
+var _ = Describe("random selection", func() {
+	newChaosServer := func(opts ...Option) *Server {
+		cfg := &config.Config{
+			Server: config.ServerConfig{BasePath: "/"},
+			Endpoints: []config.Endpoint{{
+				Method:    "GET",
+				Path:      "/chaos",
+				Selection: "random",
+				Responses: []config.ResponseVariant{
+					{Status: 200, Body: "ok", Weight: 9},
+					{Status: 503, Body: "down", Weight: 1},
+					{Status: 418, Body: "debug", When: &config.WhenClause{Header: map[string]config.Matcher{"X-Debug": config.Eq("1")}}},
+				},
+			}},
+		}
+		cfg.ApplyDefaults()
+		srv, err := New(context.Background(), cfg, append([]Option{WithLogger(discardLogger())}, opts...)...)
+		Expect(err).NotTo(HaveOccurred())
+		return srv
+	}
+
+	statuses := func(srv *Server, n int, header string) []int {
+		out := make([]int, 0, n)
+		for range n {
+			req := httptest.NewRequest(http.MethodGet, "/chaos", nil)
+			if header != "" {
+				req.Header.Set("X-Debug", header)
+			}
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, req)
+			out = append(out, rec.Code)
+		}
+		return out
+	}
+
+	It("is reproducible with the same seed", func() {
+		a := statuses(newChaosServer(WithSeed(42)), 50, "")
+		b := statuses(newChaosServer(WithSeed(42)), 50, "")
+		Expect(a).To(Equal(b))
+	})
+
+	It("picks variants proportionally to their weight", func() {
+		counts := map[int]int{}
+		for _, code := range statuses(newChaosServer(WithSeed(7)), 2000, "") {
+			counts[code]++
+		}
+		Expect(counts).To(HaveLen(2))
+		Expect(counts[503]).To(BeNumerically("~", 200, 60))
+	})
+
+	It("only picks among the variants whose conditions match", func() {
+		Expect(statuses(newChaosServer(WithSeed(1)), 10, "1")).To(HaveEach(418))
+	})
+
+	It("treats unset weights as 1", func() {
+		cands := []*config.ResponseVariant{{Status: 1}, {Status: 2, Weight: 0}}
+		rnd := newLockedRand(3)
+		seen := map[int]bool{}
+		for range 50 {
+			seen[weightedPick(cands, rnd).Status] = true
+		}
+		Expect(seen).To(Equal(map[int]bool{1: true, 2: true}))
+	})
+
+	It("never picks variants with weight 0 next to weighted ones", func() {
+		cands := []*config.ResponseVariant{{Status: 1, Weight: 0}, {Status: 2, Weight: 1}, {Status: 3}}
+		rnd := newLockedRand(3)
+		for range 50 {
+			Expect(weightedPick(cands, rnd).Status).To(Equal(2))
+		}
+	})
+})
@@ -0,0 +1,244 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/resource"
+	"github.com/Bl4cky99/mocker/internal/validate"
+	"github.com/go-chi/chi/v5"
+)
+
+type resourceEntry struct {
+	def   config.Resource
+	seed  [32]byte
+	store *resource.Store
+}
+
+// resourceSet holds the stores of the configured resources. A store whose
+// definition and seed file did not change keeps its data across reloads.
+type resourceSet struct {
+	mu      sync.Mutex
+	entries map[string]*resourceEntry
+}
+
+func newResourceSet() *resourceSet {
+	return &resourceSet{entries: make(map[string]*resourceEntry)}
+}
+
+// prepare builds the entries for defs without activating them.
+func (rs *resourceSet) prepare(defs []config.Resource) (map[string]*resourceEntry, error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	next := make(map[string]*resourceEntry, len(defs))
+	for _, def := range defs {
+		var (
+			raw   []byte
+			items []resource.Item
+			err   error
+		)
+		if def.SeedFile != "" {
+			if raw, err = os.ReadFile(def.SeedFile); err != nil {
+				return nil, err
+			}
+			if items, err = resource.LoadSeed(def.SeedFile); err != nil {
+				return nil, err
+			}
+		}
+
+		e := &resourceEntry{def: def, seed: sha256.Sum256(raw)}
+		if prev, ok := rs.entries[def.Path]; ok && prev.def == def && prev.seed == e.seed {
+			e.store = prev.store
+		} else {
+			e.store = resource.NewStore(def.IDField, def.IDStrategy)
+			if err := e.store.Seed(items); err != nil {
+				return nil, err
+			}
+		}
+		next[def.Path] = e
+	}
+	return next, nil
+}
+
+func (rs *resourceSet) swap(next map[string]*resourceEntry) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.entries = next
+}
+
+func (rs *resourceSet) stores() map[string]*resource.Store {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	out := make(map[string]*resource.Store, len(rs.entries))
+	for p, e := range rs.entries {
+		out[p] = e.store
+	}
+	return out
+}
+
+func (s *Server) resourceValidator(def config.Resource) *validate.JSONSchemaValidator {
+	if def.SchemaFile == "" {
+		return nil
+	}
+	abs, _ := filepath.Abs(def.SchemaFile)
+	return s.validators[abs]
+}
+
+// registerResource adds the CRUD routes of one resource to r.
+func registerResource(r chi.Router, s *Server, e *resourceEntry) {
+	st := e.store
+	list, item := e.def.Path, e.def.Path+"/{id}"
+	sch := s.resourceValidator(e.def)
+
+	check := func(it resource.Item) error {
+		if sch == nil {
+			return nil
+		}
+		b, _ := json.Marshal(it)
+		return sch.Validate(b)
+	}
+
+	readItem := func(w http.ResponseWriter, r *http.Request) (resource.Item, bool) {
+		_, body, err := bufferBody(w, r, 0)
+		if err != nil {
+			writeBodyError(w, err)
+			return nil, false
+		}
+		it, err := resource.DecodeItem(body)
+		if err == nil {
+			err = check(it)
+		}
+		if err != nil {
+			writeResourceError(w, err)
+			return nil, false
+		}
+		return it, true
+	}
+
+	r.Get(list, func(w http.ResponseWriter, r *http.Request) {
+		q, err := resource.ParseQuery(r.URL.Query())
+		if err != nil {
+			writeResourceError(w, err)
+			return
+		}
+		items, total := st.List(q)
+		w.Header().Set("X-Total-Count", strconv.Itoa(total))
+		writeResource(w, s, http.StatusOK, items)
+	})
+
+	r.Post(list, func(w http.ResponseWriter, r *http.Request) {
+		it, ok := readItem(w, r)
+		if !ok {
+			return
+		}
+		created, err := st.Create(it)
+		if err != nil {
+			writeResourceError(w, err)
+			return
+		}
+		id, _ := resource.IDString(created[st.IDField()])
+		w.Header().Set("Location", joinURLPath(r.URL.Path, id))
+		writeResource(w, s, http.StatusCreated, created)
+	})
+
+	r.Get(item, func(w http.ResponseWriter, r *http.Request) {
+		it, err := st.Get(chi.URLParam(r, "id"))
+		if err != nil {
+			writeResourceError(w, err)
+			return
+		}
+		writeResource(w, s, http.StatusOK, it)
+	})
+
+	r.Put(item, func(w http.ResponseWriter, r *http.Request) {
+		it, ok := readItem(w, r)
+		if !ok {
+			return
+		}
+		replaced, err := st.Replace(chi.URLParam(r, "id"), it)
+		if err != nil {
+			writeResourceError(w, err)
+			return
+		}
+		writeResource(w, s, http.StatusOK, replaced)
+	})
+
+	r.Patch(item, func(w http.ResponseWriter, r *http.Request) {
+		_, body, err := bufferBody(w, r, 0)
+		if err != nil {
+			writeBodyError(w, err)
+			return
+		}
+		patch, err := resource.DecodeItem(body)
+		if err != nil {
+			writeResourceError(w, err)
+			return
+		}
+		patched, err := st.Patch(chi.URLParam(r, "id"), patch, check)
+		if err != nil {
+			writeResourceError(w, err)
+			return
+		}
+		writeResource(w, s, http.StatusOK, patched)
+	})
+
+	r.Delete(item, func(w http.ResponseWriter, r *http.Request) {
+		if err := st.Delete(chi.URLParam(r, "id")); err != nil {
+			writeResourceError(w, err)
+			return
+		}
+		setDefaultHeaders(w, s)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func writeResource(w http.ResponseWriter, s *Server, status int, v any) {
+	setDefaultHeaders(w, s)
+	writeJSON(w, status, v)
+}
+
+func setDefaultHeaders(w http.ResponseWriter, s *Server) {
+	for k, val := range s.cfg.Server.DefaultHeaders {
+		if w.Header().Get(k) == "" {
+			w.Header().Set(k, val)
+		}
+	}
+}
+
+func writeResourceError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, resource.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, resource.ErrConflict):
+		status = http.StatusConflict
+	}
+	writeJSON(w, status, map[string]any{"error": err.Error()})
+}
+
+func joinURLPath(base, id string) string {
+	if len(base) > 0 && base[len(base)-1] == '/' {
+		return base + id
+	}
+	return base + "/" + id
+}
+
+// resourceCounts reports the number of items per resource path.
+func resourceCounts(stores map[string]*resource.Store) map[string]int {
+	out := make(map[string]int, len(stores))
+	for p, st := range stores {
+		out[p] = st.Len()
+	}
+	return out
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+)
+
+var _ = Describe("resources", func() {
+	var (
+		dir string
+		cfg *config.Config
+	)
+
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		Expect(os.WriteFile(p, []byte(content), 0o600)).To(Succeed())
+		return p
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		cfg = &config.Config{
+			Server: config.ServerConfig{BasePath: "/api", DefaultHeaders: map[string]string{"X-Mock": "1"}},
+			Resources: []config.Resource{{
+				Path:       "/users",
+				SeedFile:   write("users.json", `[{"id":1,"name":"alice","age":30},{"id":2,"name":"bob","age":25}]`),
+				SchemaFile: write("user.schema.json", `{"type":"object","required":["name"],"properties":{"name":{"type":"string"}}}`),
+			}},
+			Endpoints: []config.Endpoint{{
+				Method:    "GET",
+				Path:      "/users/stats",
+				Responses: []config.ResponseVariant{{Status: 200, Body: "stats"}},
+			}},
+		}
+		cfg.ApplyDefaults()
+	})
+
+	newServer := func() *Server {
+		srv, err := New(context.Background(), cfg, WithLogger(discardLogger()))
+		Expect(err).NotTo(HaveOccurred())
+		return srv
+	}
+
+	It("serves the full CRUD cycle", func() {
+		srv := newServer()
+
+		rec := call(srv, "POST", "/api/users", `{"name":"carol","age":35}`)
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+		Expect(rec.Header().Get("Location")).To(Equal("/api/users/3"))
+		Expect(rec.Header().Get("X-Mock")).To(Equal("1"))
+		Expect(rec.Body.String()).To(MatchJSON(`{"id":3,"name":"carol","age":35}`))
+
+		rec = call(srv, "GET", "/api/users/3", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(MatchJSON(`{"id":3,"name":"carol","age":35}`))
+
+		rec = call(srv, "PUT", "/api/users/3", `{"name":"caroline"}`)
+		Expect(rec.Body.String()).To(MatchJSON(`{"id":3,"name":"caroline"}`))
+
+		rec = call(srv, "PATCH", "/api/users/3", `{"age":36}`)
+		Expect(rec.Body.String()).To(MatchJSON(`{"id":3,"name":"caroline","age":36}`))
+
+		Expect(call(srv, "DELETE", "/api/users/3", "").Code).To(Equal(http.StatusNoContent))
+		Expect(call(srv, "GET", "/api/users/3", "").Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("filters, sorts and pages the list", func() {
+		srv := newServer()
+		rec := call(srv, "GET", "/api/users?_sort=age&_limit=1", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("X-Total-Count")).To(Equal("2"))
+		Expect(rec.Body.String()).To(MatchJSON(`[{"id":2,"name":"bob","age":25}]`))
+
+		Expect(call(srv, "GET", "/api/users?_limit=x", "").Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("validates items against the schema", func() {
+		srv := newServer()
+		Expect(call(srv, "POST", "/api/users", `{"age":1}`).Code).To(Equal(http.StatusBadRequest))
+		Expect(call(srv, "POST", "/api/users", `[1]`).Code).To(Equal(http.StatusBadRequest))
+		Expect(call(srv, "PATCH", "/api/users/1", `{"name":7}`).Code).To(Equal(http.StatusBadRequest))
+		Expect(call(srv, "POST", "/api/users", `{"id":1,"name":"x"}`).Code).To(Equal(http.StatusConflict))
+	})
+
+	It("keeps hand-written endpoints next to the generated routes", func() {
+		Expect(call(newServer(), "GET", "/api/users/stats", "").Body.String()).To(Equal("stats"))
+	})
+
+	It("keeps data across reloads unless the seed changes", func() {
+		srv := newServer()
+		call(srv, "POST", "/api/users", `{"name":"carol"}`)
+
+		Expect(srv.Reload(cfg)).To(Succeed())
+		Expect(call(srv, "GET", "/api/users/3", "").Code).To(Equal(http.StatusOK))
+
+		write("users.json", `[{"id":1,"name":"alice"}]`)
+		Expect(srv.Reload(cfg)).To(Succeed())
+		Expect(call(srv, "GET", "/api/users", "").Header().Get("X-Total-Count")).To(Equal("1"))
+	})
+
+	It("counts and resets resources via the admin endpoint", func() {
+		srv := newServer()
+		call(srv, "DELETE", "/api/users/1", "")
+
+		rec := call(srv, "GET", AdminPrefix+"/resources", "")
+		Expect(rec.Body.String()).To(MatchJSON(`{"resources":{"/users":1}}`))
+
+		Expect(call(srv, "DELETE", AdminPrefix+"/resources?path=/nope", "").Code).To(Equal(http.StatusNotFound))
+		rec = call(srv, "DELETE", AdminPrefix+"/resources?path=/users", "")
+		Expect(rec.Body.String()).To(MatchJSON(`{"resources":{"/users":2}}`))
+	})
+
+	It("fails to start with an invalid seed file", func() {
+		cfg.Resources[0].SeedFile = write("bad.json", `{"id":1}`)
+		_, err := New(context.Background(), cfg, WithLogger(discardLogger()))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -4,34 +4,51 @@
 package httpx
 
 import (
+	"maps"
 	"net/http"
 	"path/filepath"
 	"strings"
 
+	"github.com/Bl4cky99/mocker/internal/auth"
+	"github.com/Bl4cky99/mocker/internal/config"
 	"github.com/Bl4cky99/mocker/internal/validate"
 	"github.com/go-chi/chi/v5"
 )
 
 func buildRouter(s *Server) http.Handler {
 	r := chi.NewRouter()
-	r.Use(recoverMW(s.log), requestIDMW(), loggingMW(s.log))
+	r.Use(recoverMW(s.log), requestIDMW(), loggingMW(s.log), corsMW(s.cfg.Server.CORS))
 
-	if s.authMode != "" && s.authMode != "none" && s.authProv != nil {
-		if s.cfg.Server.CORS != nil {
-			r.Use(skipAuthForOPTIONS(requireAuth(s.authProv, s.authMode)))
-		} else {
-			r.Use(requireAuth(s.authProv, s.authMode))
-		}
+	r.Route(AdminPrefix, adminRoutes(s))
 
+	if s.cfg.OAuth != nil && s.oauth != nil {
+		r.Mount(s.cfg.OAuth.Prefix, s.oauth.Handler())
+	}
+	provs := s.providers()
+
+	if s.proxy != nil {
+		r.NotFound(s.proxy.ServeHTTP)
+		r.MethodNotAllowed(s.proxy.ServeHTTP)
 	}
 
 	base := strings.TrimRight(s.cfg.Server.BasePath, "/")
 	if base == "" {
 		base = "/"
 	}
-	r.Route(base, func(sr chi.Router) {
+	r.Route(base, func(r chi.Router) {
+		// resources and spec operations use the server auth
+		def := r.With(s.authFor(nil, provs))
+
+		for _, res := range s.cfg.Resources {
+			registerResource(def, s, s.resources[res.Path])
+		}
+
 		for _, ep := range s.cfg.Endpoints {
 			h := endpointHandler(s, ep)
+			sr := r.With(s.authFor(ep.Auth, provs))
+			if ep.Requires != nil {
+				sr = sr.With(s.guard(requireGrants(*ep.Requires, s.cfg.Auth.ForbiddenBody)))
+			}
 
 			if ep.Validate != nil && (ep.Validate.ContentType != "" || ep.Validate.SchemaFile != "") {
 				var sch *validate.JSONSchemaValidator
@@ -40,13 +57,94 @@ func buildRouter(s *Server) http.Handler {
 					sch = s.validators[abs]
 				}
 
-				sr.With(validateBody(ep.Validate.ContentType, sch)).Method(ep.Method, ep.Path, h)
+				sr.With(validateBody(ep.Validate.ContentType, sch, ep.MaxBodyBytes)).Method(ep.Method, ep.Path, h)
 				continue
 			}
 
 			sr.Method(ep.Method, ep.Path, h)
 		}
+
+		// hand-written endpoints and resources take precedence over the spec
+		taken := map[string]bool{}
+		for _, res := range s.cfg.Resources {
+			for _, k := range config.ResourceRoutes(res.Path) {
+				taken[k] = true
+			}
+		}
+		for _, ep := range s.cfg.Endpoints {
+			taken[config.RouteKey(ep.Method, ep.Path)] = true
+		}
+		for _, rt := range s.spec {
+			if k := config.RouteKey(rt.Method, rt.Path); !taken[k] {
+				taken[k] = true
+				def.Method(rt.Method, rt.Path, specHandler(s, rt))
+			}
+		}
 	})
 
 	return r
 }
+
+// providers returns the auth providers by type, with the server default
+// under its mode. JWT auth also trusts the built-in oauth issuer.
+func (s *Server) providers() map[string]auth.Provider {
+	provs := maps.Clone(s.authProvs)
+	if provs == nil {
+		provs = map[string]auth.Provider{}
+	}
+	if s.authProv != nil {
+		provs[s.authMode] = s.authProv
+	}
+	if jp, ok := provs["jwt"].(*auth.JWTAuth); ok && s.cfg.OAuth != nil && s.oauth != nil {
+		provs["jwt"] = jp.Trusting(s.oauth.KeyID(), s.oauth.PublicKey())
+	}
+	return provs
+}
+
+// authFor returns the auth middleware for an endpoint override, or for the
+// server auth when ea is nil.
+func (s *Server) authFor(ea *config.EndpointAuth, provs map[string]auth.Provider) func(http.Handler) http.Handler {
+	mode := s.authMode
+	if ea != nil && ea.Type != "" && ea.Type != "inherit" {
+		mode = ea.Type
+	}
+	narrow := func(p auth.Provider) auth.Provider {
+		if tp, ok := p.(*auth.TokenAuth); ok && ea != nil && len(ea.Tokens) > 0 {
+			return tp.Only(ea.Tokens)
+		}
+		return p
+	}
+
+	var p auth.Provider
+	switch mode {
+	case "any", "all":
+		var members []auth.Provider
+		for _, typ := range s.cfg.Auth.Providers {
+			if m := provs[typ]; m != nil {
+				members = append(members, narrow(m))
+			}
+		}
+		if mode == "all" {
+			p = auth.AllOf(members...)
+		} else {
+			p = auth.AnyOf(members...)
+		}
+	default:
+		if provs[mode] != nil {
+			p = narrow(provs[mode])
+		}
+	}
+	if p != nil && ea != nil && len(ea.Users) > 0 {
+		p = auth.AllowNames(p, ea.Users)
+	}
+
+	return s.guard(requireAuth(p, mode))
+}
+
+// guard lets OPTIONS requests past auth middleware when CORS is enabled.
+func (s *Server) guard(mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	if s.cfg.Server.CORS != nil && s.cfg.Server.CORS.Enabled {
+		return skipAuthForOPTIONS(mw)
+	}
+	return mw
+}
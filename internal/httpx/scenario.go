@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+)
+
+// scenarios holds the current state of every declared scenario. Like the
+// sequence counters it is shared across reloads.
+type scenarios struct {
+	mu     sync.Mutex
+	defs   map[string]config.Scenario
+	states map[string]string
+}
+
+func newScenarios() *scenarios {
+	return &scenarios{defs: make(map[string]config.Scenario), states: make(map[string]string)}
+}
+
+// configure installs the scenario definitions. Scenarios that still exist
+// keep their current state if it is still allowed; all others start over.
+func (s *scenarios) configure(defs []config.Scenario) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := make(map[string]string, len(defs))
+	s.defs = make(map[string]config.Scenario, len(defs))
+	for _, d := range defs {
+		s.defs[d.Name] = d
+		next[d.Name] = d.Initial
+		if cur, ok := s.states[d.Name]; ok && allowedState(d, cur) {
+			next[d.Name] = cur
+		}
+	}
+	s.states = next
+}
+
+func (s *scenarios) state(name string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.states[name]
+	return st, ok
+}
+
+func (s *scenarios) set(name, state string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.defs[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownScenario, name)
+	}
+	if !allowedState(d, state) {
+		return fmt.Errorf("%w: %q is not a state of scenario %q", ErrUnknownState, state, name)
+	}
+	s.states[name] = state
+	return nil
+}
+
+// reset puts the named scenario, or every scenario for an empty name, back
+// into its initial state.
+func (s *scenarios) reset(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if name == "" {
+		for n, d := range s.defs {
+			s.states[n] = d.Initial
+		}
+		return nil
+	}
+	d, ok := s.defs[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownScenario, name)
+	}
+	s.states[name] = d.Initial
+	return nil
+}
+
+func (s *scenarios) snapshot() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.states)
+}
+
+func allowedState(d config.Scenario, state string) bool {
+	return state != "" && (len(d.States) == 0 || slices.Contains(d.States, state))
+}
+
+type ctxKeyState struct{}
+
+// withScenarioState stores the current state of the endpoint's scenario on
+// the request so when.state conditions can be evaluated.
+func (s *Server) withScenarioState(ep config.Endpoint, r *http.Request) *http.Request {
+	name := s.cfg.ScenarioFor(ep)
+	if name == "" || s.scn == nil {
+		return r
+	}
+	st, _ := s.scn.state(name)
+	return r.WithContext(context.WithValue(r.Context(), ctxKeyState{}, st))
+}
+
+func scenarioState(r *http.Request) (string, bool) {
+	st, ok := r.Context().Value(ctxKeyState{}).(string)
+	return st, ok
+}
+
+// applyState performs the variant's state transition, if any.
+func (s *Server) applyState(ep config.Endpoint, v config.ResponseVariant) {
+	if v.SetState == "" || s.scn == nil {
+		return
+	}
+	name := s.cfg.ScenarioFor(ep)
+	if err := s.scn.set(name, v.SetState); err != nil {
+		s.log.Warn("scenario transition failed", "endpoint", endpointKey(ep), "err", err)
+	}
+}
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+)
+
+var _ = Describe("scenarios", func() {
+	state := func(s string) *config.WhenClause {
+		m := config.Eq(s)
+		return &config.WhenClause{State: &m}
+	}
+
+	newOrdersConfig := func() *config.Config {
+		cfg := &config.Config{
+			Server:    config.ServerConfig{BasePath: "/"},
+			Scenarios: []config.Scenario{{Name: "orders", Initial: "empty", States: []string{"empty", "created", "deleted"}}},
+			Endpoints: []config.Endpoint{
+				{
+					Method:    "POST",
+					Path:      "/orders",
+					Responses: []config.ResponseVariant{{Status: 201, Body: "created", SetState: "created"}},
+				},
+				{
+					Method: "GET",
+					Path:   "/orders/{id}",
+					Responses: []config.ResponseVariant{
+						{Status: 200, Body: "order", When: state("created")},
+						{Status: 404, Body: "missing"},
+					},
+				},
+				{
+					Method: "DELETE",
+					Path:   "/orders/{id}",
+					Responses: []config.ResponseVariant{
+						{Status: 204, Body: "", SetState: "deleted", When: state("created")},
+						{Status: 404, Body: "missing"},
+					},
+				},
+			},
+		}
+		cfg.ApplyDefaults()
+		return cfg
+	}
+
+	newOrdersServer := func(opts ...Option) *Server {
+		srv, err := New(context.Background(), newOrdersConfig(), append([]Option{WithLogger(discardLogger())}, opts...)...)
+		Expect(err).NotTo(HaveOccurred())
+		return srv
+	}
+
+	It("moves through the states across endpoints", func() {
+		srv := newOrdersServer()
+		Expect(call(srv, "GET", "/orders/1", "").Code).To(Equal(404))
+		Expect(call(srv, "POST", "/orders", "").Code).To(Equal(201))
+		Expect(call(srv, "GET", "/orders/1", "").Code).To(Equal(200))
+		Expect(call(srv, "DELETE", "/orders/1", "").Code).To(Equal(204))
+		Expect(call(srv, "GET", "/orders/1", "").Code).To(Equal(404))
+		Expect(call(srv, "DELETE", "/orders/1", "").Code).To(Equal(404))
+	})
+
+	It("starts in the state given by WithScenarioStates", func() {
+		srv := newOrdersServer(WithScenarioStates(map[string]string{"orders": "created"}))
+		Expect(call(srv, "GET", "/orders/1", "").Code).To(Equal(200))
+	})
+
+	It("rejects unknown scenarios and states at startup", func() {
+		_, err := New(context.Background(), newOrdersConfig(), WithScenarioStates(map[string]string{"carts": "x"}))
+		Expect(err).To(MatchError(ErrUnknownScenario))
+
+		_, err = New(context.Background(), newOrdersConfig(), WithScenarioStates(map[string]string{"orders": "paid"}))
+		Expect(err).To(MatchError(ErrUnknownState))
+	})
+
+	It("keeps the current state across reloads while it stays valid", func() {
+		srv := newOrdersServer()
+		call(srv, "POST", "/orders", "")
+
+		Expect(srv.Reload(newOrdersConfig())).To(Succeed())
+		Expect(call(srv, "GET", "/orders/1", "").Code).To(Equal(200))
+
+		cfg := newOrdersConfig()
+		cfg.Scenarios[0].States = []string{"empty"}
+		Expect(srv.Reload(cfg)).To(Succeed())
+		Expect(srv.scn.snapshot()).To(HaveKeyWithValue("orders", "empty"))
+	})
+
+	It("lists, sets and resets scenarios via the admin endpoint", func() {
+		srv := newOrdersServer()
+
+		rec := call(srv, "GET", AdminPrefix+"/scenarios", "")
+		Expect(rec.Body.String()).To(MatchJSON(`{"scenarios":{"orders":"empty"}}`))
+
+		rec = call(srv, "PUT", AdminPrefix+"/scenarios/orders", `{"state":"created"}`)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(call(srv, "GET", "/orders/1", "").Code).To(Equal(200))
+
+		rec = call(srv, "PUT", AdminPrefix+"/scenarios/orders?state=deleted", "")
+		Expect(rec.Body.String()).To(MatchJSON(`{"scenarios":{"orders":"deleted"}}`))
+
+		rec = call(srv, "PUT", AdminPrefix+"/scenarios/orders", `{"state":"paid"}`)
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		rec = call(srv, "PUT", AdminPrefix+"/scenarios/carts", `{"state":"x"}`)
+		Expect(rec.Code).To(Equal(http.StatusNotFound))
+
+		rec = call(srv, "DELETE", AdminPrefix+"/scenarios/orders", "")
+		var out struct{ Scenarios map[string]string }
+		Expect(json.Unmarshal(rec.Body.Bytes(), &out)).To(Succeed())
+		Expect(out.Scenarios).To(HaveKeyWithValue("orders", "empty"))
+
+		call(srv, "POST", "/orders", "")
+		Expect(call(srv, "DELETE", AdminPrefix+"/scenarios", "").Code).To(Equal(http.StatusOK))
+		Expect(call(srv, "GET", "/orders/1", "").Code).To(Equal(404))
+	})
+})
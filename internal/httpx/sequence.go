@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/render"
+)
+
+// sequences holds the per-endpoint call counters for sequence mode. It is
+// shared across reloads so counters survive config changes.
+type sequences struct {
+	mu       sync.Mutex
+	counters map[string]int
+}
+
+func newSequences() *sequences {
+	return &sequences{counters: make(map[string]int)}
+}
+
+// next returns the number of previous calls for key and counts this one.
+func (s *sequences) next(key string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := s.counters[key]
+	s.counters[key] = n + 1
+	return n
+}
+
+// reset clears the counters of endpoint, or only the one for key when key
+// is set, and returns how many were removed. An empty endpoint clears
+// everything.
+func (s *sequences) reset(endpoint, key string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	match := func(k string) bool {
+		switch {
+		case endpoint == "":
+			return true
+		case key != "":
+			return k == endpoint+"|"+key
+		}
+		return k == endpoint || strings.HasPrefix(k, endpoint+"|")
+	}
+	n := 0
+	for k := range s.counters {
+		if match(k) {
+			delete(s.counters, k)
+			n++
+		}
+	}
+	return n
+}
+
+func (s *sequences) snapshot() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.counters)
+}
+
+// restore replaces all counters.
+func (s *sequences) restore(counters map[string]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counters = maps.Clone(counters)
+	if s.counters == nil {
+		s.counters = make(map[string]int)
+	}
+}
+
+func endpointKey(ep config.Endpoint) string {
+	return strings.ToUpper(ep.Method) + " " + ep.Path
+}
+
+// selectVariant picks the response for r among the matching variants: the
+// first one by default, a weighted random one for selection: random, and the
+// n-th one on the n-th call for a sequence.
+func (s *Server) selectVariant(ep config.Endpoint, r *http.Request, data render.Data) config.ResponseVariant {
+	cands := candidates(ep, r)
+	if ep.Selection == "random" && s.rnd != nil {
+		return *weightedPick(cands, s.rnd)
+	}
+	if ep.Sequence == nil || s.seq == nil {
+		return *cands[0]
+	}
+
+	key := endpointKey(ep)
+	if ep.Sequence.Key != "" {
+		k, err := s.templates().RenderString(render.EngineText, ep.Sequence.Key, data)
+		if err != nil {
+			s.log.Warn("sequence key render failed, using shared counter", "endpoint", key, "err", err)
+		} else {
+			key += "|" + string(k)
+		}
+	}
+
+	n := s.seq.next(key)
+	if ep.Sequence.Mode == "cycle" {
+		return *cands[n%len(cands)]
+	}
+	return *cands[min(n, len(cands)-1)]
+}
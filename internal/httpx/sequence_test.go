@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/render"
+)
+
+var _ = Describe("sequence mode", func() {
+	newSeqServer := func(seq *config.SequenceSpec) *Server {
+		cfg := &config.Config{
+			Server: config.ServerConfig{BasePath: "/"},
+			Endpoints: []config.Endpoint{{
+				Method:   "GET",
+				Path:     "/jobs/{id}",
+				Sequence: seq,
+				Responses: []config.ResponseVariant{
+					{Status: http.StatusAccepted, Body: "pending"},
+					{Status: http.StatusAccepted, Body: "pending"},
+					{Status: http.StatusOK, Body: "done"},
+				},
+			}},
+		}
+		cfg.ApplyDefaults()
+		srv, err := New(context.Background(), cfg, WithLogger(discardLogger()), WithRenderer(render.New()))
+		Expect(err).NotTo(HaveOccurred())
+		return srv
+	}
+
+	statuses := func(srv *Server, target string, n int) []int {
+		var out []int
+		for range n {
+			out = append(out, call(srv, http.MethodGet, target, "").Code)
+		}
+		return out
+	}
+
+	It("sticks on the last response by default", func() {
+		srv := newSeqServer(&config.SequenceSpec{})
+		Expect(statuses(srv, "/jobs/1", 5)).To(Equal([]int{202, 202, 200, 200, 200}))
+	})
+
+	It("starts over in cycle mode", func() {
+		srv := newSeqServer(&config.SequenceSpec{Mode: "cycle"})
+		Expect(statuses(srv, "/jobs/1", 4)).To(Equal([]int{202, 202, 200, 202}))
+	})
+
+	It("keeps separate counters per rendered key", func() {
+		srv := newSeqServer(&config.SequenceSpec{Key: "{{ .Path.id }}"})
+		Expect(statuses(srv, "/jobs/1", 3)).To(Equal([]int{202, 202, 200}))
+		Expect(statuses(srv, "/jobs/2", 1)).To(Equal([]int{202}))
+	})
+
+	It("lists and resets counters via the admin endpoint", func() {
+		srv := newSeqServer(&config.SequenceSpec{Key: "{{ .Path.id }}"})
+		statuses(srv, "/jobs/1", 3)
+		statuses(srv, "/jobs/2", 1)
+
+		rec := call(srv, http.MethodGet, AdminPrefix+"/sequences", "")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var listed struct{ Counters map[string]int }
+		Expect(json.Unmarshal(rec.Body.Bytes(), &listed)).To(Succeed())
+		Expect(listed.Counters).To(HaveKeyWithValue("GET /jobs/{id}|1", 3))
+
+		rec = call(srv, http.MethodDelete, AdminPrefix+"/sequences?endpoint=get%20/jobs/%7Bid%7D&key=1", "")
+		Expect(rec.Body.String()).To(MatchJSON(`{"reset":1}`))
+		Expect(statuses(srv, "/jobs/1", 1)).To(Equal([]int{202}))
+		Expect(statuses(srv, "/jobs/2", 1)).To(Equal([]int{202}))
+
+		rec = call(srv, http.MethodDelete, AdminPrefix+"/sequences", "")
+		Expect(rec.Body.String()).To(MatchJSON(`{"reset":2}`))
+	})
+
+	It("resets only the named endpoint and exactly the given key", func() {
+		responses := []config.ResponseVariant{{Status: http.StatusAccepted}, {Status: http.StatusOK}}
+		cfg := &config.Config{
+			Server: config.ServerConfig{BasePath: "/"},
+			Endpoints: []config.Endpoint{
+				{Method: "GET", Path: "/jobs", Sequence: &config.SequenceSpec{}, Responses: responses},
+				{Method: "GET", Path: "/jobs/{id}", Sequence: &config.SequenceSpec{Key: "{{ .Path.id }}"}, Responses: responses},
+			},
+		}
+		cfg.ApplyDefaults()
+		srv, err := New(context.Background(), cfg, WithLogger(discardLogger()), WithRenderer(render.New()))
+		Expect(err).NotTo(HaveOccurred())
+		for _, target := range []string{"/jobs", "/jobs/4", "/jobs/42", "/jobs/4x"} {
+			statuses(srv, target, 1)
+		}
+
+		rec := call(srv, http.MethodDelete, AdminPrefix+"/sequences?endpoint=GET%20/jobs", "")
+		Expect(rec.Body.String()).To(MatchJSON(`{"reset":1}`))
+		Expect(statuses(srv, "/jobs/4", 1)).To(Equal([]int{200}), "sibling route kept its counter")
+
+		rec = call(srv, http.MethodDelete, AdminPrefix+"/sequences?endpoint=GET%20/jobs/%7Bid%7D&key=4", "")
+		Expect(rec.Body.String()).To(MatchJSON(`{"reset":1}`))
+		Expect(statuses(srv, "/jobs/4", 1)).To(Equal([]int{202}))
+		Expect(statuses(srv, "/jobs/42", 1)).To(Equal([]int{200}), "key sharing a prefix kept its counter")
+		Expect(statuses(srv, "/jobs/4x", 1)).To(Equal([]int{200}))
+	})
+
+	It("only sequences through the variants whose conditions match", func() {
+		ep := config.Endpoint{
+			Method:   "GET",
+			Path:     "/flaky",
+			Sequence: &config.SequenceSpec{Mode: "cycle"},
+			Responses: []config.ResponseVariant{
+				{Status: 200, Body: "ok"},
+				{Status: 200, Body: "ok"},
+				{Status: 503, Body: "down"},
+				{Status: 418, Body: "debug", When: &config.WhenClause{Header: map[string]config.Matcher{"X-Debug": config.Eq("1")}}},
+			},
+		}
+		srv := &Server{log: discardLogger(), seq: newSequences()}
+
+		var got []int
+		for range 6 {
+			req := httptest.NewRequest(http.MethodGet, "/flaky", nil)
+			got = append(got, srv.selectVariant(ep, req, render.Data{}).Status)
+		}
+		Expect(got).To(Equal([]int{200, 200, 503, 200, 200, 503}))
+
+		req := httptest.NewRequest(http.MethodGet, "/flaky", nil)
+		req.Header.Set("X-Debug", "1")
+		Expect(srv.selectVariant(ep, req, render.Data{}).Status).To(Equal(418))
+	})
+})
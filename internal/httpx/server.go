@@ -6,27 +6,55 @@ package httpx
 import (
 	"context"
 	"log/slog"
+	"maps"
+	"math/rand/v2"
 	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
+	"sync/atomic"
 
 	"github.com/Bl4cky99/mocker/internal/auth"
 	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/fake"
+	"github.com/Bl4cky99/mocker/internal/oauth"
+	"github.com/Bl4cky99/mocker/internal/openapi"
 	"github.com/Bl4cky99/mocker/internal/render"
 	"github.com/Bl4cky99/mocker/internal/validate"
 	"github.com/santhosh-tekuri/jsonschema/v6"
 )
 
 type Server struct {
-	cfg        *config.Config
-	log        *slog.Logger
-	authMode   string
-	authProv   auth.Provider
+	// config being built; Config returns the one in service
+	cfg      *config.Config
+	log      *slog.Logger
+	authMode string
+	authProv auth.Provider
+	// further providers endpoints can select by type
+	authProvs  map[string]auth.Provider
 	handler    http.Handler
 	httpSrv    *http.Server
 	validators map[string]*validate.JSONSchemaValidator
 	renderer   *render.Renderer
+	seq        *sequences
+	scn        *scenarios
+	rnd        *lockedRand
+	fake       *fake.Generator
+	res        *resourceSet
+	resources  map[string]*resourceEntry
+	proxy      http.Handler
+	spec       []*openapi.Route
+	scnInit    map[string]string
+	oauth      *oauth.Issuer
+	active     atomic.Pointer[live]
+}
+
+// live is the router in service together with the config it was built
+// from, swapped as one by Reload.
+type live struct {
+	cfg    *config.Config
+	router http.Handler
 }
 
 type Option func(*Server)
@@ -44,25 +72,130 @@ func WithAuth(p auth.Provider, mode string) Option {
 	}
 }
 
+// WithAuthProvider registers a provider that endpoints with an auth
+// override of type mode use.
+func WithAuthProvider(mode string, p auth.Provider) Option {
+	return func(s *Server) {
+		provs := maps.Clone(s.authProvs)
+		if provs == nil {
+			provs = map[string]auth.Provider{}
+		}
+		provs[mode] = p
+		s.authProvs = provs
+	}
+}
+
 func WithRenderer(r *render.Renderer) Option {
 	return func(s *Server) {
 		s.renderer = r
 	}
 }
 
+// WithScenarioStates sets the initial state of the named scenarios,
+// overriding their configured initial state.
+func WithScenarioStates(states map[string]string) Option {
+	return func(s *Server) {
+		s.scnInit = states
+	}
+}
+
 func New(ctx context.Context, cfg *config.Config, opts ...Option) (*Server, error) {
-	s := &Server{cfg: cfg, log: slog.New(slog.NewTextHandler(os.Stdout, nil))}
+	s := &Server{
+		cfg:  cfg,
+		log:  slog.New(slog.NewTextHandler(os.Stdout, nil)),
+		seq:  newSequences(),
+		scn:  newScenarios(),
+		rnd:  newLockedRand(rand.Uint64()),
+		fake: fake.NewRandom(),
+		res:  newResourceSet(),
+	}
 	for _, o := range opts {
 		o(s)
 	}
 
-	s.validators = make(map[string]*validate.JSONSchemaValidator)
-	for _, ep := range cfg.Endpoints {
-		if ep.Validate == nil || ep.Validate.SchemaFile == "" {
-			continue
+	s.scn.configure(cfg.Scenarios)
+	for _, name := range slices.Sorted(maps.Keys(s.scnInit)) {
+		if err := s.scn.set(name, s.scnInit[name]); err != nil {
+			return nil, err
 		}
+	}
 
-		abs, _ := filepath.Abs(ep.Validate.SchemaFile)
+	router, err := s.build()
+	if err != nil {
+		return nil, err
+	}
+	s.res.swap(s.resources)
+	if s.oauth != nil && cfg.OAuth != nil {
+		s.oauth.Configure(*cfg.OAuth)
+	}
+
+	s.active.Store(&live{cfg: cfg, router: router})
+	s.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.active.Load().router.ServeHTTP(w, r)
+	})
+	s.httpSrv = &http.Server{
+		Addr:        cfg.Server.Addr,
+		Handler:     s.handler,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	return s, nil
+}
+
+// Reload compiles a new router for cfg and swaps it in atomically. In-flight
+// requests finish on the previous router; on error the old one stays active.
+// Endpoint auth providers are not carried over, pass them again in opts.
+func (s *Server) Reload(cfg *config.Config, opts ...Option) error {
+	ns := &Server{
+		cfg:      cfg,
+		log:      s.log,
+		authMode: s.authMode,
+		authProv: s.authProv,
+		renderer: s.renderer,
+		seq:      s.seq,
+		scn:      s.scn,
+		rnd:      s.rnd,
+		fake:     s.fake,
+		res:      s.res,
+		oauth:    s.oauth,
+	}
+	for _, o := range opts {
+		o(ns)
+	}
+
+	router, err := ns.build()
+	if err != nil {
+		return err
+	}
+
+	ns.scn.configure(cfg.Scenarios)
+	ns.res.swap(ns.resources)
+	if ns.oauth != nil && cfg.OAuth != nil {
+		ns.oauth.Configure(*cfg.OAuth)
+	}
+	s.authMode, s.authProv, s.authProvs = ns.authMode, ns.authProv, ns.authProvs
+	s.oauth = ns.oauth
+
+	s.active.Store(&live{cfg: cfg, router: router})
+	return nil
+}
+
+func (s *Server) build() (http.Handler, error) {
+	var schemas []string
+	for _, ep := range s.cfg.Endpoints {
+		if ep.Validate != nil && ep.Validate.SchemaFile != "" {
+			schemas = append(schemas, ep.Validate.SchemaFile)
+		}
+	}
+	for _, res := range s.cfg.Resources {
+		if res.SchemaFile != "" {
+			schemas = append(schemas, res.SchemaFile)
+		}
+	}
+
+	s.validators = make(map[string]*validate.JSONSchemaValidator)
+	for _, file := range schemas {
+		abs, _ := filepath.Abs(file)
 		if s.validators[abs] != nil {
 			continue
 		}
@@ -79,22 +212,64 @@ func New(ctx context.Context, cfg *config.Config, opts ...Option) (*Server, erro
 		s.validators[abs] = v
 	}
 
-	s.handler = buildRouter(s)
-	s.httpSrv = &http.Server{
-		Addr:        cfg.Server.Addr,
-		Handler:     s.handler,
-		BaseContext: func(net.Listener) context.Context { return ctx },
+	if err := s.compileTemplates(); err != nil {
+		return nil, err
 	}
 
-	return s, nil
+	s.proxy = nil
+	if pc := s.cfg.Server.FallbackProxy; pc != nil {
+		p, err := newProxy(pc, s.cfg.Server.BasePath, s.log)
+		if err != nil {
+			return nil, err
+		}
+		s.proxy = p
+	}
+
+	// the issuer keeps its key across reloads so issued tokens stay valid
+	if s.cfg.OAuth != nil && s.oauth == nil {
+		iss, err := oauth.New(*s.cfg.OAuth)
+		if err != nil {
+			return nil, err
+		}
+		s.oauth = iss
+	}
+
+	if err := s.loadSpec(); err != nil {
+		return nil, err
+	}
+
+	resources, err := s.res.prepare(s.cfg.Resources)
+	if err != nil {
+		return nil, err
+	}
+	s.resources = resources
+
+	return buildRouter(s), nil
+}
+
+// templates returns the configured renderer or a default one, so features
+// that always need templating (e.g. sequence keys) work without WithRenderer.
+func (s *Server) templates() *render.Renderer {
+	if s.renderer == nil {
+		return defaultRenderer
+	}
+	return s.renderer
 }
 
+var defaultRenderer = render.New()
+
 func (s *Server) Handler() http.Handler {
 	return s.handler
 }
 
+// Config returns the config of the router in service.
+func (s *Server) Config() *config.Config {
+	return s.active.Load().cfg
+}
+
 func (s *Server) ListenAndServe() error {
-	s.log.Info("mocker running", "addr", s.cfg.Server.Addr, "basePath", s.cfg.Server.BasePath)
+	cfg := s.Config()
+	s.log.Info("mocker running", "addr", cfg.Server.Addr, "basePath", cfg.Server.BasePath)
 	return s.httpSrv.ListenAndServe()
 }
 
@@ -6,6 +6,7 @@ package httpx
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
@@ -21,6 +22,7 @@ import (
 
 	"github.com/Bl4cky99/mocker/internal/auth"
 	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/render"
 	"github.com/Bl4cky99/mocker/internal/validate"
 )
 
@@ -57,6 +59,18 @@ var _ = Describe("Server", func() {
 		Expect(resp.Header().Get("X-Request-ID")).NotTo(BeEmpty())
 	})
 
+	It("serves the OpenAPI description of the active config", func() {
+		cfg := mustLoad(filepath.Join("testdata", "ok.basic.yaml"))
+		s, _ := New(context.Background(), cfg, WithLogger(discardLogger()))
+		resp := httptest.NewRecorder()
+
+		s.Handler().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, AdminPrefix+"/openapi.json", nil))
+
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Body.String()).To(ContainSubstring(`"openapi":"3.1.0"`))
+		Expect(resp.Body.String()).To(ContainSubstring(`"/healthz"`))
+	})
+
 	Describe("New with options", func() {
 		It("applies logger, auth, and schema options", func() {
 			tmp := GinkgoT().TempDir()
@@ -97,6 +111,79 @@ var _ = Describe("Server", func() {
 		})
 	})
 
+	Describe("template precompilation", func() {
+		cfgWith := func(rv config.ResponseVariant) *config.Config {
+			return &config.Config{
+				Server: config.ServerConfig{BasePath: "/"},
+				Endpoints: []config.Endpoint{
+					{Method: "GET", Path: "/ok", Responses: []config.ResponseVariant{{Status: 200, Body: "ok"}}},
+					{Method: "GET", Path: "/items/{id}", Responses: []config.ResponseVariant{{Status: 200, Body: "ok"}, rv}},
+				},
+			}
+		}
+
+		It("fails New with the endpoint and response index of a broken template", func() {
+			_, err := New(context.Background(), cfgWith(config.ResponseVariant{Status: 200, Body: "{{ .Path.id"}),
+				WithLogger(discardLogger()), WithRenderer(render.New()))
+			Expect(err).To(MatchError(ErrTemplate))
+			Expect(err.Error()).To(ContainSubstring("endpoints[1].responses[1] (GET /items/{id})"))
+
+			path := filepath.Join(GinkgoT().TempDir(), "broken.tmpl")
+			Expect(os.WriteFile(path, []byte("{{ end }}"), 0o600)).To(Succeed())
+			_, err = New(context.Background(), cfgWith(config.ResponseVariant{Status: 200, BodyFile: path}),
+				WithLogger(discardLogger()), WithRenderer(render.New()))
+			Expect(err).To(MatchError(ErrTemplate))
+		})
+
+		It("skips bodies served verbatim", func() {
+			_, err := New(context.Background(), cfgWith(config.ResponseVariant{Status: 200, Body: "{{ .Path.id", Engine: "none"}),
+				WithLogger(discardLogger()), WithRenderer(render.New()))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("Reload", func() {
+		It("swaps the active router and keeps the old one on error", func() {
+			cfg := mustLoad(filepath.Join("testdata", "ok.basic.yaml"))
+			s, err := New(context.Background(), cfg, WithLogger(discardLogger()))
+			Expect(err).NotTo(HaveOccurred())
+
+			next := &config.Config{
+				Server: config.ServerConfig{BasePath: "/"},
+				Endpoints: []config.Endpoint{{
+					Method:    "GET",
+					Path:      "/ready",
+					Responses: []config.ResponseVariant{{Status: http.StatusAccepted, Body: "ready"}},
+				}},
+			}
+			Expect(s.Reload(next)).To(Succeed())
+			Expect(s.Config()).To(BeIdenticalTo(next))
+
+			resp := httptest.NewRecorder()
+			s.Handler().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ready", nil))
+			Expect(resp.Code).To(Equal(http.StatusAccepted))
+
+			resp = httptest.NewRecorder()
+			s.Handler().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			Expect(resp.Code).To(Equal(http.StatusNotFound))
+
+			broken := &config.Config{
+				Server: config.ServerConfig{BasePath: "/"},
+				Endpoints: []config.Endpoint{{
+					Method:    "POST",
+					Path:      "/x",
+					Validate:  &config.ValidateSpec{SchemaFile: filepath.Join(GinkgoT().TempDir(), "missing.json")},
+					Responses: []config.ResponseVariant{{Status: 200, Body: "x"}},
+				}},
+			}
+			Expect(s.Reload(broken)).NotTo(Succeed())
+
+			resp = httptest.NewRecorder()
+			s.Handler().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/ready", nil))
+			Expect(resp.Code).To(Equal(http.StatusAccepted))
+		})
+	})
+
 	Describe("endpointHandler", func() {
 		It("writes status, body, and response headers", func() {
 			srv := &Server{
@@ -149,6 +236,77 @@ var _ = Describe("Server", func() {
 			Expect(time.Since(start)).To(BeNumerically("<", 30*time.Millisecond))
 			Expect(resp.Body.Len()).To(Equal(0))
 		})
+
+		It("exposes the request body to templates", func() {
+			srv := &Server{cfg: &config.Config{Server: config.ServerConfig{}}, log: discardLogger(), renderer: render.New()}
+			ep := config.Endpoint{Responses: []config.ResponseVariant{{
+				Status: http.StatusOK,
+				Body:   `{{ .Body.a }}|{{ len .RawBody }}`,
+			}}}
+
+			h := endpointHandler(srv, ep)
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"a":1}`))
+			req.Header.Set("Content-Type", "application/json")
+			h.ServeHTTP(resp, req)
+
+			Expect(resp.Code).To(Equal(http.StatusOK))
+			Expect(resp.Body.String()).To(Equal("1|7"))
+		})
+
+		It("generates the body from bodySchema, reproducibly under a seed", func() {
+			schema := filepath.Join(GinkgoT().TempDir(), "user.json")
+			Expect(os.WriteFile(schema, []byte(`{"type":"object","required":["id","email"],"properties":{"id":{"type":"string","format":"uuid"},"email":{"type":"string","format":"email"}}}`), 0o600)).To(Succeed())
+			ep := config.Endpoint{Responses: []config.ResponseVariant{{Status: http.StatusOK, BodySchema: schema}}}
+
+			serve := func() []byte {
+				srv := &Server{cfg: &config.Config{Server: config.ServerConfig{}}, log: discardLogger(), renderer: render.New(render.WithSeed(42))}
+				resp := httptest.NewRecorder()
+				endpointHandler(srv, ep).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/", nil))
+				Expect(resp.Code).To(Equal(http.StatusOK))
+				return resp.Body.Bytes()
+			}
+
+			body := serve()
+			Expect(serve()).To(Equal(body))
+
+			var out map[string]string
+			Expect(json.Unmarshal(body, &out)).To(Succeed())
+			Expect(out["email"]).To(ContainSubstring("@"))
+			Expect(out["id"]).To(HaveLen(36))
+		})
+
+		It("renders JSON bodies without HTML escaping and HTML bodies with it", func() {
+			srv := &Server{
+				cfg: &config.Config{Server: config.ServerConfig{DefaultHeaders: map[string]string{"Content-Type": "application/json"}}},
+				log: discardLogger(), renderer: render.New(),
+			}
+			ep := config.Endpoint{Responses: []config.ResponseVariant{
+				{Status: http.StatusOK, Body: `{"q":"{{ .Query.q }}"}`, When: &config.WhenClause{Query: map[string]config.Matcher{"as": config.Eq("json")}}},
+				{Status: http.StatusOK, Body: `<p>{{ .Query.q }}</p>`, Headers: map[string]string{"Content-Type": "text/html"}, When: &config.WhenClause{Query: map[string]config.Matcher{"as": config.Eq("html")}}},
+				{Status: http.StatusOK, Body: `{{ .Query.q }}`, Engine: "none"},
+			}}
+
+			get := func(as string) string {
+				resp := httptest.NewRecorder()
+				endpointHandler(srv, ep).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/?q=a%3Cb%2Bc&as="+as, nil))
+				return resp.Body.String()
+			}
+			Expect(get("json")).To(Equal(`{"q":"a<b+c"}`))
+			Expect(get("html")).To(Equal(`<p>a&lt;b&#43;c</p>`))
+			Expect(get("raw")).To(Equal(`{{ .Query.q }}`))
+		})
+
+		It("returns 413 when the body exceeds maxBodyBytes", func() {
+			srv := &Server{cfg: &config.Config{Server: config.ServerConfig{}}, log: discardLogger()}
+			ep := config.Endpoint{MaxBodyBytes: 4, Responses: []config.ResponseVariant{{Status: http.StatusOK, Body: "ok"}}}
+
+			h := endpointHandler(srv, ep)
+			resp := httptest.NewRecorder()
+			h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("too large")))
+
+			Expect(resp.Code).To(Equal(http.StatusRequestEntityTooLarge))
+		})
 	})
 
 	Describe("validateBody middleware", func() {
@@ -168,7 +326,7 @@ var _ = Describe("Server", func() {
 				w.WriteHeader(http.StatusCreated)
 			})
 			_ = nextCalled
-			ctHandler = validateBody("application/json", validator)(next)
+			ctHandler = validateBody("application/json", validator, 0)(next)
 		})
 
 		It("passes a valid JSON body through", func() {
@@ -197,11 +355,228 @@ var _ = Describe("Server", func() {
 
 		It("returns the original handler unchanged when schema is nil", func() {
 			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
-			noop := validateBody("", nil)
+			noop := validateBody("", nil, 0)
 			Expect(reflect.ValueOf(noop(next)).Pointer()).To(Equal(reflect.ValueOf(next).Pointer()))
 		})
 	})
 
+	Describe("per-endpoint auth", func() {
+		It("applies endpoint overrides and allow lists", func() {
+			ok := []config.ResponseVariant{{Status: 200, Body: "ok"}}
+			cfg := &config.Config{
+				Server: config.ServerConfig{BasePath: "/"},
+				Auth: config.AuthConfig{
+					Type:  "token",
+					Token: &config.TokenAuthConfig{Header: "Authorization", Prefix: "Bearer ", Tokens: []string{"t1", "t2"}},
+					Basic: &config.BasicAuthConfig{Users: []config.BasicUser{{Username: "alice", Password: "a"}, {Username: "bob", Password: "b"}}},
+				},
+				Endpoints: []config.Endpoint{
+					{Method: "GET", Path: "/healthz", Auth: &config.EndpointAuth{Type: "none"}, Responses: ok},
+					{Method: "GET", Path: "/private", Responses: ok},
+					{Method: "GET", Path: "/admin", Auth: &config.EndpointAuth{Type: "inherit", Tokens: []string{"t2"}}, Responses: ok},
+					{Method: "GET", Path: "/users", Auth: &config.EndpointAuth{Type: "basic", Users: []string{"alice"}}, Responses: ok},
+				},
+			}
+			cfg.ApplyDefaults()
+			Expect(cfg.Validate()).To(Succeed())
+
+			srv, err := New(context.Background(), cfg, WithLogger(discardLogger()),
+				WithAuth(auth.NewTokenAuth("Authorization", "Bearer ", []string{"t1", "t2"}), "token"),
+				WithAuthProvider("basic", auth.NewBasicAuth(map[string]string{"alice": "a", "bob": "b"}, "mocker")),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			call := func(path string, prep func(*http.Request)) *httptest.ResponseRecorder {
+				req := httptest.NewRequest(http.MethodGet, path, nil)
+				if prep != nil {
+					prep(req)
+				}
+				rec := httptest.NewRecorder()
+				srv.Handler().ServeHTTP(rec, req)
+				return rec
+			}
+			bearer := func(t string) func(*http.Request) {
+				return func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+t) }
+			}
+			basic := func(u, p string) func(*http.Request) {
+				return func(r *http.Request) { r.SetBasicAuth(u, p) }
+			}
+
+			Expect(call("/healthz", nil).Code).To(Equal(http.StatusOK))
+			Expect(call("/private", nil).Code).To(Equal(http.StatusUnauthorized))
+			Expect(call("/private", bearer("t1")).Code).To(Equal(http.StatusOK))
+
+			Expect(call("/admin", bearer("t1")).Code).To(Equal(http.StatusUnauthorized))
+			Expect(call("/admin", bearer("t2")).Code).To(Equal(http.StatusOK))
+
+			rec := call("/users", bearer("t1"))
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+			Expect(rec.Header().Get("WWW-Authenticate")).To(ContainSubstring("Basic"))
+			Expect(call("/users", basic("bob", "b")).Code).To(Equal(http.StatusUnauthorized))
+			Expect(call("/users", basic("alice", "a")).Code).To(Equal(http.StatusOK))
+		})
+	})
+
+	Describe("combined auth providers", func() {
+		It("accepts any provider, requires all on override and aggregates challenges", func() {
+			ok := []config.ResponseVariant{{Status: 200, Body: "ok"}}
+			cfg := &config.Config{
+				Server: config.ServerConfig{BasePath: "/"},
+				Auth: config.AuthConfig{
+					Providers: []string{"token", "basic", "jwt"},
+					Token:     &config.TokenAuthConfig{Header: "X-API-Key", Tokens: []string{"k1"}},
+					Basic:     &config.BasicAuthConfig{Users: []config.BasicUser{{Username: "alice", Password: "a"}}},
+					JWT:       &config.JWTAuthConfig{Secret: "s3cret"},
+				},
+				Endpoints: []config.Endpoint{
+					{Method: "GET", Path: "/users", Responses: ok},
+					{Method: "DELETE", Path: "/users", Auth: &config.EndpointAuth{Type: "all"}, Responses: ok},
+				},
+			}
+			cfg.ApplyDefaults()
+			Expect(cfg.Validate()).To(Succeed())
+
+			ks, err := auth.LoadKeySet("s3cret", nil, "")
+			Expect(err).NotTo(HaveOccurred())
+			srv, err := New(context.Background(), cfg, WithLogger(discardLogger()),
+				WithAuth(nil, "any"),
+				WithAuthProvider("token", auth.NewTokenAuth("X-API-Key", "", []string{"k1"})),
+				WithAuthProvider("basic", auth.NewBasicAuth(map[string]string{"alice": "a"}, "mocker")),
+				WithAuthProvider("jwt", auth.NewJWTAuth(ks)),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			call := func(method string, prep func(*http.Request)) *httptest.ResponseRecorder {
+				req := httptest.NewRequest(method, "/users", nil)
+				prep(req)
+				rec := httptest.NewRecorder()
+				srv.Handler().ServeHTTP(rec, req)
+				return rec
+			}
+			key := func(r *http.Request) { r.Header.Set("X-API-Key", "k1") }
+			basic := func(r *http.Request) { r.SetBasicAuth("alice", "a") }
+
+			Expect(call(http.MethodGet, key).Code).To(Equal(http.StatusOK))
+			Expect(call(http.MethodGet, basic).Code).To(Equal(http.StatusOK))
+
+			rec := call(http.MethodGet, func(*http.Request) {})
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+			Expect(rec.Header().Values("WWW-Authenticate")).To(Equal([]string{`Basic realm="mocker"`, `Bearer realm="mocker"`}))
+
+			rec = call(http.MethodGet, func(r *http.Request) { r.Header.Set("Authorization", "Bearer nope") })
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+			Expect(rec.Header().Values("WWW-Authenticate")).To(ContainElement(ContainSubstring(`error="invalid_token"`)))
+
+			Expect(call(http.MethodDelete, key).Code).To(Equal(http.StatusUnauthorized))
+			Expect(call(http.MethodDelete, func(r *http.Request) { key(r); basic(r) }).Code).To(Equal(http.StatusUnauthorized),
+				"jwt is missing")
+
+			Expect(srv.Reload(cfg, WithAuth(nil, "any"), WithAuthProvider("token", auth.NewTokenAuth("X-API-Key", "", []string{"k1"})))).To(Succeed())
+			Expect(call(http.MethodGet, key).Code).To(Equal(http.StatusOK))
+			Expect(call(http.MethodGet, basic).Code).To(Equal(http.StatusUnauthorized), "basic was not passed to the reload")
+		})
+	})
+
+	Describe("endpoint requirements", func() {
+		It("answers 401 without credentials and 403 without the required grants", func() {
+			ok := []config.ResponseVariant{{Status: 200, Body: "ok"}}
+			cfg := &config.Config{
+				Server: config.ServerConfig{BasePath: "/"},
+				Auth: config.AuthConfig{
+					Type:          "token",
+					Token:         &config.TokenAuthConfig{Header: "Authorization", Prefix: "Bearer ", Tokens: []string{"t1", "t2"}},
+					ForbiddenBody: `{"error":"forbidden"}`,
+				},
+				Endpoints: []config.Endpoint{
+					{Method: "GET", Path: "/users", Requires: &config.Requirement{Scopes: []string{"users:read"}}, Responses: ok},
+					{Method: "DELETE", Path: "/users", Requires: &config.Requirement{Roles: []string{"admin"}, Body: "admins only"}, Responses: ok},
+				},
+			}
+			cfg.ApplyDefaults()
+			Expect(cfg.Validate()).To(Succeed())
+
+			srv, err := New(context.Background(), cfg, WithLogger(discardLogger()),
+				WithAuth(auth.NewTokenAuth("Authorization", "Bearer ", []string{"t1", "t2"}, auth.WithTokenGrants(map[string]auth.Grants{
+					"t1": {Roles: []string{"admin"}, Scopes: []string{"users:read"}},
+				})), "token"),
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			call := func(method, token string) *httptest.ResponseRecorder {
+				req := httptest.NewRequest(method, "/users", nil)
+				if token != "" {
+					req.Header.Set("Authorization", "Bearer "+token)
+				}
+				rec := httptest.NewRecorder()
+				srv.Handler().ServeHTTP(rec, req)
+				return rec
+			}
+
+			Expect(call(http.MethodGet, "").Code).To(Equal(http.StatusUnauthorized))
+			Expect(call(http.MethodGet, "t1").Code).To(Equal(http.StatusOK))
+			Expect(call(http.MethodDelete, "t1").Code).To(Equal(http.StatusOK))
+
+			rec := call(http.MethodGet, "t2")
+			Expect(rec.Code).To(Equal(http.StatusForbidden))
+			Expect(rec.Header().Get("Content-Type")).To(Equal("application/json"))
+			Expect(rec.Body.String()).To(Equal(`{"error":"forbidden"}`))
+
+			rec = call(http.MethodDelete, "t2")
+			Expect(rec.Code).To(Equal(http.StatusForbidden))
+			Expect(rec.Header().Get("Content-Type")).To(HavePrefix("text/plain"))
+			Expect(rec.Body.String()).To(Equal("admins only"))
+		})
+	})
+
+	Describe("built-in oauth issuer", func() {
+		It("mounts the issuer outside auth and accepts its tokens on jwt routes across reloads", func() {
+			cfg := &config.Config{
+				Server: config.ServerConfig{BasePath: "/api"},
+				Auth:   config.AuthConfig{Type: "jwt"},
+				OAuth: &config.OAuthConfig{
+					Clients: []config.OAuthClient{{ClientID: "svc", ClientSecret: "pw", GrantTypes: []string{"client_credentials"}}},
+				},
+				Endpoints: []config.Endpoint{{Method: "GET", Path: "/me", Responses: []config.ResponseVariant{{Status: 200, Body: "ok"}}}},
+			}
+			cfg.ApplyDefaults()
+			Expect(cfg.Validate()).To(Succeed())
+
+			prov := auth.NewJWTAuth(&auth.KeySet{})
+			srv, err := New(context.Background(), cfg, WithLogger(discardLogger()), WithAuth(prov, "jwt"))
+			Expect(err).NotTo(HaveOccurred())
+
+			fetchToken := func() string {
+				req := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader("grant_type=client_credentials"))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				req.SetBasicAuth("svc", "pw")
+				rec := httptest.NewRecorder()
+				srv.Handler().ServeHTTP(rec, req)
+				Expect(rec.Code).To(Equal(http.StatusOK))
+				var body struct {
+					AccessToken string `json:"access_token"`
+				}
+				Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+				return body.AccessToken
+			}
+			call := func(token string) int {
+				req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+				if token != "" {
+					req.Header.Set("Authorization", "Bearer "+token)
+				}
+				rec := httptest.NewRecorder()
+				srv.Handler().ServeHTTP(rec, req)
+				return rec.Code
+			}
+
+			token := fetchToken()
+			Expect(call("")).To(Equal(http.StatusUnauthorized))
+			Expect(call(token)).To(Equal(http.StatusOK))
+
+			Expect(srv.Reload(cfg, WithAuth(auth.NewJWTAuth(&auth.KeySet{}), "jwt"))).To(Succeed())
+			Expect(call(token)).To(Equal(http.StatusOK), "tokens survive reloads")
+		})
+	})
+
 	Describe("requireAuth middleware", func() {
 		It("passes the principal into context on success", func() {
 			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -231,6 +606,20 @@ var _ = Describe("Server", func() {
 			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
 			Expect(rec.Header().Get("WWW-Authenticate")).To(ContainSubstring("Basic"))
 		})
+
+		It("returns a Bearer challenge for jwt auth and flags rejected tokens", func() {
+			noop := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+
+			rec := httptest.NewRecorder()
+			requireAuth(stubProvider{ok: false}, "jwt")(noop).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+			Expect(rec.Header().Get("WWW-Authenticate")).To(Equal(`Bearer realm="mocker"`))
+
+			rec = httptest.NewRecorder()
+			requireAuth(stubProvider{err: auth.ErrInvalidToken}, "jwt")(noop).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+			Expect(rec.Header().Get("WWW-Authenticate")).To(ContainSubstring(`error="invalid_token"`))
+		})
 	})
 
 	Describe("skipAuthForOPTIONS middleware", func() {
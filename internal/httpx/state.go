@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/Bl4cky99/mocker/internal/state"
+)
+
+// Snapshot captures the runtime state: sequence counters, scenario states
+// and resource items.
+func (s *Server) Snapshot() state.Snapshot {
+	snap := state.Snapshot{
+		Version:   state.Version,
+		SavedAt:   time.Now().UTC(),
+		Sequences: s.seq.snapshot(),
+		Scenarios: s.scn.snapshot(),
+		Resources: make(map[string][]map[string]any),
+	}
+	for p, st := range s.res.stores() {
+		snap.Resources[p] = st.Items()
+	}
+	return snap
+}
+
+// Restore applies a snapshot. Entries that no longer fit the config, such as
+// unknown scenarios or resources, are skipped and reported in the returned
+// error; everything else is still applied.
+func (s *Server) Restore(snap state.Snapshot) error {
+	var errs []error
+
+	s.seq.restore(snap.Sequences)
+
+	for _, name := range slices.Sorted(maps.Keys(snap.Scenarios)) {
+		if err := s.scn.set(name, snap.Scenarios[name]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	stores := s.res.stores()
+	for _, p := range slices.Sorted(maps.Keys(snap.Resources)) {
+		st, ok := stores[p]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrUnknownResource, p))
+			continue
+		}
+		if err := st.Restore(snap.Resources[p]); err != nil {
+			errs = append(errs, fmt.Errorf("resource %s: %w", p, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/state"
+)
+
+var _ = Describe("runtime state", func() {
+	newStatefulServer := func() *Server {
+		cfg := &config.Config{
+			Server:    config.ServerConfig{BasePath: "/"},
+			Scenarios: []config.Scenario{{Name: "orders", Initial: "empty", States: []string{"empty", "created"}}},
+			Resources: []config.Resource{{Path: "/users"}},
+			Endpoints: []config.Endpoint{{
+				Method:    "POST",
+				Path:      "/orders",
+				Sequence:  &config.SequenceSpec{},
+				Responses: []config.ResponseVariant{{Status: 201, Body: "created", SetState: "created"}},
+			}},
+		}
+		cfg.ApplyDefaults()
+		srv, err := New(context.Background(), cfg, WithLogger(discardLogger()))
+		Expect(err).NotTo(HaveOccurred())
+		return srv
+	}
+
+	It("restores a snapshot into a fresh server", func() {
+		src := newStatefulServer()
+		call(src, "POST", "/orders", "")
+		call(src, "POST", "/users", `{"name":"alice"}`)
+
+		b, err := json.Marshal(src.Snapshot())
+		Expect(err).NotTo(HaveOccurred())
+		snap, err := state.Decode(strings.NewReader(string(b)))
+		Expect(err).NotTo(HaveOccurred())
+
+		dst := newStatefulServer()
+		Expect(dst.Restore(snap)).To(Succeed())
+		Expect(dst.Snapshot().Scenarios).To(HaveKeyWithValue("orders", "created"))
+		Expect(dst.Snapshot().Sequences).To(HaveKeyWithValue("POST /orders", 1))
+		Expect(call(dst, "GET", "/users/1", "").Body.String()).To(MatchJSON(`{"id":1,"name":"alice"}`))
+	})
+
+	It("skips entries that no longer match the config", func() {
+		srv := newStatefulServer()
+		err := srv.Restore(state.Snapshot{
+			Scenarios: map[string]string{"orders": "created", "carts": "full"},
+			Resources: map[string][]map[string]any{"/posts": {{"id": "1"}}},
+		})
+		Expect(err).To(MatchError(ErrUnknownScenario))
+		Expect(err).To(MatchError(ErrUnknownResource))
+		Expect(srv.Snapshot().Scenarios).To(HaveKeyWithValue("orders", "created"))
+	})
+
+	It("exports and imports via the admin endpoint", func() {
+		srv := newStatefulServer()
+		rec := call(srv, "PUT", AdminPrefix+"/state", `{"version":1,"scenarios":{"orders":"created","x":"y"}}`)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring("unknown scenario"))
+
+		rec = call(srv, "GET", AdminPrefix+"/state", "")
+		Expect(rec.Body.String()).To(ContainSubstring(`"orders":"created"`))
+
+		Expect(call(srv, "PUT", AdminPrefix+"/state", `{"version":7}`).Code).To(Equal(http.StatusBadRequest))
+	})
+})
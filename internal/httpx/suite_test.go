@@ -4,6 +4,9 @@
 package httpx
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -14,3 +17,22 @@ func TestHttpx(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Httpx Suite")
 }
+
+// call serves one request on srv. A non-empty body is sent as JSON; prep
+// can adjust the request, e.g. with header.
+func call(srv *Server, method, target, body string, prep ...func(*http.Request)) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for _, p := range prep {
+		p(req)
+	}
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func header(k, v string) func(*http.Request) {
+	return func(r *http.Request) { r.Header.Set(k, v) }
+}
@@ -7,44 +7,98 @@ import (
 	"net/http"
 
 	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/go-chi/chi/v5"
 )
 
 func pickVariant(ep config.Endpoint, r *http.Request) config.ResponseVariant {
-	var fallback *config.ResponseVariant
+	return *candidates(ep, r)[0]
+}
+
+// candidates returns the variants eligible for r: every variant whose when
+// clause matches, otherwise the variants without conditions, otherwise the
+// first variant. The result is never empty for a non-empty endpoint.
+func candidates(ep config.Endpoint, r *http.Request) []*config.ResponseVariant {
+	var matched, fallbacks []*config.ResponseVariant
 
 	for i := range ep.Responses {
 		v := &ep.Responses[i]
-		if v.When == nil || (len(v.When.Query) == 0 && len(v.When.Header) == 0) {
-			if fallback == nil {
-				fallback = v
-			}
+		if v.When.IsEmpty() {
+			fallbacks = append(fallbacks, v)
 			continue
 		}
 		if whenMatches(r, v.When) {
-			return *v
+			matched = append(matched, v)
 		}
 	}
 
-	if fallback != nil {
-		return *fallback
+	switch {
+	case len(matched) > 0:
+		return matched
+	case len(fallbacks) > 0:
+		return fallbacks
+	default:
+		return []*config.ResponseVariant{&ep.Responses[0]}
 	}
-
-	return ep.Responses[0]
 }
 
 func whenMatches(r *http.Request, w *config.WhenClause) bool {
 	if len(w.Query) > 0 {
 		q := r.URL.Query()
-		for k, want := range w.Query {
-			if q.Get(k) != want {
+		for k, m := range w.Query {
+			_, present := q[k]
+			if !matchText(q.Get(k), present, m) {
 				return false
 			}
 		}
 	}
 
 	if len(w.Header) > 0 {
-		for k, want := range w.Header {
-			if r.Header.Get(k) != want {
+		for k, m := range w.Header {
+			present := len(r.Header.Values(k)) > 0
+			if !matchText(r.Header.Get(k), present, m) {
+				return false
+			}
+		}
+	}
+
+	if len(w.Path) > 0 {
+		rc := chi.RouteContext(r.Context())
+		for k, m := range w.Path {
+			val, present := routeParam(rc, k)
+			if !matchText(val, present, m) {
+				return false
+			}
+		}
+	}
+
+	if len(w.Cookie) > 0 {
+		for k, m := range w.Cookie {
+			val, present := "", false
+			if c, err := r.Cookie(k); err == nil {
+				val, present = c.Value, true
+			}
+			if !matchText(val, present, m) {
+				return false
+			}
+		}
+	}
+
+	if w.State != nil {
+		st, present := scenarioState(r)
+		if !matchText(st, present, *w.State) {
+			return false
+		}
+	}
+
+	if len(w.Body) > 0 {
+		doc := parsedBody(r)
+		for expr, m := range w.Body {
+			p, err := cachedPath(expr)
+			if err != nil {
+				return false
+			}
+			found := p.Find(doc)
+			if !matchValues(found, len(found) > 0, false, m) {
 				return false
 			}
 		}
@@ -52,3 +106,15 @@ func whenMatches(r *http.Request, w *config.WhenClause) bool {
 
 	return true
 }
+
+func routeParam(rc *chi.Context, key string) (string, bool) {
+	if rc == nil {
+		return "", false
+	}
+	for i := len(rc.URLParams.Keys) - 1; i >= 0; i-- {
+		if rc.URLParams.Keys[i] == key {
+			return rc.URLParams.Values[i], true
+		}
+	}
+	return "", false
+}
@@ -4,20 +4,24 @@
 package httpx
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/go-chi/chi/v5"
 )
 
 var _ = Describe("pickVariant", func() {
 	It("selects the variant whose query params match", func() {
 		ep := config.Endpoint{Responses: []config.ResponseVariant{
 			{Status: 200, Body: "default"},
-			{Status: 201, Body: "query", When: &config.WhenClause{Query: map[string]string{"foo": "bar"}}},
+			{Status: 201, Body: "query", When: &config.WhenClause{Query: map[string]config.Matcher{"foo": config.Eq("bar")}}},
 			{Status: 202, Body: "other"},
 		}}
 		req := httptest.NewRequest(http.MethodGet, "/test?foo=bar", nil)
@@ -30,7 +34,7 @@ var _ = Describe("pickVariant", func() {
 	It("selects the variant whose headers match", func() {
 		ep := config.Endpoint{Responses: []config.ResponseVariant{
 			{Status: 200, Body: "default"},
-			{Status: 202, Body: "header", When: &config.WhenClause{Header: map[string]string{"X-Test": "yes"}}},
+			{Status: 202, Body: "header", When: &config.WhenClause{Header: map[string]config.Matcher{"X-Test": config.Eq("yes")}}},
 		}}
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 		req.Header.Set("X-Test", "yes")
@@ -43,7 +47,7 @@ var _ = Describe("pickVariant", func() {
 	It("falls back to the first variant without a when clause", func() {
 		ep := config.Endpoint{Responses: []config.ResponseVariant{
 			{Status: 200, Body: "first", When: nil},
-			{Status: 201, Body: "second", When: &config.WhenClause{Query: map[string]string{"foo": "no"}}},
+			{Status: 201, Body: "second", When: &config.WhenClause{Query: map[string]config.Matcher{"foo": config.Eq("no")}}},
 		}}
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 
@@ -54,8 +58,8 @@ var _ = Describe("pickVariant", func() {
 
 	It("returns the first variant when nothing matches and there is no fallback", func() {
 		ep := config.Endpoint{Responses: []config.ResponseVariant{
-			{Status: 200, Body: "first", When: &config.WhenClause{Query: map[string]string{"a": "1"}}},
-			{Status: 201, Body: "second", When: &config.WhenClause{Header: map[string]string{"X": "y"}}},
+			{Status: 200, Body: "first", When: &config.WhenClause{Query: map[string]config.Matcher{"a": config.Eq("1")}}},
+			{Status: 201, Body: "second", When: &config.WhenClause{Header: map[string]config.Matcher{"X": config.Eq("y")}}},
 		}}
 		req := httptest.NewRequest(http.MethodGet, "/", nil)
 
@@ -65,13 +69,153 @@ var _ = Describe("pickVariant", func() {
 	})
 })
 
+func withBody(req *http.Request, contentType string) *http.Request {
+	req.Header.Set("Content-Type", contentType)
+	r, _, err := bufferBody(httptest.NewRecorder(), req, 0)
+	Expect(err).NotTo(HaveOccurred())
+	return r
+}
+
+var _ = Describe("pickVariant with body conditions", func() {
+	ep := config.Endpoint{Responses: []config.ResponseVariant{
+		{Status: 200, Body: "default"},
+		{Status: 202, Body: "refund", When: &config.WhenClause{Body: map[string]config.Matcher{
+			"$.type": {Equals: "refund"},
+		}}},
+		{Status: 201, Body: "big", When: &config.WhenClause{Body: map[string]config.Matcher{
+			"/amount": {Equals: 1000},
+		}}},
+	}}
+
+	It("selects by JSONPath equality", func() {
+		req := withBody(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"type":"refund"}`)), "application/json")
+		Expect(pickVariant(ep, req).Status).To(Equal(202))
+	})
+
+	It("compares numbers numerically via JSON Pointer", func() {
+		req := withBody(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"amount":1000.0}`)), "application/json")
+		Expect(pickVariant(ep, req).Status).To(Equal(201))
+	})
+
+	It("falls back when the body does not match or is not JSON", func() {
+		req := withBody(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"type":"charge"}`)), "application/json")
+		Expect(pickVariant(ep, req).Status).To(Equal(200))
+
+		req = withBody(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`type=refund`)), "text/plain")
+		Expect(pickVariant(ep, req).Status).To(Equal(200))
+	})
+
+	It("matches form fields", func() {
+		req := withBody(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`type=refund`)), "application/x-www-form-urlencoded")
+		Expect(pickVariant(ep, req).Status).To(Equal(202))
+	})
+})
+
+func withParams(req *http.Request, kv ...string) *http.Request {
+	rc := chi.NewRouteContext()
+	for i := 0; i+1 < len(kv); i += 2 {
+		rc.URLParams.Add(kv[i], kv[i+1])
+	}
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rc))
+}
+
+var _ = Describe("pickVariant with path and cookie conditions", func() {
+	ep := config.Endpoint{Path: "/users/{id}", Responses: []config.ResponseVariant{
+		{Status: 200, Body: "user"},
+		{Status: 404, Body: "missing", When: &config.WhenClause{Path: map[string]config.Matcher{"id": config.Eq("999")}}},
+		{Status: 403, Body: "admin only", When: &config.WhenClause{
+			Path:   map[string]config.Matcher{"id": {Regex: "^adm"}},
+			Cookie: map[string]config.Matcher{"role": {Not: &config.Matcher{Equals: "admin"}}},
+		}},
+	}}
+
+	It("matches chi path parameters", func() {
+		req := withParams(httptest.NewRequest(http.MethodGet, "/users/999", nil), "id", "999")
+		Expect(pickVariant(ep, req).Status).To(Equal(404))
+
+		req = withParams(httptest.NewRequest(http.MethodGet, "/users/1", nil), "id", "1")
+		Expect(pickVariant(ep, req).Status).To(Equal(200))
+	})
+
+	It("combines path and cookie conditions", func() {
+		req := withParams(httptest.NewRequest(http.MethodGet, "/users/admin", nil), "id", "admin")
+		Expect(pickVariant(ep, req).Status).To(Equal(403))
+
+		req = withParams(httptest.NewRequest(http.MethodGet, "/users/admin", nil), "id", "admin")
+		req.AddCookie(&http.Cookie{Name: "role", Value: "admin"})
+		Expect(pickVariant(ep, req).Status).To(Equal(200))
+	})
+
+	It("treats a missing cookie as absent", func() {
+		no := false
+		m := map[string]config.Matcher{"session": {Exists: &no}}
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		Expect(whenMatches(req, &config.WhenClause{Cookie: m})).To(BeTrue())
+
+		req.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+		Expect(whenMatches(req, &config.WhenClause{Cookie: m})).To(BeFalse())
+	})
+})
+
+var _ = Describe("matchValues", func() {
+	yes, no := true, false
+
+	DescribeTable("evaluates body operators",
+		func(found []any, m config.Matcher, want bool) {
+			Expect(matchValues(found, len(found) > 0, false, m)).To(Equal(want))
+		},
+		Entry("exists on present value", []any{"x"}, config.Matcher{Exists: &yes}, true),
+		Entry("exists on missing value", nil, config.Matcher{Exists: &yes}, false),
+		Entry("not exists on missing value", nil, config.Matcher{Exists: &no}, true),
+		Entry("equality on bool", []any{true}, config.Matcher{Equals: true}, true),
+		Entry("equality type mismatch", []any{"1"}, config.Matcher{Equals: 1}, false),
+		Entry("equality on object", []any{map[string]any{"a": json.Number("1")}}, config.Matcher{Equals: map[string]any{"a": 1}}, true),
+		Entry("regex on string", []any{"bob@corp.test"}, config.Matcher{Regex: `@corp\.test$`}, true),
+		Entry("regex on number", []any{json.Number("404")}, config.Matcher{Regex: `^4\d\d$`}, true),
+		Entry("regex any of wildcard results", []any{"a", "b"}, config.Matcher{Regex: `^b$`}, true),
+		Entry("regex without match", []any{"a"}, config.Matcher{Regex: `^b$`}, false),
+		Entry("contains array element", []any{[]any{"admin", "dev"}}, config.Matcher{Contains: "dev"}, true),
+		Entry("numeric range", []any{json.Number("7")}, config.Matcher{Gte: ptr(5.0), Lt: ptr(10.0)}, true),
+		Entry("numeric on string body value", []any{"abc"}, config.Matcher{Gt: ptr(1.0)}, false),
+	)
+})
+
+func ptr[T any](v T) *T { return &v }
+
+var _ = Describe("matchText", func() {
+	DescribeTable("evaluates query/header operators",
+		func(val string, present bool, m config.Matcher, want bool) {
+			Expect(matchText(val, present, m)).To(Equal(want))
+		},
+		Entry("plain equality", "bar", true, config.Eq("bar"), true),
+		Entry("plain empty matches missing", "", false, config.Eq(""), true),
+		Entry("equality against yaml number", "2", true, config.Matcher{Equals: 2}, true),
+		Entry("regex", "alice", true, config.Matcher{Regex: "^a.*"}, true),
+		Entry("regex ignore case", "Alice", true, config.Matcher{Regex: "^a", IgnoreCase: true}, true),
+		Entry("exists", "", true, config.Matcher{Exists: ptr(true)}, true),
+		Entry("exists missing", "", false, config.Matcher{Exists: ptr(true)}, false),
+		Entry("not exists", "", false, config.Matcher{Exists: ptr(false)}, true),
+		Entry("not exists but present", "x", true, config.Matcher{Exists: ptr(false)}, false),
+		Entry("oneOf", "b", true, config.Matcher{OneOf: []any{"a", "b"}}, true),
+		Entry("oneOf miss", "c", true, config.Matcher{OneOf: []any{"a", "b"}}, false),
+		Entry("contains", "application/json; charset=utf-8", true, config.Matcher{Contains: "json"}, true),
+		Entry("contains ignore case", "Bearer X", true, config.Matcher{Contains: "bearer", IgnoreCase: true}, true),
+		Entry("not", "b", true, config.Matcher{Not: &config.Matcher{OneOf: []any{"a", "b"}}}, false),
+		Entry("not passes", "c", true, config.Matcher{Not: &config.Matcher{Equals: "a"}}, true),
+		Entry("gt", "11", true, config.Matcher{Gt: ptr(10.0)}, true),
+		Entry("lte fails", "11", true, config.Matcher{Lte: ptr(10.0)}, false),
+		Entry("numeric on non-number", "ten", true, config.Matcher{Gt: ptr(1.0)}, false),
+		Entry("equality ignore case", "YES", true, config.Matcher{Equals: "yes", IgnoreCase: true}, true),
+	)
+})
+
 var _ = Describe("whenMatches", func() {
 	It("returns true when both query and header conditions match", func() {
 		req := httptest.NewRequest(http.MethodGet, "/?foo=bar", nil)
 		req.Header.Set("X-Test", "yes")
 		clause := &config.WhenClause{
-			Query:  map[string]string{"foo": "bar"},
-			Header: map[string]string{"X-Test": "yes"},
+			Query:  map[string]config.Matcher{"foo": config.Eq("bar")},
+			Header: map[string]config.Matcher{"X-Test": config.Eq("yes")},
 		}
 		Expect(whenMatches(req, clause)).To(BeTrue())
 	})
@@ -80,8 +224,8 @@ var _ = Describe("whenMatches", func() {
 		req := httptest.NewRequest(http.MethodGet, "/?foo=bar", nil)
 		req.Header.Set("X-Test", "yes")
 		clause := &config.WhenClause{
-			Query:  map[string]string{"foo": "nope"},
-			Header: map[string]string{"X-Test": "yes"},
+			Query:  map[string]config.Matcher{"foo": config.Eq("nope")},
+			Header: map[string]config.Matcher{"X-Test": config.Eq("yes")},
 		}
 		Expect(whenMatches(req, clause)).To(BeFalse())
 	})
@@ -90,8 +234,8 @@ var _ = Describe("whenMatches", func() {
 		req := httptest.NewRequest(http.MethodGet, "/?foo=bar", nil)
 		req.Header.Set("X-Test", "yes")
 		clause := &config.WhenClause{
-			Query:  map[string]string{"foo": "bar"},
-			Header: map[string]string{"X-Test": "nope"},
+			Query:  map[string]config.Matcher{"foo": config.Eq("bar")},
+			Header: map[string]config.Matcher{"X-Test": config.Eq("nope")},
 		}
 		Expect(whenMatches(req, clause)).To(BeFalse())
 	})
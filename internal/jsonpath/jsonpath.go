@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+// Package jsonpath evaluates a small JSONPath subset ($.a.b, $['a'], $.items[0],
+// $.items[*]) and RFC 6901 JSON Pointers (/a/b/0) against decoded JSON values.
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type segment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+type Path struct {
+	expr     string
+	segments []segment
+}
+
+func (p *Path) String() string {
+	return p.expr
+}
+
+// Compile parses expr as a JSON Pointer when it is empty or starts with '/',
+// otherwise as a JSONPath starting with '$'.
+func Compile(expr string) (*Path, error) {
+	if expr == "" || strings.HasPrefix(expr, "/") {
+		return compilePointer(expr)
+	}
+	if strings.HasPrefix(expr, "$") {
+		return compileJSONPath(expr)
+	}
+	return nil, fmt.Errorf("path %q must start with '$' (JSONPath) or '/' (JSON Pointer)", expr)
+}
+
+func compilePointer(expr string) (*Path, error) {
+	p := &Path{expr: expr}
+	if expr == "" {
+		return p, nil
+	}
+
+	for _, tok := range strings.Split(expr[1:], "/") {
+		tok = strings.ReplaceAll(strings.ReplaceAll(tok, "~1", "/"), "~0", "~")
+		seg := segment{key: tok}
+		if n, err := strconv.Atoi(tok); err == nil && n >= 0 {
+			seg.index = n
+			seg.isIndex = true
+		}
+		p.segments = append(p.segments, seg)
+	}
+
+	return p, nil
+}
+
+func compileJSONPath(expr string) (*Path, error) {
+	p := &Path{expr: expr}
+	s := expr[1:]
+
+	for len(s) > 0 {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			if strings.HasPrefix(s, ".") {
+				return nil, fmt.Errorf("path %q: recursive descent is not supported", expr)
+			}
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+			name := s[:end]
+			if name == "" {
+				return nil, fmt.Errorf("path %q: empty member name", expr)
+			}
+			if name == "*" {
+				p.segments = append(p.segments, segment{wildcard: true})
+			} else {
+				p.segments = append(p.segments, segment{key: name})
+			}
+			s = s[end:]
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("path %q: unterminated '['", expr)
+			}
+			inner := strings.TrimSpace(s[1:end])
+			s = s[end+1:]
+
+			switch {
+			case inner == "*":
+				p.segments = append(p.segments, segment{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				p.segments = append(p.segments, segment{key: inner[1 : len(inner)-1]})
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("path %q: invalid index %q", expr, inner)
+				}
+				p.segments = append(p.segments, segment{index: n, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("path %q: unexpected %q", expr, s[0])
+		}
+	}
+
+	return p, nil
+}
+
+// Find returns every value the path resolves to. An empty result means the
+// path does not exist in doc.
+func (p *Path) Find(doc any) []any {
+	cur := []any{doc}
+	for _, seg := range p.segments {
+		var next []any
+		for _, v := range cur {
+			next = append(next, seg.apply(v)...)
+		}
+		if len(next) == 0 {
+			return nil
+		}
+		cur = next
+	}
+	return cur
+}
+
+func (s segment) apply(v any) []any {
+	switch t := v.(type) {
+	case map[string]any:
+		if s.wildcard {
+			out := make([]any, 0, len(t))
+			for _, child := range t {
+				out = append(out, child)
+			}
+			return out
+		}
+		if child, ok := t[s.key]; ok {
+			return []any{child}
+		}
+	case []any:
+		if s.wildcard {
+			return t
+		}
+		if !s.isIndex {
+			return nil
+		}
+		i := s.index
+		if i < 0 {
+			i += len(t)
+		}
+		if i >= 0 && i < len(t) {
+			return []any{t[i]}
+		}
+	}
+	return nil
+}
+
+// Lookup compiles expr and evaluates it against doc.
+func Lookup(doc any, expr string) ([]any, error) {
+	p, err := Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return p.Find(doc), nil
+}
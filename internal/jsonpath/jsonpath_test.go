@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package jsonpath
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func decode(s string) any {
+	var v any
+	Expect(json.Unmarshal([]byte(s), &v)).To(Succeed())
+	return v
+}
+
+var _ = Describe("Lookup", func() {
+	doc := decode(`{
+		"type": "refund",
+		"a/b": 1,
+		"user": {"name": "alice", "tags": ["x", "y"]},
+		"items": [{"sku": "A"}, {"sku": "B"}]
+	}`)
+
+	DescribeTable("resolves expressions",
+		func(expr string, want []any) {
+			got, err := Lookup(doc, expr)
+			Expect(err).NotTo(HaveOccurred())
+			if want == nil {
+				Expect(got).To(BeEmpty())
+			} else {
+				Expect(got).To(ConsistOf(want...))
+			}
+		},
+		Entry("root member", "$.type", []any{"refund"}),
+		Entry("nested member", "$.user.name", []any{"alice"}),
+		Entry("bracket member", "$['user'][\"name\"]", []any{"alice"}),
+		Entry("array index", "$.user.tags[1]", []any{"y"}),
+		Entry("negative index", "$.user.tags[-1]", []any{"y"}),
+		Entry("wildcard", "$.items[*].sku", []any{"A", "B"}),
+		Entry("missing member", "$.nope", nil),
+		Entry("pointer member", "/user/name", []any{"alice"}),
+		Entry("pointer index", "/items/0/sku", []any{"A"}),
+		Entry("pointer escape", "/a~1b", []any{float64(1)}),
+	)
+
+	It("returns the whole document for the root expressions", func() {
+		got, err := Lookup(doc, "$")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got).To(HaveLen(1))
+
+		got, err = Lookup(doc, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got).To(HaveLen(1))
+	})
+
+	DescribeTable("rejects invalid expressions",
+		func(expr string) {
+			_, err := Compile(expr)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("no prefix", "type"),
+		Entry("recursive descent", "$..type"),
+		Entry("unterminated bracket", "$.items[0"),
+		Entry("bad index", "$.items[x]"),
+		Entry("empty member", "$.user."),
+	)
+})
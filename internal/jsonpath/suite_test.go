@@ -0,0 +1,16 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package jsonpath
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestJSONPath(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "JSONPath Suite")
+}
@@ -0,0 +1,418 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package oauth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/go-chi/chi/v5"
+)
+
+// Handler serves the issuer's endpoints relative to where it is mounted.
+func (i *Issuer) Handler() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/.well-known/openid-configuration", i.discovery)
+	r.Get("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, i.jwks())
+	})
+	r.Get("/authorize", i.authorize)
+	r.Post("/authorize", i.authorize)
+	r.Post("/token", i.token)
+	r.Get("/userinfo", i.userinfo)
+	r.Post("/userinfo", i.userinfo)
+	r.Post("/introspect", i.introspect)
+	return r
+}
+
+// baseURL is where the issuer is reachable for this request.
+func (i *Issuer) baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if p := r.Header.Get("X-Forwarded-Proto"); p != "" {
+		scheme = p
+	}
+	return scheme + "://" + r.Host + i.config().Prefix
+}
+
+func (i *Issuer) issuer(r *http.Request) string {
+	if iss := i.config().Issuer; iss != "" {
+		return iss
+	}
+	return i.baseURL(r)
+}
+
+func (i *Issuer) discovery(w http.ResponseWriter, r *http.Request) {
+	base := i.baseURL(r)
+	scopes := []string{"openid"}
+	for _, c := range i.config().Clients {
+		for _, s := range c.Scopes {
+			if !slices.Contains(scopes, s) {
+				scopes = append(scopes, s)
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                i.issuer(r),
+		"authorization_endpoint":                base + "/authorize",
+		"token_endpoint":                        base + "/token",
+		"userinfo_endpoint":                     base + "/userinfo",
+		"jwks_uri":                              base + "/jwks",
+		"introspection_endpoint":                base + "/introspect",
+		"scopes_supported":                      scopes,
+		"response_types_supported":              []string{"code"},
+		"grant_types_supported":                 config.OAuthGrantTypes,
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post", "none"},
+		"code_challenge_methods_supported":      []string{"S256", "plain"},
+	})
+}
+
+// authorize approves the request for the user named by login_hint, or the
+// first configured user, and redirects back with a code.
+func (i *Issuer) authorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, errInvalidRequest("malformed request"))
+		return
+	}
+	q := r.Form
+
+	c, ok := i.client(q.Get("client_id"))
+	if !ok {
+		writeError(w, errInvalidRequest("unknown client_id"))
+		return
+	}
+	redirect := q.Get("redirect_uri")
+	if redirect == "" && len(c.RedirectURIs) == 1 {
+		redirect = c.RedirectURIs[0]
+	}
+	if u, err := url.Parse(redirect); err != nil || u.Scheme == "" {
+		writeError(w, errInvalidRequest("invalid redirect_uri"))
+		return
+	}
+	if !slices.Contains(c.RedirectURIs, redirect) {
+		writeError(w, errInvalidRequest("redirect_uri not registered for client"))
+		return
+	}
+
+	// from here on errors go back to the client
+	fail := func(e *oauthError) {
+		redirectTo(w, r, redirect, url.Values{"error": {e.Code}, "error_description": {e.Description}, "state": {q.Get("state")}})
+	}
+
+	if q.Get("response_type") != "code" {
+		fail(&oauthError{Code: "unsupported_response_type", Description: "only response_type=code is supported"})
+		return
+	}
+	if !grantAllowed(c, "authorization_code") {
+		fail(&oauthError{Code: "unauthorized_client", Description: "grant type not allowed for client"})
+		return
+	}
+	scope, err := scopeFor(c, q.Get("scope"))
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	method := q.Get("code_challenge_method")
+	challenge := q.Get("code_challenge")
+	switch {
+	case challenge == "" && c.ClientSecret == "":
+		fail(errInvalidRequest("public clients must use PKCE"))
+		return
+	case challenge != "" && method == "":
+		method = "plain"
+	case challenge != "" && method != "S256" && method != "plain":
+		fail(errInvalidRequest("unsupported code_challenge_method"))
+		return
+	}
+
+	users := i.config().Users
+	var username string
+	if hint := q.Get("login_hint"); hint != "" {
+		if _, ok := i.user(hint); ok {
+			username = hint
+		}
+	} else if len(users) > 0 {
+		username = users[0].Username
+	}
+	if username == "" {
+		fail(&oauthError{Code: "access_denied", Description: "no matching user"})
+		return
+	}
+
+	code := i.storeCode(grant{
+		clientID:    c.ClientID,
+		username:    username,
+		scope:       scope,
+		redirectURI: q.Get("redirect_uri"),
+		challenge:   challenge,
+		method:      method,
+		nonce:       q.Get("nonce"),
+	})
+	redirectTo(w, r, redirect, url.Values{"code": {code}, "state": {q.Get("state")}})
+}
+
+func (i *Issuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, errInvalidRequest("malformed request"))
+		return
+	}
+	c, oerr := i.authenticateClient(r)
+	if oerr != nil {
+		writeError(w, oerr)
+		return
+	}
+
+	gt := r.PostForm.Get("grant_type")
+	if !slices.Contains(config.OAuthGrantTypes, gt) {
+		writeError(w, &oauthError{Code: "unsupported_grant_type", Description: "unsupported grant_type " + gt})
+		return
+	}
+	if !grantAllowed(c, gt) || (gt == "client_credentials" && c.ClientSecret == "") {
+		writeError(w, &oauthError{Code: "unauthorized_client", Description: "grant type not allowed for client"})
+		return
+	}
+
+	var (
+		g           grant
+		withRefresh = true
+	)
+	switch gt {
+	case "client_credentials":
+		scope, err := scopeFor(c, r.PostForm.Get("scope"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		g = grant{clientID: c.ClientID, scope: scope}
+		withRefresh = false
+
+	case "password":
+		username := r.PostForm.Get("username")
+		u, ok := i.user(username)
+		if !ok || subtle.ConstantTimeCompare([]byte(u.Password), []byte(r.PostForm.Get("password"))) != 1 {
+			writeError(w, errInvalidGrant("invalid username or password"))
+			return
+		}
+		scope, err := scopeFor(c, r.PostForm.Get("scope"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		g = grant{clientID: c.ClientID, username: username, scope: scope}
+
+	case "authorization_code":
+		var ok bool
+		g, ok = i.redeemCode(r.PostForm.Get("code"))
+		switch {
+		case !ok || g.clientID != c.ClientID:
+			writeError(w, errInvalidGrant("invalid or expired code"))
+			return
+		case g.redirectURI != r.PostForm.Get("redirect_uri"):
+			writeError(w, errInvalidGrant("redirect_uri does not match the authorization request"))
+			return
+		case !verifyPKCE(g, r.PostForm.Get("code_verifier")):
+			writeError(w, errInvalidGrant("code_verifier does not match code_challenge"))
+			return
+		}
+
+	case "refresh_token":
+		var ok bool
+		g, ok = i.redeemRefresh(r.PostForm.Get("refresh_token"), c.ClientID)
+		if !ok {
+			writeError(w, errInvalidGrant("invalid refresh_token"))
+			return
+		}
+		// a refresh may narrow the scope but not widen it
+		if s := strings.Fields(r.PostForm.Get("scope")); len(s) > 0 {
+			for _, x := range s {
+				if !slices.Contains(g.scope, x) {
+					writeError(w, &oauthError{Code: "invalid_scope", Description: "scope " + x + " was not granted"})
+					return
+				}
+			}
+			g.scope = s
+		}
+	}
+
+	resp, err := i.issue(i.issuer(r), g, withRefresh)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, &oauthError{Code: "server_error", Description: err.Error()})
+		return
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// authenticateClient reads client credentials from HTTP basic auth or the
+// form. Public clients only send their client_id.
+func (i *Issuer) authenticateClient(r *http.Request) (config.OAuthClient, *oauthError) {
+	id, secret, basic := r.BasicAuth()
+	if basic {
+		// RFC 6749 2.3.1 form-encodes both parts
+		if v, err := url.QueryUnescape(id); err == nil {
+			id = v
+		}
+		if v, err := url.QueryUnescape(secret); err == nil {
+			secret = v
+		}
+	} else {
+		id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+
+	c, ok := i.client(id)
+	if !ok || subtle.ConstantTimeCompare([]byte(c.ClientSecret), []byte(secret)) != 1 {
+		e := &oauthError{Code: "invalid_client", Description: "client authentication failed", Status: http.StatusUnauthorized}
+		if basic {
+			e.Challenge = `Basic realm="mocker"`
+		}
+		return config.OAuthClient{}, e
+	}
+	return c, nil
+}
+
+func (i *Issuer) userinfo(w http.ResponseWriter, r *http.Request) {
+	claims, err := i.verifier.Verify(strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")))
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="mocker", error="invalid_token"`)
+		writeJSON(w, http.StatusUnauthorized, &oauthError{Code: "invalid_token", Description: err.Error()})
+		return
+	}
+	username, _ := claims["preferred_username"].(string)
+	u, ok := i.user(username)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="mocker", error="invalid_token"`)
+		writeJSON(w, http.StatusUnauthorized, &oauthError{Code: "invalid_token", Description: "token has no user"})
+		return
+	}
+	writeJSON(w, http.StatusOK, claimsWith(u.Claims, map[string]any{
+		"sub":                u.Username,
+		"preferred_username": u.Username,
+	}))
+}
+
+// introspect reports whether an access or refresh token is active
+// (RFC 7662). Client authentication is not required.
+func (i *Issuer) introspect(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, errInvalidRequest("malformed request"))
+		return
+	}
+	token := r.PostForm.Get("token")
+
+	if claims, err := i.verifier.Verify(token); err == nil {
+		writeJSON(w, http.StatusOK, claimsWith(claims, map[string]any{
+			"active":     true,
+			"token_type": "Bearer",
+			"username":   claims["preferred_username"],
+		}))
+		return
+	}
+	if g, ok := i.lookupRefresh(token); ok {
+		out := map[string]any{
+			"active":     true,
+			"token_type": "refresh_token",
+			"client_id":  g.clientID,
+			"scope":      strings.Join(g.scope, " "),
+			"sub":        g.clientID,
+		}
+		if g.username != "" {
+			out["sub"], out["username"] = g.username, g.username
+		}
+		writeJSON(w, http.StatusOK, out)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"active": false})
+}
+
+func grantAllowed(c config.OAuthClient, gt string) bool {
+	return len(c.GrantTypes) == 0 || slices.Contains(c.GrantTypes, gt)
+}
+
+// scopeFor checks the requested scopes against the client's, defaulting
+// to all of the client's scopes.
+func scopeFor(c config.OAuthClient, requested string) ([]string, *oauthError) {
+	scope := strings.Fields(requested)
+	if len(scope) == 0 {
+		return slices.Clone(c.Scopes), nil
+	}
+	if len(c.Scopes) == 0 {
+		return scope, nil
+	}
+	for _, s := range scope {
+		if s != "openid" && !slices.Contains(c.Scopes, s) {
+			return nil, &oauthError{Code: "invalid_scope", Description: "scope " + s + " not allowed for client"}
+		}
+	}
+	return scope, nil
+}
+
+func verifyPKCE(g grant, verifier string) bool {
+	switch g.method {
+	case "":
+		return true
+	case "plain":
+		return subtle.ConstantTimeCompare([]byte(g.challenge), []byte(verifier)) == 1
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	want := base64.RawURLEncoding.EncodeToString(sum[:])
+	return verifier != "" && subtle.ConstantTimeCompare([]byte(g.challenge), []byte(want)) == 1
+}
+
+func redirectTo(w http.ResponseWriter, r *http.Request, target string, params url.Values) {
+	u, _ := url.Parse(target)
+	q := u.Query()
+	for k, vs := range params {
+		if vs[0] != "" {
+			q.Set(k, vs[0])
+		}
+	}
+	u.RawQuery = q.Encode()
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
+
+// oauthError is an RFC 6749 error response.
+type oauthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+	Status      int    `json:"-"`
+	Challenge   string `json:"-"`
+}
+
+func errInvalidRequest(desc string) *oauthError {
+	return &oauthError{Code: "invalid_request", Description: desc}
+}
+
+func errInvalidGrant(desc string) *oauthError {
+	return &oauthError{Code: "invalid_grant", Description: desc}
+}
+
+func writeError(w http.ResponseWriter, e *oauthError) {
+	status := e.Status
+	if status == 0 {
+		status = http.StatusBadRequest
+	}
+	if e.Challenge != "" {
+		w.Header().Set("WWW-Authenticate", e.Challenge)
+	}
+	writeJSON(w, status, e)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
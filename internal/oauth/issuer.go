@@ -0,0 +1,322 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+// Package oauth implements a mock OAuth2 authorization server and OpenID
+// provider for testing login flows against mocker.
+package oauth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"maps"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bl4cky99/mocker/internal/auth"
+	"github.com/Bl4cky99/mocker/internal/config"
+)
+
+const (
+	// codeTTL bounds how long an authorization code can be redeemed.
+	codeTTL = time.Minute
+	// refreshTTL bounds how long a refresh token can be used.
+	refreshTTL = 24 * time.Hour
+)
+
+// Issuer signs access, id and refresh tokens for configured clients and
+// users. Its key and issued codes and refresh tokens live in memory, so
+// they survive config reloads but not restarts.
+type Issuer struct {
+	key      *rsa.PrivateKey
+	kid      string
+	verifier *auth.JWTAuth
+
+	mu      sync.Mutex
+	cfg     config.OAuthConfig
+	codes   map[string]grant
+	refresh map[string]grant
+	now     func() time.Time
+}
+
+// grant is what an authorization code or refresh token stands for.
+type grant struct {
+	clientID string
+	// empty for client_credentials
+	username string
+	scope    []string
+	expires  time.Time
+
+	// authorization codes only
+	redirectURI string
+	challenge   string
+	method      string
+	nonce       string
+}
+
+// New generates a signing key and returns an issuer serving cfg.
+func New(cfg config.OAuthConfig) (*Issuer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(key.N.Bytes())
+	i := &Issuer{
+		key:     key,
+		kid:     base64.RawURLEncoding.EncodeToString(sum[:8]),
+		cfg:     cfg,
+		codes:   map[string]grant{},
+		refresh: map[string]grant{},
+		now:     time.Now,
+	}
+
+	ks := &auth.KeySet{}
+	ks.AddPublicKey(i.kid, &key.PublicKey)
+	i.verifier = auth.NewJWTAuth(ks, auth.WithAlgorithms("RS256"), auth.WithClock(i.clock))
+	return i, nil
+}
+
+// Configure swaps the clients, users and token settings. Issued tokens
+// stay valid.
+func (i *Issuer) Configure(cfg config.OAuthConfig) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.cfg = cfg
+}
+
+func (i *Issuer) KeyID() string { return i.kid }
+
+func (i *Issuer) PublicKey() crypto.PublicKey { return &i.key.PublicKey }
+
+func (i *Issuer) clock() time.Time { return i.now() }
+
+func (i *Issuer) config() config.OAuthConfig {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.cfg
+}
+
+func (i *Issuer) client(id string) (config.OAuthClient, bool) {
+	cfg := i.config()
+	for _, c := range cfg.Clients {
+		if c.ClientID == id {
+			return c, true
+		}
+	}
+	return config.OAuthClient{}, false
+}
+
+func (i *Issuer) user(name string) (config.BasicUser, bool) {
+	cfg := i.config()
+	for _, u := range cfg.Users {
+		if u.Username == name {
+			return u, true
+		}
+	}
+	return config.BasicUser{}, false
+}
+
+// jwks returns the public signing key as a JSON Web Key Set.
+func (i *Issuer) jwks() map[string]any {
+	pub := i.key.PublicKey
+	return map[string]any{"keys": []map[string]any{{
+		"kty": "RSA",
+		"use": "sig",
+		"alg": "RS256",
+		"kid": i.kid,
+		"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}}
+}
+
+// tokenResponse is the body of a successful token request.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+}
+
+// issue mints tokens for g. An id token is added for users when openid
+// was requested, a refresh token when withRefresh is set.
+func (i *Issuer) issue(iss string, g grant, withRefresh bool) (tokenResponse, error) {
+	cfg := i.config()
+	now := i.now()
+	ttl := time.Duration(cfg.TokenTTL) * time.Second
+
+	sub := g.clientID
+	var extra map[string]any
+	var roles []string
+	if g.username != "" {
+		sub = g.username
+		if u, ok := i.user(g.username); ok {
+			extra, roles = u.Claims, u.Roles
+		}
+	}
+	aud := cfg.Audience
+	if aud == "" {
+		aud = g.clientID
+	}
+
+	at := claimsWith(extra, map[string]any{
+		"iss":       iss,
+		"sub":       sub,
+		"aud":       aud,
+		"iat":       now.Unix(),
+		"exp":       now.Add(ttl).Unix(),
+		"jti":       randomString(),
+		"client_id": g.clientID,
+	})
+	if len(g.scope) > 0 {
+		at["scope"] = strings.Join(g.scope, " ")
+	}
+	if g.username != "" {
+		at["preferred_username"] = g.username
+	}
+	if len(roles) > 0 {
+		at["roles"] = roles
+	}
+
+	access, err := i.sign(at)
+	if err != nil {
+		return tokenResponse{}, err
+	}
+	resp := tokenResponse{
+		AccessToken: access,
+		TokenType:   "Bearer",
+		ExpiresIn:   cfg.TokenTTL,
+		Scope:       strings.Join(g.scope, " "),
+	}
+
+	if g.username != "" && slices.Contains(g.scope, "openid") {
+		id := claimsWith(extra, map[string]any{
+			"iss":                iss,
+			"sub":                sub,
+			"aud":                g.clientID,
+			"iat":                now.Unix(),
+			"exp":                now.Add(ttl).Unix(),
+			"auth_time":          now.Unix(),
+			"preferred_username": g.username,
+		})
+		if g.nonce != "" {
+			id["nonce"] = g.nonce
+		}
+		if resp.IDToken, err = i.sign(id); err != nil {
+			return tokenResponse{}, err
+		}
+	}
+
+	if withRefresh {
+		resp.RefreshToken = randomString()
+		i.mu.Lock()
+		prune(i.refresh, now)
+		i.refresh[resp.RefreshToken] = grant{clientID: g.clientID, username: g.username, scope: g.scope, expires: now.Add(refreshTTL)}
+		i.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// sign serialises claims as an RS256 compact JWS.
+func (i *Issuer) sign(claims map[string]any) (string, error) {
+	enc := func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(b), err
+	}
+	header, err := enc(map[string]any{"alg": "RS256", "typ": "JWT", "kid": i.kid})
+	if err != nil {
+		return "", err
+	}
+	payload, err := enc(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := header + "." + payload
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, i.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// storeCode remembers g under a new single-use authorization code and
+// drops the codes that expired unredeemed.
+func (i *Issuer) storeCode(g grant) string {
+	code := randomString()
+	now := i.now()
+	g.expires = now.Add(codeTTL)
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	prune(i.codes, now)
+	i.codes[code] = g
+	return code
+}
+
+// redeemCode removes and returns the grant behind an unexpired code.
+func (i *Issuer) redeemCode(code string) (grant, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	g, ok := i.codes[code]
+	delete(i.codes, code)
+	if !ok || !i.now().Before(g.expires) {
+		return grant{}, false
+	}
+	return g, true
+}
+
+// redeemRefresh removes and returns the grant behind a refresh token
+// issued to clientID; refresh tokens are rotated on every use.
+func (i *Issuer) redeemRefresh(token, clientID string) (grant, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	g, ok := i.refresh[token]
+	if !ok || g.clientID != clientID {
+		return grant{}, false
+	}
+	delete(i.refresh, token)
+	if !i.now().Before(g.expires) {
+		return grant{}, false
+	}
+	return g, true
+}
+
+func (i *Issuer) lookupRefresh(token string) (grant, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	g, ok := i.refresh[token]
+	if !ok || !i.now().Before(g.expires) {
+		return grant{}, false
+	}
+	return g, true
+}
+
+// prune deletes the grants that expired by now.
+func prune(grants map[string]grant, now time.Time) {
+	maps.DeleteFunc(grants, func(_ string, g grant) bool { return !now.Before(g.expires) })
+}
+
+// claimsWith merges registered claims over the user's extra claims.
+func claimsWith(extra, registered map[string]any) map[string]any {
+	out := make(map[string]any, len(extra)+len(registered))
+	for k, v := range extra {
+		out[k] = v
+	}
+	for k, v := range registered {
+		out[k] = v
+	}
+	return out
+}
+
+func randomString() string {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,285 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package oauth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Bl4cky99/mocker/internal/auth"
+	"github.com/Bl4cky99/mocker/internal/config"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Issuer", func() {
+	var (
+		iss *Issuer
+		h   http.Handler
+	)
+
+	BeforeEach(func() {
+		var err error
+		iss, err = New(config.OAuthConfig{
+			Prefix:   "/oauth",
+			TokenTTL: 600,
+			Clients: []config.OAuthClient{
+				{ClientID: "backend", ClientSecret: "s3cret", RedirectURIs: []string{"http://app.test/cb"}, Scopes: []string{"read", "write"}},
+				{ClientID: "spa", RedirectURIs: []string{"http://app.test/cb"}, GrantTypes: []string{"authorization_code", "refresh_token"}},
+			},
+			Users: []config.BasicUser{
+				{Username: "alice", Password: "pw", Roles: []string{"admin"}, Claims: map[string]any{"email": "alice@example.com"}},
+				{Username: "bob", Password: "pw2"},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		h = iss.Handler()
+	})
+
+	do := func(req *http.Request) (*httptest.ResponseRecorder, map[string]any) {
+		req.Host = "id.test"
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		var body map[string]any
+		if strings.HasPrefix(rec.Header().Get("Content-Type"), "application/json") {
+			Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+		}
+		return rec, body
+	}
+	post := func(path string, form url.Values, basic ...string) (*httptest.ResponseRecorder, map[string]any) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if len(basic) == 2 {
+			req.SetBasicAuth(basic[0], basic[1])
+		}
+		return do(req)
+	}
+	// verifier checks tokens the way mocker's jwt auth does, with the
+	// published JWKS as the only key
+	verifier := func() *auth.JWTAuth {
+		rec, _ := do(httptest.NewRequest(http.MethodGet, "/jwks", nil))
+		ks := &auth.KeySet{}
+		Expect(ks.AddJWKS(rec.Body.Bytes())).To(Succeed())
+		return auth.NewJWTAuth(ks, auth.WithIssuer("http://id.test/oauth"))
+	}
+
+	bearer := func(token string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		return req
+	}
+
+	It("publishes discovery metadata and its signing key", func() {
+		rec, doc := do(httptest.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(doc).To(HaveKeyWithValue("issuer", "http://id.test/oauth"))
+		Expect(doc).To(HaveKeyWithValue("token_endpoint", "http://id.test/oauth/token"))
+		Expect(doc).To(HaveKeyWithValue("jwks_uri", "http://id.test/oauth/jwks"))
+		Expect(doc["scopes_supported"]).To(ConsistOf("openid", "read", "write"))
+
+		_, jwks := do(httptest.NewRequest(http.MethodGet, "/jwks", nil))
+		Expect(jwks["keys"]).To(HaveLen(1))
+		Expect(jwks["keys"].([]any)[0]).To(HaveKeyWithValue("kid", iss.KeyID()))
+	})
+
+	It("issues client_credentials tokens without refresh token", func() {
+		rec, body := post("/token", url.Values{"grant_type": {"client_credentials"}, "scope": {"read"}}, "backend", "s3cret")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Cache-Control")).To(Equal("no-store"))
+		Expect(body).To(HaveKeyWithValue("token_type", "Bearer"))
+		Expect(body).To(HaveKeyWithValue("expires_in", 600.0))
+		Expect(body).NotTo(HaveKey("refresh_token"))
+
+		claims, err := verifier().Verify(body["access_token"].(string))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claims).To(HaveKeyWithValue("sub", "backend"))
+		Expect(claims).To(HaveKeyWithValue("aud", "backend"))
+		Expect(claims).To(HaveKeyWithValue("scope", "read"))
+	})
+
+	It("issues password tokens with id token and rotates refresh tokens", func() {
+		rec, body := post("/token", url.Values{
+			"grant_type": {"password"}, "username": {"alice"}, "password": {"pw"},
+			"scope": {"openid read"}, "client_id": {"backend"}, "client_secret": {"s3cret"},
+		})
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		id, err := verifier().Verify(body["id_token"].(string))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(HaveKeyWithValue("sub", "alice"))
+		Expect(id).To(HaveKeyWithValue("email", "alice@example.com"))
+
+		p, ok, err := verifier().Authenticate(bearer(body["access_token"].(string)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(p.Roles).To(Equal([]string{"admin"}))
+		Expect(p.Scopes).To(Equal([]string{"openid", "read"}))
+
+		refresh := body["refresh_token"].(string)
+		rec, again := post("/token", url.Values{"grant_type": {"refresh_token"}, "refresh_token": {refresh}, "scope": {"read"}}, "backend", "s3cret")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(again).To(HaveKeyWithValue("scope", "read"))
+		Expect(again["refresh_token"]).NotTo(Equal(refresh))
+
+		rec, errBody := post("/token", url.Values{"grant_type": {"refresh_token"}, "refresh_token": {refresh}}, "backend", "s3cret")
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		Expect(errBody).To(HaveKeyWithValue("error", "invalid_grant"))
+	})
+
+	It("runs the authorization code flow with PKCE", func() {
+		verifierStr := "a-long-random-code-verifier-0123456789"
+		sum := sha256.Sum256([]byte(verifierStr))
+		challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+		q := url.Values{
+			"response_type": {"code"}, "client_id": {"spa"}, "redirect_uri": {"http://app.test/cb"},
+			"scope": {"openid"}, "state": {"xyz"}, "nonce": {"n-1"}, "login_hint": {"bob"},
+			"code_challenge": {challenge}, "code_challenge_method": {"S256"},
+		}
+		rec, _ := do(httptest.NewRequest(http.MethodGet, "/authorize?"+q.Encode(), nil))
+		Expect(rec.Code).To(Equal(http.StatusFound))
+		loc, err := url.Parse(rec.Header().Get("Location"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loc.Host).To(Equal("app.test"))
+		Expect(loc.Query().Get("state")).To(Equal("xyz"))
+		code := loc.Query().Get("code")
+		Expect(code).NotTo(BeEmpty())
+
+		exchange := func(v string) (*httptest.ResponseRecorder, map[string]any) {
+			return post("/token", url.Values{
+				"grant_type": {"authorization_code"}, "code": {code}, "client_id": {"spa"},
+				"redirect_uri": {"http://app.test/cb"}, "code_verifier": {v},
+			})
+		}
+
+		rec, body := exchange("wrong")
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(HaveKeyWithValue("error", "invalid_grant"))
+
+		// a failed exchange burns the code
+		rec, _ = exchange(verifierStr)
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+
+		rec, _ = do(httptest.NewRequest(http.MethodGet, "/authorize?"+q.Encode(), nil))
+		loc, _ = url.Parse(rec.Header().Get("Location"))
+		code = loc.Query().Get("code")
+		rec, body = exchange(verifierStr)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		id, err := verifier().Verify(body["id_token"].(string))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(HaveKeyWithValue("sub", "bob"))
+		Expect(id).To(HaveKeyWithValue("nonce", "n-1"))
+		Expect(id).To(HaveKeyWithValue("aud", "spa"))
+	})
+
+	It("redirects authorization errors back to the client", func() {
+		q := url.Values{"response_type": {"code"}, "client_id": {"spa"}, "state": {"s"}}
+		rec, _ := do(httptest.NewRequest(http.MethodGet, "/authorize?"+q.Encode(), nil))
+		Expect(rec.Code).To(Equal(http.StatusFound))
+		loc, _ := url.Parse(rec.Header().Get("Location"))
+		Expect(loc.Query().Get("error")).To(Equal("invalid_request"))
+		Expect(loc.Query().Get("error_description")).To(ContainSubstring("PKCE"))
+		Expect(loc.Query().Get("state")).To(Equal("s"))
+
+		q.Set("redirect_uri", "http://evil.test/cb")
+		rec, body := do(httptest.NewRequest(http.MethodGet, "/authorize?"+q.Encode(), nil))
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(HaveKeyWithValue("error", "invalid_request"))
+
+		// without registered redirect URIs there is nowhere safe to redirect to
+		cfg := iss.config()
+		cfg.Clients = append(cfg.Clients, config.OAuthClient{ClientID: "open", ClientSecret: "s"})
+		iss.Configure(cfg)
+		q.Set("client_id", "open")
+		rec, body = do(httptest.NewRequest(http.MethodGet, "/authorize?"+q.Encode(), nil))
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(HaveKeyWithValue("error", "invalid_request"))
+	})
+
+	It("expires authorization codes", func() {
+		now := time.Now()
+		iss.now = func() time.Time { return now }
+		q := url.Values{"response_type": {"code"}, "client_id": {"backend"}, "redirect_uri": {"http://app.test/cb"}}
+		rec, _ := do(httptest.NewRequest(http.MethodGet, "/authorize?"+q.Encode(), nil))
+		loc, _ := url.Parse(rec.Header().Get("Location"))
+
+		now = now.Add(2 * codeTTL)
+		rec, body := post("/token", url.Values{
+			"grant_type": {"authorization_code"}, "code": {loc.Query().Get("code")}, "redirect_uri": {"http://app.test/cb"},
+		}, "backend", "s3cret")
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(HaveKeyWithValue("error", "invalid_grant"))
+	})
+
+	It("expires refresh tokens and prunes stale codes and tokens", func() {
+		now := time.Now()
+		iss.now = func() time.Time { return now }
+		password := url.Values{"grant_type": {"password"}, "username": {"alice"}, "password": {"pw"}}
+		authorize := url.Values{"response_type": {"code"}, "client_id": {"backend"}, "redirect_uri": {"http://app.test/cb"}}
+
+		_, body := post("/token", password, "backend", "s3cret")
+		refresh := body["refresh_token"].(string)
+		do(httptest.NewRequest(http.MethodGet, "/authorize?"+authorize.Encode(), nil))
+
+		now = now.Add(refreshTTL)
+		_, in := post("/introspect", url.Values{"token": {refresh}})
+		Expect(in).To(Equal(map[string]any{"active": false}))
+
+		post("/token", password, "backend", "s3cret")
+		do(httptest.NewRequest(http.MethodGet, "/authorize?"+authorize.Encode(), nil))
+		Expect(iss.refresh).To(HaveLen(1))
+		Expect(iss.codes).To(HaveLen(1))
+
+		rec, errBody := post("/token", url.Values{"grant_type": {"refresh_token"}, "refresh_token": {refresh}}, "backend", "s3cret")
+		Expect(rec.Code).To(Equal(http.StatusBadRequest))
+		Expect(errBody).To(HaveKeyWithValue("error", "invalid_grant"))
+	})
+
+	DescribeTable("rejects token requests",
+		func(form url.Values, basic []string, status int, code string) {
+			rec, body := post("/token", form, basic...)
+			Expect(rec.Code).To(Equal(status))
+			Expect(body).To(HaveKeyWithValue("error", code))
+		},
+		Entry("wrong client secret", url.Values{"grant_type": {"client_credentials"}}, []string{"backend", "nope"}, http.StatusUnauthorized, "invalid_client"),
+		Entry("unknown grant type", url.Values{"grant_type": {"implicit"}}, []string{"backend", "s3cret"}, http.StatusBadRequest, "unsupported_grant_type"),
+		Entry("grant not allowed", url.Values{"grant_type": {"password"}, "client_id": {"spa"}}, nil, http.StatusBadRequest, "unauthorized_client"),
+		Entry("scope not allowed", url.Values{"grant_type": {"client_credentials"}, "scope": {"admin"}}, []string{"backend", "s3cret"}, http.StatusBadRequest, "invalid_scope"),
+		Entry("wrong password", url.Values{"grant_type": {"password"}, "username": {"alice"}, "password": {"x"}}, []string{"backend", "s3cret"}, http.StatusBadRequest, "invalid_grant"),
+	)
+
+	It("serves userinfo and introspection", func() {
+		_, body := post("/token", url.Values{"grant_type": {"password"}, "username": {"alice"}, "password": {"pw"}}, "backend", "s3cret")
+		access := body["access_token"].(string)
+
+		req := httptest.NewRequest(http.MethodGet, "/userinfo", nil)
+		req.Header.Set("Authorization", "Bearer "+access)
+		rec, info := do(req)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(info).To(HaveKeyWithValue("sub", "alice"))
+		Expect(info).To(HaveKeyWithValue("email", "alice@example.com"))
+
+		rec, _ = do(httptest.NewRequest(http.MethodGet, "/userinfo", nil))
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+		Expect(rec.Header().Get("WWW-Authenticate")).To(ContainSubstring("invalid_token"))
+
+		_, in := post("/introspect", url.Values{"token": {access}})
+		Expect(in).To(HaveKeyWithValue("active", true))
+		Expect(in).To(HaveKeyWithValue("username", "alice"))
+
+		_, in = post("/introspect", url.Values{"token": {body["refresh_token"].(string)}})
+		Expect(in).To(HaveKeyWithValue("active", true))
+		Expect(in).To(HaveKeyWithValue("token_type", "refresh_token"))
+
+		_, in = post("/introspect", url.Values{"token": {"garbage"}})
+		Expect(in).To(Equal(map[string]any{"active": false}))
+	})
+})
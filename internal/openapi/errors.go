@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package openapi
+
+import "errors"
+
+var (
+	ErrRead    = errors.New("openapi: read document")
+	ErrDecode  = errors.New("openapi: decode document")
+	ErrVersion = errors.New("openapi: unsupported version")
+	ErrRef     = errors.New("openapi: unresolved reference")
+)
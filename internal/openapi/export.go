@@ -0,0 +1,406 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/render"
+)
+
+// Export describes the configured endpoints and resources as an OpenAPI
+// 3.1 document. Response examples are rendered with tpl against a request
+// whose path parameters are all "1"; bodies that fail to render are left
+// without an example. Unreadable schema files are reported but do not stop
+// the export.
+func Export(cfg *config.Config, tpl *render.Renderer) (*Document, error) {
+	doc := &Document{
+		OpenAPI:    "3.1.0",
+		Info:       Info{Title: "mocker", Version: "1.0.0"},
+		Paths:      map[string]*PathItem{},
+		Components: &Components{},
+	}
+	if bp := strings.TrimRight(cfg.Server.BasePath, "/"); bp != "" {
+		doc.Servers = []Server{{URL: bp}}
+	}
+	if reqs, schemes := security(cfg.Auth, cfg.Auth.Type, []string{}); len(schemes) > 0 {
+		doc.Components.SecuritySchemes = schemes
+		doc.Security = reqs
+	}
+
+	var errs []error
+	schemas := map[string]Schema{}
+	loadSchema := func(file string) Schema {
+		if file == "" {
+			return nil
+		}
+		if s, ok := schemas[file]; ok {
+			return s
+		}
+		s, err := readSchema(file)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		schemas[file] = s
+		return s
+	}
+
+	for _, ep := range cfg.Endpoints {
+		op := &Operation{Parameters: parameters(ep), Responses: map[string]*Response{}}
+		if ep.Validate != nil && ep.Validate.ContentType != "" {
+			op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+				ep.Validate.ContentType: {Schema: loadSchema(ep.Validate.SchemaFile)},
+			}}
+		}
+		addResponses(cfg, ep, op, tpl)
+		if typ := cfg.AuthFor(ep); typ != cfg.Auth.Type || ep.Requires != nil {
+			// 3.1 allows role names for schemes other than oauth2
+			need := []string{}
+			if r := ep.Requires; r != nil {
+				need = append(append(need, r.Scopes...), r.Roles...)
+			}
+			reqs, schemes := security(cfg.Auth, typ, need)
+			if doc.Components.SecuritySchemes == nil && len(schemes) > 0 {
+				doc.Components.SecuritySchemes = map[string]*SecurityScheme{}
+			}
+			maps.Copy(doc.Components.SecuritySchemes, schemes)
+			if reqs == nil {
+				// an empty list marks the operation as public
+				reqs = []SecurityRequirement{}
+			}
+			op.Security = &reqs
+		}
+		if ep.Requires != nil && op.Responses["403"] == nil {
+			op.Responses["403"] = &Response{Description: "Forbidden"}
+		}
+
+		item := doc.pathItem(OpenAPIPath(ep.Path))
+		if prev := item.Operation(ep.Method); prev != nil {
+			// several endpoints on one route differ only in their when clauses
+			for code, r := range op.Responses {
+				if _, ok := prev.Responses[code]; !ok {
+					prev.Responses[code] = r
+				}
+			}
+			continue
+		}
+		item.SetOperation(ep.Method, op)
+	}
+
+	for _, res := range cfg.Resources {
+		exportResource(doc, res, loadSchema(res.SchemaFile))
+	}
+
+	return doc, errors.Join(errs...)
+}
+
+func (d *Document) pathItem(path string) *PathItem {
+	item := d.Paths[path]
+	if item == nil {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+	return item
+}
+
+// SetOperation sets the operation for an upper-case method.
+func (p *PathItem) SetOperation(method string, op *Operation) {
+	switch method {
+	case "GET":
+		p.Get = op
+	case "PUT":
+		p.Put = op
+	case "POST":
+		p.Post = op
+	case "DELETE":
+		p.Delete = op
+	case "OPTIONS":
+		p.Options = op
+	case "HEAD":
+		p.Head = op
+	case "PATCH":
+		p.Patch = op
+	case "TRACE":
+		p.Trace = op
+	}
+}
+
+var chiParam = regexp.MustCompile(`\{([^}:]+)(?::[^}]*)?\}`)
+
+// OpenAPIPath converts a chi route to an OpenAPI path template: regexp
+// constraints are dropped and a trailing * becomes {wildcard}.
+func OpenAPIPath(path string) string {
+	path = chiParam.ReplaceAllString(path, "{$1}")
+	if rest, ok := strings.CutSuffix(path, "*"); ok {
+		path = rest + "{wildcard}"
+	}
+	return path
+}
+
+func parameters(ep config.Endpoint) []*Parameter {
+	var out []*Parameter
+	for _, name := range config.PathParams(ep.Path) {
+		if name == "*" {
+			name = "wildcard"
+		}
+		out = append(out, &Parameter{Name: name, In: "path", Required: true, Schema: Schema{"type": "string"}})
+	}
+
+	query, header := map[string]struct{}{}, map[string]struct{}{}
+	for _, rv := range ep.Responses {
+		if rv.When == nil {
+			continue
+		}
+		for k := range rv.When.Query {
+			query[k] = struct{}{}
+		}
+		for k := range rv.When.Header {
+			header[http.CanonicalHeaderKey(k)] = struct{}{}
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(query)) {
+		out = append(out, &Parameter{Name: k, In: "query", Schema: Schema{"type": "string"}})
+	}
+	for _, k := range slices.Sorted(maps.Keys(header)) {
+		out = append(out, &Parameter{Name: k, In: "header", Schema: Schema{"type": "string"}})
+	}
+	return out
+}
+
+func addResponses(cfg *config.Config, ep config.Endpoint, op *Operation, tpl *render.Renderer) {
+	examples := map[string][]any{}
+	for _, rv := range ep.Responses {
+		code := "default"
+		if rv.Status != 0 {
+			code = strconv.Itoa(rv.Status)
+		} else if !rv.Proxy {
+			code = "200"
+		}
+
+		resp := op.Responses[code]
+		if resp == nil {
+			resp = &Response{Description: http.StatusText(rv.Status)}
+			switch {
+			case rv.Proxy:
+				resp.Description = "Proxied to the upstream service"
+			case rv.Status == 0:
+				resp.Description = http.StatusText(http.StatusOK)
+			case resp.Description == "":
+				resp.Description = "Response"
+			}
+			op.Responses[code] = resp
+		}
+		for _, h := range slices.Sorted(maps.Keys(rv.Headers)) {
+			if strings.EqualFold(h, "Content-Type") {
+				continue
+			}
+			if resp.Headers == nil {
+				resp.Headers = map[string]*Header{}
+			}
+			if _, ok := resp.Headers[h]; !ok {
+				resp.Headers[h] = &Header{Schema: Schema{"type": "string"}, Example: rv.Headers[h]}
+			}
+		}
+		if rv.Proxy {
+			continue
+		}
+
+		body, err := renderExample(cfg, tpl, ep, rv)
+		ct := contentType(cfg, rv, body)
+		if ct == "" {
+			continue
+		}
+		if resp.Content == nil {
+			resp.Content = map[string]MediaType{}
+		}
+		if _, ok := resp.Content[ct]; !ok {
+			resp.Content[ct] = MediaType{}
+		}
+		if err == nil {
+			examples[code+"\x00"+ct] = append(examples[code+"\x00"+ct], exampleValue(ct, body))
+		}
+	}
+
+	for key, vals := range examples {
+		code, ct, _ := strings.Cut(key, "\x00")
+		mt := op.Responses[code].Content[ct]
+		if len(vals) == 1 {
+			mt.Example = vals[0]
+		} else {
+			mt.Examples = map[string]*Example{}
+			for i, v := range vals {
+				mt.Examples[fmt.Sprintf("variant%d", i+1)] = &Example{Value: v}
+			}
+		}
+		op.Responses[code].Content[ct] = mt
+	}
+}
+
+func renderExample(cfg *config.Config, tpl *render.Renderer, ep config.Endpoint, rv config.ResponseVariant) ([]byte, error) {
+	engine := render.EngineFor(rv.Engine, cfg.ContentType(rv))
+	data := render.Data{
+		Path:       map[string]string{},
+		Query:      map[string]string{},
+		Header:     map[string]string{},
+		Form:       map[string]string{},
+		NowRFC3339: time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, p := range config.PathParams(ep.Path) {
+		data.Path[p] = "1"
+	}
+	switch {
+	case rv.Body != "":
+		return tpl.RenderString(engine, rv.Body, data)
+	case rv.BodyFile != "":
+		return tpl.RenderFile(engine, rv.BodyFile, data)
+	case rv.BodySchema != "":
+		return tpl.RenderSchema(rv.BodySchema)
+	}
+	return nil, nil
+}
+
+func contentType(cfg *config.Config, rv config.ResponseVariant, body []byte) string {
+	if ct := cfg.ContentType(rv); ct != "" {
+		return ct
+	}
+	switch {
+	case len(body) == 0:
+		return ""
+	case json.Valid(body):
+		return "application/json"
+	default:
+		return "text/plain"
+	}
+}
+
+func exampleValue(contentType string, body []byte) any {
+	var v any
+	if isJSON(contentType) && json.Unmarshal(body, &v) == nil {
+		return v
+	}
+	return string(body)
+}
+
+func readSchema(file string) (Schema, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrRead, file, err)
+	}
+	var s Schema
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrDecode, file, err)
+	}
+	return s, nil
+}
+
+// security returns the security requirements for auth type typ and the
+// schemes they name: one alternative per provider for "any", a single
+// requirement naming all of them for "all".
+func security(a config.AuthConfig, typ string, need []string) ([]SecurityRequirement, map[string]*SecurityScheme) {
+	types := []string{typ}
+	if typ == "any" || typ == "all" {
+		types = a.Providers
+	}
+
+	var reqs []SecurityRequirement
+	schemes := map[string]*SecurityScheme{}
+	for _, t := range types {
+		name, ss := securityScheme(a, t)
+		if ss == nil {
+			continue
+		}
+		if _, dup := schemes[name]; !dup && typ != "all" {
+			reqs = append(reqs, SecurityRequirement{name: need})
+		}
+		schemes[name] = ss
+	}
+	if typ == "all" && len(schemes) > 0 {
+		req := SecurityRequirement{}
+		for name := range schemes {
+			req[name] = need
+		}
+		reqs = []SecurityRequirement{req}
+	}
+	return reqs, schemes
+}
+
+// securityScheme maps the auth provider of type typ onto an OpenAPI
+// security scheme.
+func securityScheme(a config.AuthConfig, typ string) (string, *SecurityScheme) {
+	switch {
+	case typ == "basic":
+		return "basicAuth", &SecurityScheme{Type: "http", Scheme: "basic"}
+	case typ == "token" && a.Token != nil:
+		if strings.EqualFold(a.Token.Header, "Authorization") && strings.EqualFold(strings.TrimSpace(a.Token.Prefix), "Bearer") {
+			return "bearerAuth", &SecurityScheme{Type: "http", Scheme: "bearer"}
+		}
+		return "apiKeyAuth", &SecurityScheme{Type: "apiKey", In: "header", Name: a.Token.Header}
+	case typ == "jwt" && a.JWT != nil:
+		if strings.EqualFold(a.JWT.Header, "Authorization") && strings.EqualFold(strings.TrimSpace(a.JWT.Prefix), "Bearer") {
+			return "bearerAuth", &SecurityScheme{Type: "http", Scheme: "bearer", BearerFormat: "JWT"}
+		}
+		return "apiKeyAuth", &SecurityScheme{Type: "apiKey", In: "header", Name: a.JWT.Header}
+	}
+	return "", nil
+}
+
+func exportResource(doc *Document, res config.Resource, schema Schema) {
+	item := schema
+	if item == nil {
+		item = Schema{"type": "object"}
+	}
+	jsonBody := func(s Schema) map[string]MediaType {
+		return map[string]MediaType{"application/json": {Schema: s}}
+	}
+	idParam := []*Parameter{{Name: "id", In: "path", Required: true, Schema: Schema{"type": "string"}}}
+	notFound := &Response{Description: http.StatusText(http.StatusNotFound)}
+	invalid := &Response{Description: http.StatusText(http.StatusBadRequest)}
+
+	list := doc.pathItem(res.Path)
+	list.Get = &Operation{
+		Parameters: []*Parameter{
+			{Name: "_sort", In: "query", Schema: Schema{"type": "string"}},
+			{Name: "_order", In: "query", Schema: Schema{"enum": []any{"asc", "desc"}}},
+			{Name: "_limit", In: "query", Schema: Schema{"type": "integer"}},
+			{Name: "_offset", In: "query", Schema: Schema{"type": "integer"}},
+			{Name: "_page", In: "query", Schema: Schema{"type": "integer"}},
+		},
+		Responses: map[string]*Response{"200": {
+			Description: http.StatusText(http.StatusOK),
+			Headers:     map[string]*Header{"X-Total-Count": {Schema: Schema{"type": "integer"}}},
+			Content:     jsonBody(Schema{"type": "array", "items": item}),
+		}},
+	}
+	list.Post = &Operation{
+		RequestBody: &RequestBody{Required: true, Content: jsonBody(item)},
+		Responses: map[string]*Response{
+			"201": {Description: http.StatusText(http.StatusCreated), Content: jsonBody(item),
+				Headers: map[string]*Header{"Location": {Schema: Schema{"type": "string"}}}},
+			"400": invalid,
+			"409": {Description: http.StatusText(http.StatusConflict)},
+		},
+	}
+
+	one := doc.pathItem(res.Path + "/{id}")
+	ok := &Response{Description: http.StatusText(http.StatusOK), Content: jsonBody(item)}
+	one.Get = &Operation{Parameters: idParam, Responses: map[string]*Response{"200": ok, "404": notFound}}
+	one.Put = &Operation{Parameters: idParam, RequestBody: &RequestBody{Required: true, Content: jsonBody(item)},
+		Responses: map[string]*Response{"200": ok, "400": invalid, "404": notFound}}
+	one.Patch = &Operation{Parameters: idParam,
+		RequestBody: &RequestBody{Required: true, Content: jsonBody(Schema{"type": "object"})},
+		Responses:   map[string]*Response{"200": ok, "400": invalid, "404": notFound}}
+	one.Delete = &Operation{Parameters: idParam,
+		Responses: map[string]*Response{"204": {Description: http.StatusText(http.StatusNoContent)}, "404": notFound}}
+}
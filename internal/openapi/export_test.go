@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/render"
+)
+
+var _ = Describe("Export", func() {
+	var (
+		dir string
+		cfg *config.Config
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		schema := filepath.Join(dir, "user.schema.json")
+		Expect(os.WriteFile(schema, []byte(`{"type":"object","required":["name"]}`), 0o600)).To(Succeed())
+		body := filepath.Join(dir, "user.json")
+		Expect(os.WriteFile(body, []byte(`{"id": {{ .Path.id }}}`), 0o600)).To(Succeed())
+
+		cfg = &config.Config{
+			Server: config.ServerConfig{BasePath: "/api"},
+			Auth: config.AuthConfig{Type: "token", Token: &config.TokenAuthConfig{
+				Header: "Authorization", Prefix: "Bearer ", Tokens: []string{"t"},
+			}},
+			Resources: []config.Resource{{Path: "/teams", SchemaFile: schema}},
+			Endpoints: []config.Endpoint{
+				{
+					Method: "GET", Path: "/users/{id:[0-9]+}",
+					Responses: []config.ResponseVariant{
+						{When: &config.WhenClause{Query: map[string]config.Matcher{"v": config.Eq("2")}},
+							Status: 200, Headers: map[string]string{"Content-Type": "application/json", "X-Version": "2"}, Body: `{"v":2}`},
+						{Status: 200, Headers: map[string]string{"Content-Type": "application/json"}, BodyFile: body},
+						{When: &config.WhenClause{Header: map[string]config.Matcher{"x-fail": config.Eq("1")}},
+							Status: 500, Body: "boom"},
+					},
+				},
+				{
+					Method: "POST", Path: "/users",
+					Validate:  &config.ValidateSpec{ContentType: "application/json", SchemaFile: schema},
+					Responses: []config.ResponseVariant{{Status: 201, Body: `{{ .Broken`}},
+				},
+				{Method: "GET", Path: "/files/*", Responses: []config.ResponseVariant{{Proxy: true}}},
+			},
+		}
+		cfg.ApplyDefaults()
+	})
+
+	It("describes endpoints, examples and security", func() {
+		doc, err := Export(cfg, render.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(doc.OpenAPI).To(Equal("3.1.0"))
+		Expect(doc.Servers).To(Equal([]Server{{URL: "/api"}}))
+		Expect(doc.Components.SecuritySchemes).To(HaveKeyWithValue("bearerAuth", &SecurityScheme{Type: "http", Scheme: "bearer"}))
+
+		get := doc.Paths["/users/{id}"].Get
+		Expect(get.Parameters).To(HaveLen(3))
+		Expect(get.Parameters[0].In).To(Equal("path"))
+		Expect(get.Parameters[1].Name).To(Equal("v"))
+		Expect(get.Parameters[2].Name).To(Equal("X-Fail"))
+
+		ok := get.Responses["200"]
+		Expect(ok.Headers).To(HaveKey("X-Version"))
+		Expect(ok.Content["application/json"].Examples).To(HaveLen(2))
+		Expect(ok.Content["application/json"].Examples["variant2"].Value).To(Equal(map[string]any{"id": 1.0}))
+		Expect(get.Responses["500"].Content["text/plain"].Example).To(Equal("boom"))
+
+		post := doc.Paths["/users"].Post
+		Expect(post.RequestBody.Content["application/json"].Schema).To(HaveKeyWithValue("required", []any{"name"}))
+		Expect(post.Responses["201"].Content).To(BeEmpty())
+
+		Expect(doc.Paths["/files/{wildcard}"].Get.Responses).To(HaveKey("default"))
+		Expect(doc.Paths["/teams"].Post.RequestBody.Content["application/json"].Schema).To(HaveKey("required"))
+		Expect(doc.Paths["/teams/{id}"].Delete.Responses).To(HaveKey("204"))
+	})
+
+	It("describes jwt auth as a bearer scheme with JWT format", func() {
+		cfg.Auth = config.AuthConfig{Type: "jwt", JWT: &config.JWTAuthConfig{Secret: "s"}}
+		cfg.ApplyDefaults()
+		doc, err := Export(cfg, render.New())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(doc.Components.SecuritySchemes).To(HaveKeyWithValue("bearerAuth", &SecurityScheme{Type: "http", Scheme: "bearer", BearerFormat: "JWT"}))
+	})
+
+	It("documents per-endpoint auth overrides on the operation", func() {
+		cfg.Auth.Basic = &config.BasicAuthConfig{Users: []config.BasicUser{{Username: "a", Password: "b"}}}
+		cfg.Endpoints[0].Auth = &config.EndpointAuth{Type: "none"}
+		cfg.Endpoints[1].Auth = &config.EndpointAuth{Type: "basic"}
+		doc, err := Export(cfg, render.New())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(doc.Paths["/users/{id}"].Get.Security).To(Equal(&[]SecurityRequirement{}))
+		Expect(doc.Paths["/users"].Post.Security).To(Equal(&[]SecurityRequirement{{"basicAuth": {}}}))
+		Expect(doc.Components.SecuritySchemes).To(HaveKey("basicAuth"))
+		Expect(doc.Paths["/files/{wildcard}"].Get.Security).To(BeNil())
+	})
+
+	It("documents combined providers as alternatives or as one requirement", func() {
+		cfg.Auth.Type = "any"
+		cfg.Auth.Providers = []string{"token", "basic"}
+		cfg.Auth.Basic = &config.BasicAuthConfig{Users: []config.BasicUser{{Username: "a", Password: "b"}}}
+		cfg.Endpoints[1].Auth = &config.EndpointAuth{Type: "all"}
+		doc, err := Export(cfg, render.New())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(doc.Security).To(Equal([]SecurityRequirement{{"bearerAuth": {}}, {"basicAuth": {}}}))
+		Expect(doc.Components.SecuritySchemes).To(HaveKey("basicAuth"))
+		Expect(doc.Paths["/users"].Post.Security).To(Equal(&[]SecurityRequirement{{"bearerAuth": {}, "basicAuth": {}}}))
+	})
+
+	It("lists required scopes and roles on the operation", func() {
+		cfg.Endpoints[1].Requires = &config.Requirement{Scopes: []string{"users:write"}, Roles: []string{"admin"}}
+		doc, err := Export(cfg, render.New())
+		Expect(err).NotTo(HaveOccurred())
+
+		post := doc.Paths["/users"].Post
+		Expect(post.Security).To(Equal(&[]SecurityRequirement{{"bearerAuth": {"users:write", "admin"}}}))
+		Expect(post.Responses).To(HaveKey("403"))
+	})
+
+	It("reports unreadable schema files", func() {
+		cfg.Endpoints[1].Validate.SchemaFile = filepath.Join(dir, "gone.json")
+		doc, err := Export(cfg, render.New())
+		Expect(errors.Is(err, ErrRead)).To(BeTrue())
+		Expect(doc.Paths).To(HaveKey("/users"))
+	})
+
+	It("produces a document mocker can import again", func() {
+		doc, err := Export(cfg, render.New())
+		Expect(err).NotTo(HaveOccurred())
+		b, err := json.Marshal(doc)
+		Expect(err).NotTo(HaveOccurred())
+
+		again, err := Parse(b)
+		Expect(err).NotTo(HaveOccurred())
+		out := Import(again, filepath.Join(dir, "bodies"), filepath.Join(dir, "schemas"))
+		Expect(out.Config.Server.BasePath).To(Equal("/api"))
+		Expect(out.Config.Auth.Type).To(Equal("token"))
+		Expect(out.Write(filepath.Join(dir, "config.yaml"))).To(Succeed())
+		Expect(out.Config.Validate()).To(Succeed())
+	})
+})
@@ -0,0 +1,346 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"mime"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/fake"
+)
+
+// PreferHeader selects a non-default response of an imported endpoint,
+// e.g. "Prefer: code=404".
+const PreferHeader = "Prefer"
+
+// Output is a generated config plus the body and schema files it references.
+type Output struct {
+	config.Bundle
+	// parts of the document that could not be mapped
+	Warnings []string
+
+	gen *fake.Generator
+}
+
+// Import converts every operation into an endpoint. The lowest 2xx
+// response becomes the default variant; the other documented codes are
+// served when the request carries "Prefer: code=<status>". JSON request
+// body schemas are written to schemasDir and wired to validate.schemaFile.
+func Import(doc *Document, bodiesDir, schemasDir string) *Output {
+	out := &Output{
+		Bundle: config.Bundle{Config: &config.Config{}, Files: map[string][]byte{}},
+		// a fixed seed keeps generated files stable between imports
+		gen: fake.New(0),
+	}
+	out.Config.Server.BasePath = doc.BasePath()
+	out.Config.Auth = out.auth(doc)
+
+	names := map[string]int{}
+	for _, path := range slices.Sorted(maps.Keys(doc.Paths)) {
+		item := doc.Paths[path]
+		if item == nil {
+			continue
+		}
+		if item.Ref != "" {
+			out.warnf("%s: path item references are not supported", path)
+			continue
+		}
+		for _, method := range Methods {
+			op := item.Operation(method)
+			if op == nil {
+				continue
+			}
+			if !slices.Contains(config.Methods, method) {
+				out.warnf("%s %s: method not supported by mocker", method, path)
+				continue
+			}
+			if ep, ok := out.endpoint(doc, method, path, op, uniqueName(names, operationName(method, path, op)), bodiesDir, schemasDir); ok {
+				out.Config.Endpoints = append(out.Config.Endpoints, ep)
+			}
+		}
+	}
+
+	out.Config.ApplyDefaults()
+	return out
+}
+
+func (o *Output) warnf(format string, args ...any) {
+	o.Warnings = append(o.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (o *Output) endpoint(doc *Document, method, path string, op *Operation, name, bodiesDir, schemasDir string) (config.Endpoint, bool) {
+	scope := method + " " + path
+	ep := config.Endpoint{Method: method, Path: ChiPath(path)}
+
+	if op.RequestBody != nil {
+		rb, err := doc.RequestBody(op.RequestBody)
+		if err != nil {
+			o.warnf("%s: %v", scope, err)
+		} else if mt, media, ok := pickMedia(rb.Content); ok && media.Schema != nil && isJSON(mt) {
+			file := filepath.Join(schemasDir, name+".schema.json")
+			b, _ := json.MarshalIndent(doc.Bundle(media.Schema), "", "  ")
+			o.Files[file] = b
+			ep.Validate = &config.ValidateSpec{ContentType: mt, SchemaFile: file}
+		}
+	}
+
+	codes, primary := responseCodes(op)
+	seen := map[int]bool{}
+	for _, code := range codes {
+		status := responseStatus(code)
+		if seen[status] {
+			o.warnf("%s: response %s duplicates status %d", scope, code, status)
+			continue
+		}
+		resp, err := doc.Response(op.Responses[code])
+		if err != nil {
+			o.warnf("%s: %v", scope, err)
+			continue
+		}
+		seen[status] = true
+
+		rv := o.variant(doc, resp, status, fmt.Sprintf("%s_%d", name, status), bodiesDir)
+		if code != primary {
+			rv.When = &config.WhenClause{Header: map[string]config.Matcher{
+				PreferHeader: {Contains: "code=" + strconv.Itoa(status)},
+			}}
+		}
+		ep.Responses = append(ep.Responses, rv)
+	}
+
+	if len(ep.Responses) == 0 {
+		o.warnf("%s: no usable responses", scope)
+		return ep, false
+	}
+	// keep the default response first so it reads as the main one
+	slices.SortStableFunc(ep.Responses, func(a, b config.ResponseVariant) int {
+		return boolInt(!a.When.IsEmpty()) - boolInt(!b.When.IsEmpty())
+	})
+	return ep, true
+}
+
+func (o *Output) variant(doc *Document, resp *Response, status int, name, bodiesDir string) config.ResponseVariant {
+	rv := config.ResponseVariant{Status: status}
+
+	for _, h := range slices.Sorted(maps.Keys(resp.Headers)) {
+		hdr, err := doc.Header(resp.Headers[h])
+		if err != nil {
+			continue
+		}
+		v := hdr.Example
+		if v == nil {
+			v = doc.Sample(o.gen, hdr.Schema)
+		}
+		if v != nil && !strings.EqualFold(h, "Content-Type") {
+			setHeader(&rv, h, fmt.Sprint(v))
+		}
+	}
+
+	ext := ".txt"
+	mt, body := doc.responseBody(o.gen, resp, "")
+	if mt != "" {
+		setHeader(&rv, "Content-Type", mt)
+		if isJSON(mt) {
+			ext = ".json"
+		}
+	}
+
+	rv.BodyFile = filepath.Join(bodiesDir, name+ext)
+	o.Files[rv.BodyFile] = config.EscapeTemplate(body)
+	return rv
+}
+
+// responseCodes returns the documented response codes ordered by status
+// and the one served by default: the lowest 2xx, else the first.
+func responseCodes(op *Operation) ([]string, string) {
+	codes := slices.SortedFunc(maps.Keys(op.Responses), func(a, b string) int {
+		return responseStatus(a) - responseStatus(b)
+	})
+	for _, code := range codes {
+		if s := responseStatus(code); s >= 200 && s < 300 {
+			return codes, code
+		}
+	}
+	if len(codes) == 0 {
+		return nil, ""
+	}
+	return codes, codes[0]
+}
+
+// responseBody picks the response's media type and encodes its example:
+// the named example if given and present, else the media type's example,
+// else its first named example, else a sample of its schema.
+func (d *Document) responseBody(gen *fake.Generator, resp *Response, example string) (string, []byte) {
+	mt, media, ok := pickMedia(resp.Content)
+	if !ok {
+		return "", nil
+	}
+	v := d.mediaExample(gen, media, example)
+	if s, isString := v.(string); isString && !isJSON(mt) {
+		return mt, []byte(s)
+	}
+	if v == nil && !isJSON(mt) {
+		return mt, nil
+	}
+	b, _ := json.MarshalIndent(v, "", "  ")
+	return mt, b
+}
+
+func (d *Document) mediaExample(gen *fake.Generator, m MediaType, name string) any {
+	if ex, ok := m.Examples[name]; ok {
+		if ex, err := d.Example(ex); err == nil && ex.Value != nil {
+			return ex.Value
+		}
+	}
+	if m.Example != nil {
+		return m.Example
+	}
+	for _, name := range slices.Sorted(maps.Keys(m.Examples)) {
+		if ex, err := d.Example(m.Examples[name]); err == nil && ex.Value != nil {
+			return ex.Value
+		}
+	}
+	return d.Sample(gen, m.Schema)
+}
+
+func setHeader(rv *config.ResponseVariant, k, v string) {
+	if rv.Headers == nil {
+		rv.Headers = map[string]string{}
+	}
+	rv.Headers[k] = v
+}
+
+// auth maps the document's first security requirement onto auth. Tokens
+// and credentials are placeholders to be replaced by the user.
+func (o *Output) auth(doc *Document) config.AuthConfig {
+	none := config.AuthConfig{Type: "none"}
+	if len(doc.Security) == 0 || len(doc.Security[0]) == 0 {
+		return none
+	}
+	name := slices.Sorted(maps.Keys(doc.Security[0]))[0]
+	ss := doc.Components.SecuritySchemes[name]
+	if ss == nil {
+		o.warnf("security scheme %q is not defined", name)
+		return none
+	}
+
+	bearer := config.AuthConfig{Type: "token", Token: &config.TokenAuthConfig{
+		Header: "Authorization", Prefix: "Bearer ", Tokens: []string{"changeme"},
+	}}
+	switch {
+	case ss.Type == "http" && strings.EqualFold(ss.Scheme, "basic"):
+		return config.AuthConfig{Type: "basic", Basic: &config.BasicAuthConfig{
+			Users: []config.BasicUser{{Username: "user", Password: "changeme"}},
+		}}
+	case ss.Type == "http" && strings.EqualFold(ss.Scheme, "bearer"), ss.Type == "oauth2", ss.Type == "openIdConnect":
+		return bearer
+	case ss.Type == "apiKey" && ss.In == "header":
+		return config.AuthConfig{Type: "token", Token: &config.TokenAuthConfig{
+			Header: ss.Name, Tokens: []string{"changeme"},
+		}}
+	}
+	o.warnf("security scheme %q (%s) cannot be mapped, auth disabled", name, ss.Type)
+	return none
+}
+
+// BasePath is the path of the first server URL, with variables replaced by
+// their defaults.
+func (d *Document) BasePath() string {
+	if len(d.Servers) == 0 {
+		return "/"
+	}
+	s := d.Servers[0]
+	raw := s.URL
+	for k, v := range s.Variables {
+		raw = strings.ReplaceAll(raw, "{"+k+"}", v.Default)
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
+var pathParam = regexp.MustCompile(`\{([^}]*)\}`)
+
+// ChiPath converts an OpenAPI path template to a chi route, keeping
+// parameter names usable in templates (.Path.name).
+func ChiPath(path string) string {
+	return pathParam.ReplaceAllStringFunc(path, func(m string) string {
+		return "{" + ParamName(m[1:len(m)-1]) + "}"
+	})
+}
+
+var unsafeParam = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// ParamName is the chi name used for an OpenAPI path parameter.
+func ParamName(name string) string {
+	return unsafeParam.ReplaceAllString(name, "_")
+}
+
+// responseStatus maps a response key to a status: ranges like 4XX become
+// their first code and "default" becomes 500.
+func responseStatus(code string) int {
+	if n, err := strconv.Atoi(code); err == nil {
+		return n
+	}
+	if len(code) == 3 && strings.EqualFold(code[1:], "XX") && code[0] >= '1' && code[0] <= '5' {
+		return int(code[0]-'0') * 100
+	}
+	return 500
+}
+
+// pickMedia prefers JSON and falls back to the first media type by name.
+func pickMedia(content map[string]MediaType) (string, MediaType, bool) {
+	types := slices.Sorted(maps.Keys(content))
+	for _, mt := range types {
+		if isJSON(mt) {
+			return mt, content[mt], true
+		}
+	}
+	if len(types) == 0 {
+		return "", MediaType{}, false
+	}
+	return types[0], content[types[0]], true
+}
+
+func isJSON(contentType string) bool {
+	mt, _, _ := mime.ParseMediaType(contentType)
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
+
+func operationName(method, path string, op *Operation) string {
+	if n := config.FileName(op.OperationID); n != "" {
+		return n
+	}
+	n := config.FileName(path)
+	if n == "" {
+		n = "root"
+	}
+	return method + "_" + n
+}
+
+func uniqueName(names map[string]int, base string) string {
+	names[base]++
+	if n := names[base]; n > 1 {
+		return fmt.Sprintf("%s_%d", base, n)
+	}
+	return base
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
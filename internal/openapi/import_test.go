@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package openapi
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/validate"
+)
+
+var _ = Describe("Import", func() {
+	var (
+		dir string
+		out *Output
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		doc, err := Load(filepath.Join("testdata", "petstore.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		out = Import(doc, filepath.Join(dir, "bodies"), filepath.Join(dir, "schemas"))
+	})
+
+	endpoint := func(method, path string) config.Endpoint {
+		for _, ep := range out.Config.Endpoints {
+			if ep.Method == method && ep.Path == path {
+				return ep
+			}
+		}
+		Fail("no endpoint " + method + " " + path)
+		return config.Endpoint{}
+	}
+
+	It("maps servers, security and operations", func() {
+		Expect(out.Config.Server.BasePath).To(Equal("/v1"))
+		Expect(out.Config.Auth.Type).To(Equal("token"))
+		Expect(out.Config.Auth.Token.Prefix).To(Equal("Bearer "))
+		Expect(out.Config.Endpoints).To(HaveLen(4))
+		Expect(out.Warnings).To(ConsistOf(ContainSubstring("HEAD /pets")))
+	})
+
+	It("creates a variant per response code", func() {
+		ep := endpoint("POST", "/pets")
+		Expect(ep.Responses).To(HaveLen(2))
+		Expect(ep.Responses[0].Status).To(Equal(201))
+		Expect(ep.Responses[0].When).To(BeNil())
+		Expect(out.Files[ep.Responses[0].BodyFile]).To(MatchJSON(`{"id":7,"name":"Rex"}`))
+
+		Expect(ep.Responses[1].Status).To(Equal(400))
+		Expect(ep.Responses[1].When.Header).To(Equal(map[string]config.Matcher{"Prefer": {Contains: "code=400"}}))
+		var errBody map[string]any
+		Expect(json.Unmarshal(out.Files[ep.Responses[1].BodyFile], &errBody)).To(Succeed())
+		Expect(errBody).To(HaveKeyWithValue("code", BeNumerically("==", 404)))
+
+		list := endpoint("GET", "/pets").Responses[0]
+		Expect(list.Headers).To(Equal(map[string]string{"Content-Type": "application/json", "X-Total-Count": "2"}))
+		var pets []map[string]any
+		Expect(json.Unmarshal(out.Files[list.BodyFile], &pets)).To(Succeed())
+		Expect(pets).NotTo(BeEmpty())
+		for _, pet := range pets {
+			Expect(pet).To(HaveKeyWithValue("id", BeNumerically(">=", 1)))
+			Expect(pet).To(HaveKey("name"))
+		}
+
+		del := endpoint("DELETE", "/pets/{pet_id}").Responses[0]
+		Expect(del.Status).To(Equal(204))
+		Expect(out.Files[del.BodyFile]).To(BeEmpty())
+	})
+
+	It("generates the same sample bodies on every import", func() {
+		doc, err := Load(filepath.Join("testdata", "petstore.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(Import(doc, filepath.Join(dir, "bodies"), filepath.Join(dir, "schemas")).Files).To(Equal(out.Files))
+	})
+
+	It("escapes template syntax in examples", func() {
+		rv := endpoint("GET", "/pets/{pet_id}").Responses[0]
+		Expect(string(out.Files[rv.BodyFile])).To(ContainSubstring(`{{"{{"}} not a template }}`))
+	})
+
+	It("writes request body schemas for validation", func() {
+		ep := endpoint("POST", "/pets")
+		Expect(ep.Validate.ContentType).To(Equal("application/json"))
+		Expect(ep.Validate.SchemaFile).To(Equal(filepath.Join(dir, "schemas", "createPet.schema.json")))
+
+		Expect(out.Write(filepath.Join(dir, "config.yaml"))).To(Succeed())
+		v, err := validate.CompileSchema(ep.Validate.SchemaFile, validate.JSONSchemaValidatorOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v.Validate([]byte(`{"name":"rex","tag":null}`))).To(Succeed())
+		Expect(v.Validate([]byte(`{"tag":"x"}`))).NotTo(Succeed())
+	})
+
+	It("writes a config that loads", func() {
+		path := filepath.Join(dir, "config.yaml")
+		Expect(out.Write(path)).To(Succeed())
+		_, err := os.Stat(filepath.Join(dir, "bodies", "deletePet_204.txt"))
+		Expect(err).NotTo(HaveOccurred())
+
+		cfg, err := config.Load(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Endpoints).To(HaveLen(4))
+	})
+})
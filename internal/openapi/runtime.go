@@ -0,0 +1,352 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/fake"
+	"github.com/Bl4cky99/mocker/internal/validate"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// Route is an operation prepared for serving: validators for its
+// parameters and request body plus the responses it can produce.
+type Route struct {
+	Method string
+	// chi route pattern
+	Path string
+
+	params       []routeParam
+	bodyRequired bool
+	// media type -> validator, nil for media types without a JSON schema
+	bodies    map[string]*validate.JSONSchemaValidator
+	replies   map[int]*Reply
+	named     map[int]map[string]*Reply
+	byDefault int
+}
+
+type routeParam struct {
+	name, in string
+	// chi parameter name for path parameters
+	key      string
+	required bool
+	schema   Schema
+	v        *validate.JSONSchemaValidator
+}
+
+// Reply is a response ready to be written.
+type Reply struct {
+	Status  int
+	Headers map[string]string
+	Body    []byte
+}
+
+// RequestError lists why a request does not match its operation.
+type RequestError struct {
+	// 400 for invalid parameters and bodies, 415 for unknown content types
+	Status int
+	Issues []string
+}
+
+func (e *RequestError) Error() string {
+	return strings.Join(e.Issues, "; ")
+}
+
+var schemaOptions = validate.JSONSchemaValidatorOptions{AssertFormat: true, DefaultDraft: jsonschema.Draft2020}
+
+// Routes compiles every operation mocker can serve, sampling bodies without
+// an example with gen. Operations that fail to compile are skipped and
+// reported in the joined error.
+func (d *Document) Routes(gen *fake.Generator) ([]*Route, error) {
+	var (
+		out  []*Route
+		errs []error
+		n    int
+	)
+	compile := func(s Schema) (*validate.JSONSchemaValidator, error) {
+		n++
+		return validate.CompileValue(fmt.Sprintf("mem://openapi/%d.json", n), d.Bundle(s), schemaOptions)
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(d.Paths)) {
+		item := d.Paths[path]
+		if item == nil || item.Ref != "" {
+			continue
+		}
+		for _, method := range Methods {
+			op := item.Operation(method)
+			if op == nil {
+				continue
+			}
+			if !slices.Contains(config.Methods, method) {
+				continue
+			}
+			rt, err := d.route(gen, method, path, item, op, compile)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s %s: %w", method, path, err))
+				continue
+			}
+			out = append(out, rt)
+		}
+	}
+	return out, errors.Join(errs...)
+}
+
+func (d *Document) route(gen *fake.Generator, method, path string, item *PathItem, op *Operation, compile func(Schema) (*validate.JSONSchemaValidator, error)) (*Route, error) {
+	rt := &Route{
+		Method:  method,
+		Path:    ChiPath(path),
+		bodies:  map[string]*validate.JSONSchemaValidator{},
+		replies: map[int]*Reply{},
+		named:   map[int]map[string]*Reply{},
+	}
+
+	// operation parameters override path item parameters with the same name and location
+	params := map[string]*Parameter{}
+	var order []string
+	for _, p := range slices.Concat(item.Parameters, op.Parameters) {
+		p, err := d.Parameter(p)
+		if err != nil {
+			return nil, err
+		}
+		key := p.In + "\x00" + p.Name
+		if _, ok := params[key]; !ok {
+			order = append(order, key)
+		}
+		params[key] = p
+	}
+	for _, key := range order {
+		p := params[key]
+		rp := routeParam{name: p.Name, in: p.In, required: p.Required || p.In == "path", schema: d.resolveSchema(p.Schema)}
+		if p.In == "path" {
+			rp.key = ParamName(p.Name)
+		}
+		if p.Schema != nil {
+			v, err := compile(p.Schema)
+			if err != nil {
+				return nil, err
+			}
+			rp.v = v
+		}
+		rt.params = append(rt.params, rp)
+	}
+
+	if op.RequestBody != nil {
+		rb, err := d.RequestBody(op.RequestBody)
+		if err != nil {
+			return nil, err
+		}
+		rt.bodyRequired = rb.Required
+		for mt, media := range rb.Content {
+			var v *validate.JSONSchemaValidator
+			if media.Schema != nil && isJSON(mt) {
+				if v, err = compile(media.Schema); err != nil {
+					return nil, err
+				}
+			}
+			rt.bodies[strings.ToLower(mt)] = v
+		}
+	}
+
+	codes, primary := responseCodes(op)
+	for _, code := range codes {
+		status := responseStatus(code)
+		if _, dup := rt.replies[status]; dup {
+			continue
+		}
+		resp, err := d.Response(op.Responses[code])
+		if err != nil {
+			return nil, err
+		}
+		rt.replies[status] = d.reply(gen, resp, status, "")
+		if code == primary {
+			rt.byDefault = status
+		}
+		if _, media, ok := pickMedia(resp.Content); ok && len(media.Examples) > 0 {
+			rt.named[status] = map[string]*Reply{}
+			for name := range media.Examples {
+				rt.named[status][name] = d.reply(gen, resp, status, name)
+			}
+		}
+	}
+	if len(rt.replies) == 0 {
+		return nil, errors.New("no responses")
+	}
+	return rt, nil
+}
+
+func (d *Document) reply(gen *fake.Generator, resp *Response, status int, example string) *Reply {
+	r := &Reply{Status: status, Headers: map[string]string{}}
+	for name, h := range resp.Headers {
+		hdr, err := d.Header(h)
+		if err != nil || strings.EqualFold(name, "Content-Type") {
+			continue
+		}
+		v := hdr.Example
+		if v == nil {
+			v = d.Sample(gen, hdr.Schema)
+		}
+		if v != nil {
+			r.Headers[name] = fmt.Sprint(v)
+		}
+	}
+	mt, body := d.responseBody(gen, resp, example)
+	if mt != "" {
+		r.Headers["Content-Type"] = mt
+	}
+	r.Body = body
+	return r
+}
+
+// resolveSchema follows a top-level $ref so the type of a parameter is
+// known when coercing its raw value.
+func (d *Document) resolveSchema(s Schema) Schema {
+	for range maxRefDepth {
+		ref, ok := s["$ref"].(string)
+		if !ok {
+			return s
+		}
+		next := d.SchemaRef(ref)
+		if next == nil {
+			return s
+		}
+		s = next
+	}
+	return s
+}
+
+// Validate checks the parameters and body of r. pathParam returns the raw
+// value of a chi path parameter.
+func (rt *Route) Validate(r *http.Request, pathParam func(string) string, body []byte) error {
+	var issues []string
+	query := r.URL.Query()
+
+	for _, p := range rt.params {
+		var vals []string
+		switch p.in {
+		case "path":
+			vals = []string{pathParam(p.key)}
+		case "query":
+			vals = query[p.name]
+		case "header":
+			vals = r.Header.Values(p.name)
+		case "cookie":
+			if c, err := r.Cookie(p.name); err == nil {
+				vals = []string{c.Value}
+			}
+		}
+		if len(vals) == 0 {
+			if p.required {
+				issues = append(issues, fmt.Sprintf("%s parameter %q is required", p.in, p.name))
+			}
+			continue
+		}
+		if p.v == nil {
+			continue
+		}
+		if err := p.v.ValidateValue(coerce(vals, p.schema)); err != nil {
+			for _, detail := range validate.Details(err) {
+				issues = append(issues, fmt.Sprintf("%s parameter %q: %s", p.in, p.name, detail))
+			}
+		}
+	}
+
+	if len(body) == 0 {
+		if rt.bodyRequired {
+			issues = append(issues, "request body is required")
+		}
+	} else if len(rt.bodies) > 0 {
+		mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		v, ok := rt.bodyValidator(mt)
+		if !ok {
+			return &RequestError{Status: http.StatusUnsupportedMediaType, Issues: []string{fmt.Sprintf("content type %q is not accepted", mt)}}
+		}
+		if v != nil {
+			if err := v.Validate(body); err != nil {
+				for _, detail := range validate.Details(err) {
+					issues = append(issues, "request body: "+detail)
+				}
+			}
+		}
+	}
+
+	if len(issues) > 0 {
+		return &RequestError{Status: http.StatusBadRequest, Issues: issues}
+	}
+	return nil
+}
+
+// bodyValidator matches a media type exactly, then by type/* and */*.
+func (rt *Route) bodyValidator(mt string) (*validate.JSONSchemaValidator, bool) {
+	mt = strings.ToLower(mt)
+	typ, _, _ := strings.Cut(mt, "/")
+	for _, key := range []string{mt, typ + "/*", "*/*"} {
+		if v, ok := rt.bodies[key]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// coerce converts raw parameter values to the JSON types their schema
+// expects; arrays accept repeated values or a comma separated list.
+func coerce(vals []string, s Schema) any {
+	if schemaType(s) != "array" {
+		return scalar(vals[0], s)
+	}
+	if len(vals) == 1 {
+		vals = strings.Split(vals[0], ",")
+	}
+	items := asSchema(s["items"])
+	out := make([]any, len(vals))
+	for i, v := range vals {
+		out[i] = scalar(v, items)
+	}
+	return out
+}
+
+func scalar(v string, s Schema) any {
+	switch schemaType(s) {
+	case "integer", "number":
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return v
+}
+
+// Reply picks the response for r. "Prefer: code=404" selects another
+// documented status and "Prefer: example=name" a named example.
+func (rt *Route) Reply(r *http.Request) *Reply {
+	status, example := rt.byDefault, ""
+	for _, part := range strings.FieldsFunc(r.Header.Get("Prefer"), func(c rune) bool { return c == ',' || c == ';' }) {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		v = strings.Trim(strings.TrimSpace(v), `"`)
+		switch strings.ToLower(k) {
+		case "code":
+			if n, err := strconv.Atoi(v); err == nil && rt.replies[n] != nil {
+				status = n
+			}
+		case "example":
+			example = v
+		}
+	}
+	if r, ok := rt.named[status][example]; ok {
+		return r
+	}
+	return rt.replies[status]
+}
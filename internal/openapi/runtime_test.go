@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/fake"
+)
+
+var _ = Describe("Routes", func() {
+	var routes map[string]*Route
+
+	BeforeEach(func() {
+		doc, err := Load(filepath.Join("testdata", "petstore.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		list, err := doc.Routes(fake.New(1))
+		Expect(err).NotTo(HaveOccurred())
+		routes = map[string]*Route{}
+		for _, rt := range list {
+			routes[rt.Method+" "+rt.Path] = rt
+		}
+	})
+
+	noPath := func(string) string { return "" }
+
+	requestError := func(err error) *RequestError {
+		var re *RequestError
+		Expect(errors.As(err, &re)).To(BeTrue())
+		return re
+	}
+
+	It("compiles the servable operations", func() {
+		Expect(routes).To(HaveLen(4))
+		Expect(routes).To(HaveKey("GET /pets/{pet_id}"))
+	})
+
+	It("validates coerced parameters", func() {
+		rt := routes["GET /pets"]
+		Expect(rt.Validate(httptest.NewRequest("GET", "/pets?limit=10", nil), noPath, nil)).To(Succeed())
+
+		re := requestError(rt.Validate(httptest.NewRequest("GET", "/pets?limit=500", nil), noPath, nil))
+		Expect(re.Status).To(Equal(http.StatusBadRequest))
+		Expect(re.Issues).To(ConsistOf(ContainSubstring(`query parameter "limit"`)))
+
+		Expect(rt.Validate(httptest.NewRequest("GET", "/pets?limit=abc", nil), noPath, nil)).NotTo(Succeed())
+
+		one := routes["GET /pets/{pet_id}"]
+		Expect(one.Validate(httptest.NewRequest("GET", "/pets/1", nil), func(k string) string {
+			Expect(k).To(Equal("pet_id"))
+			return "7"
+		}, nil)).To(Succeed())
+		Expect(one.Validate(httptest.NewRequest("GET", "/pets/x", nil), func(string) string { return "x" }, nil)).NotTo(Succeed())
+	})
+
+	It("validates request bodies and content types", func() {
+		rt := routes["POST /pets"]
+		post := func(ct, body string) error {
+			req := httptest.NewRequest("POST", "/pets", strings.NewReader(body))
+			req.Header.Set("Content-Type", ct)
+			return rt.Validate(req, noPath, []byte(body))
+		}
+
+		Expect(post("application/json", `{"name":"rex","tag":null}`)).To(Succeed())
+		Expect(requestError(post("application/json", "")).Issues).To(ConsistOf("request body is required"))
+		Expect(requestError(post("application/json", `{"tag":"x"}`)).Issues).To(ConsistOf(ContainSubstring("request body:")))
+		Expect(requestError(post("text/plain", "rex")).Status).To(Equal(http.StatusUnsupportedMediaType))
+	})
+
+	It("replies with the default response or the preferred one", func() {
+		rt := routes["POST /pets"]
+		req := httptest.NewRequest("POST", "/pets", nil)
+
+		r := rt.Reply(req)
+		Expect(r.Status).To(Equal(201))
+		Expect(r.Headers).To(HaveKeyWithValue("Content-Type", "application/json"))
+		Expect(r.Body).To(MatchJSON(`{"id":7,"name":"Rex"}`))
+
+		req.Header.Set("Prefer", "code=400")
+		Expect(rt.Reply(req).Status).To(Equal(400))
+		var errBody map[string]any
+		Expect(json.Unmarshal(rt.Reply(req).Body, &errBody)).To(Succeed())
+		Expect(errBody).To(HaveKeyWithValue("code", BeNumerically("==", 404)))
+
+		req.Header.Set("Prefer", "code=418")
+		Expect(rt.Reply(req).Status).To(Equal(201))
+
+		req.Header.Set("Prefer", `code=201, example="rex"`)
+		Expect(rt.Reply(req).Body).To(MatchJSON(`{"id":7,"name":"Rex"}`))
+
+		list := routes["GET /pets"].Reply(httptest.NewRequest("GET", "/pets", nil))
+		Expect(list.Headers).To(HaveKeyWithValue("X-Total-Count", "2"))
+	})
+})
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package openapi
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Bl4cky99/mocker/internal/fake"
+)
+
+const (
+	componentSchemas = "#/components/schemas/"
+	localDefs        = "#/$defs/"
+	draft2020        = "https://json-schema.org/draft/2020-12/schema"
+)
+
+// SchemaRef returns the component schema a local $ref points to, or nil.
+func (d *Document) SchemaRef(ref string) Schema {
+	name, ok := strings.CutPrefix(ref, componentSchemas)
+	if !ok || strings.Contains(name, "/") {
+		return nil
+	}
+	return d.Components.Schemas[unescapePointer(name)]
+}
+
+// Bundle returns a self-contained JSON Schema (draft 2020-12) for s: the
+// component schemas it references are copied into $defs and OpenAPI 3.0
+// keywords (nullable, boolean exclusive bounds) are rewritten.
+func (d *Document) Bundle(s Schema) Schema {
+	defs := map[string]any{}
+
+	var walk func(v any) any
+	walk = func(v any) any {
+		switch t := v.(type) {
+		case map[string]any:
+			out := make(map[string]any, len(t))
+			for k, val := range t {
+				out[k] = walk(val)
+			}
+			if ref, ok := t["$ref"].(string); ok {
+				if rest, ok := strings.CutPrefix(ref, componentSchemas); ok {
+					out["$ref"] = localDefs + rest
+					name, _, _ := strings.Cut(rest, "/")
+					if _, seen := defs[name]; !seen {
+						defs[name] = nil
+						if c, ok := d.Components.Schemas[unescapePointer(name)]; ok {
+							defs[name] = walk(c)
+						}
+					}
+				}
+			}
+			if d.is30() {
+				upgrade30(out)
+			}
+			return out
+		case []any:
+			out := make([]any, len(t))
+			for i := range t {
+				out[i] = walk(t[i])
+			}
+			return out
+		default:
+			return v
+		}
+	}
+
+	out, _ := walk(s).(map[string]any)
+	if out == nil {
+		out = Schema{}
+	}
+	for name, def := range defs {
+		if def == nil {
+			delete(defs, name)
+		}
+	}
+	if len(defs) > 0 {
+		out["$defs"] = defs
+	}
+	out["$schema"] = draft2020
+	return out
+}
+
+// upgrade30 rewrites the OpenAPI 3.0 schema dialect into JSON Schema.
+func upgrade30(s map[string]any) {
+	if n, ok := s["nullable"].(bool); ok {
+		delete(s, "nullable")
+		if n {
+			if t, ok := s["type"].(string); ok {
+				s["type"] = []any{t, "null"}
+			}
+			if enum, ok := s["enum"].([]any); ok && !slices.Contains(enum, nil) {
+				s["enum"] = append(enum, nil)
+			}
+		}
+	}
+	for _, b := range [][2]string{{"exclusiveMinimum", "minimum"}, {"exclusiveMaximum", "maximum"}} {
+		if ex, ok := s[b[0]].(bool); ok {
+			delete(s, b[0])
+			if lim, ok := s[b[1]]; ok && ex {
+				s[b[0]] = lim
+				delete(s, b[1])
+			}
+		}
+	}
+}
+
+// Sample generates a value for s with gen, resolving component $refs.
+func (d *Document) Sample(gen *fake.Generator, s Schema) any {
+	return gen.SchemaWith(s, d.SchemaRef)
+}
+
+func asSchema(v any) Schema {
+	s, _ := v.(map[string]any)
+	return s
+}
+
+// schemaType returns the first non-null type of s, inferring object and
+// array from properties and items.
+func schemaType(s Schema) string {
+	switch t := s["type"].(type) {
+	case string:
+		return t
+	case []any:
+		for _, v := range t {
+			if str, ok := v.(string); ok && str != "null" {
+				return str
+			}
+		}
+	}
+	switch {
+	case s["properties"] != nil || s["additionalProperties"] != nil:
+		return "object"
+	case s["items"] != nil:
+		return "array"
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package openapi
+
+import (
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/fake"
+)
+
+var _ = Describe("schemas", func() {
+	var doc *Document
+
+	BeforeEach(func() {
+		var err error
+		doc, err = Load(filepath.Join("testdata", "petstore.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("bundles referenced components and upgrades 3.0 keywords", func() {
+		s := doc.Bundle(Schema{"type": "array", "items": Schema{"$ref": "#/components/schemas/Pet"}})
+		Expect(s["$schema"]).To(Equal(draft2020))
+		Expect(s["items"]).To(Equal(map[string]any{"$ref": "#/$defs/Pet"}))
+
+		defs := s["$defs"].(map[string]any)
+		Expect(defs).To(HaveKey("Pet"))
+		Expect(defs).To(HaveKey("NewPet"))
+		tag := defs["NewPet"].(map[string]any)["properties"].(map[string]any)["tag"]
+		Expect(tag).To(Equal(map[string]any{"type": []any{"string", "null"}}))
+
+		Expect(doc.Bundle(Schema{"type": "integer", "minimum": 1.0, "exclusiveMinimum": true})).
+			To(Equal(Schema{"type": "integer", "exclusiveMinimum": 1.0, "$schema": draft2020}))
+	})
+
+	It("samples values with the fake generator", func() {
+		gen := fake.New(1)
+		Expect(doc.Sample(gen, Schema{"type": "string", "example": "x", "enum": []any{"y"}})).To(Equal("x"))
+		Expect(doc.Sample(gen, Schema{"type": "integer", "minimum": 5.0, "maximum": 5.0})).To(Equal(int64(5)))
+		Expect(doc.Sample(gen, nil)).To(BeNil())
+
+		pet := doc.Sample(gen, Schema{"$ref": "#/components/schemas/Pet"})
+		Expect(pet).To(HaveKeyWithValue("id", BeNumerically(">=", 1)))
+		Expect(pet).To(HaveKeyWithValue("name", BeAssignableToTypeOf("")))
+	})
+
+	It("is reproducible with the same seed", func() {
+		s := Schema{"$ref": "#/components/schemas/Pet"}
+		Expect(doc.Sample(fake.New(7), s)).To(Equal(doc.Sample(fake.New(7), s)))
+	})
+
+	It("stops at recursive references", func() {
+		doc.Components.Schemas["Node"] = Schema{"type": "object", "required": []any{"next"}, "properties": map[string]any{
+			"next": map[string]any{"$ref": "#/components/schemas/Node"},
+		}}
+		Expect(doc.Sample(fake.New(1), Schema{"$ref": "#/components/schemas/Node"})).To(HaveKey("next"))
+	})
+})
@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is a JSON Schema object in its generic decoded form.
+type Schema = map[string]any
+
+// Document is the subset of an OpenAPI 3.0/3.1 document mocker works with.
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers,omitempty"`
+	Paths      map[string]*PathItem  `json:"paths,omitempty"`
+	Components *Components           `json:"components,omitempty"`
+	Security   []SecurityRequirement `json:"security,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL       string                    `json:"url"`
+	Variables map[string]ServerVariable `json:"variables,omitempty"`
+}
+
+type ServerVariable struct {
+	Default string `json:"default"`
+}
+
+type PathItem struct {
+	Ref        string       `json:"$ref,omitempty"`
+	Parameters []*Parameter `json:"parameters,omitempty"`
+	Get        *Operation   `json:"get,omitempty"`
+	Put        *Operation   `json:"put,omitempty"`
+	Post       *Operation   `json:"post,omitempty"`
+	Delete     *Operation   `json:"delete,omitempty"`
+	Options    *Operation   `json:"options,omitempty"`
+	Head       *Operation   `json:"head,omitempty"`
+	Patch      *Operation   `json:"patch,omitempty"`
+	Trace      *Operation   `json:"trace,omitempty"`
+}
+
+type Operation struct {
+	OperationID string                 `json:"operationId,omitempty"`
+	Summary     string                 `json:"summary,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	Parameters  []*Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]*Response   `json:"responses"`
+	Security    *[]SecurityRequirement `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Ref      string `json:"$ref,omitempty"`
+	Name     string `json:"name,omitempty"`
+	In       string `json:"in,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	Schema   Schema `json:"schema,omitempty"`
+	Example  any    `json:"example,omitempty"`
+}
+
+type RequestBody struct {
+	Ref      string               `json:"$ref,omitempty"`
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content,omitempty"`
+}
+
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description"`
+	Headers     map[string]*Header   `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Ref     string `json:"$ref,omitempty"`
+	Schema  Schema `json:"schema,omitempty"`
+	Example any    `json:"example,omitempty"`
+}
+
+type MediaType struct {
+	Schema   Schema              `json:"schema,omitempty"`
+	Example  any                 `json:"example,omitempty"`
+	Examples map[string]*Example `json:"examples,omitempty"`
+}
+
+type Example struct {
+	Ref     string `json:"$ref,omitempty"`
+	Summary string `json:"summary,omitempty"`
+	Value   any    `json:"value,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]Schema          `json:"schemas,omitempty"`
+	Parameters      map[string]*Parameter      `json:"parameters,omitempty"`
+	RequestBodies   map[string]*RequestBody    `json:"requestBodies,omitempty"`
+	Responses       map[string]*Response       `json:"responses,omitempty"`
+	Headers         map[string]*Header         `json:"headers,omitempty"`
+	Examples        map[string]*Example        `json:"examples,omitempty"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	// "http" | "apiKey" | "oauth2" | "openIdConnect" | "mutualTLS"
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+}
+
+// SecurityRequirement maps security scheme names to required scopes.
+type SecurityRequirement map[string][]string
+
+// Methods lists the operation methods of a path item in document order.
+var Methods = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"}
+
+// Operation returns the operation for an upper-case method, or nil.
+func (p *PathItem) Operation(method string) *Operation {
+	switch method {
+	case "GET":
+		return p.Get
+	case "PUT":
+		return p.Put
+	case "POST":
+		return p.Post
+	case "DELETE":
+		return p.Delete
+	case "OPTIONS":
+		return p.Options
+	case "HEAD":
+		return p.Head
+	case "PATCH":
+		return p.Patch
+	case "TRACE":
+		return p.Trace
+	}
+	return nil
+}
+
+// Load reads an OpenAPI document in YAML or JSON.
+func Load(path string) (*Document, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %v", ErrRead, path, err)
+	}
+	doc, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", path, err)
+	}
+	return doc, nil
+}
+
+// Parse decodes an OpenAPI 3.0 or 3.1 document. JSON is accepted as a
+// subset of YAML.
+func Parse(b []byte) (*Document, error) {
+	var raw any
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+	js, err := json.Marshal(normalize(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+
+	var doc Document
+	if err := json.Unmarshal(js, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecode, err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.0") && !strings.HasPrefix(doc.OpenAPI, "3.1") {
+		return nil, fmt.Errorf("%w %q, want 3.0 or 3.1", ErrVersion, doc.OpenAPI)
+	}
+	if doc.Components == nil {
+		doc.Components = &Components{}
+	}
+	return &doc, nil
+}
+
+// normalize turns the map[any]any yaml produces for non-string keys into
+// map[string]any so the tree can be encoded as JSON.
+func normalize(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			t[k] = normalize(val)
+		}
+		return t
+	case map[any]any:
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			out[fmt.Sprint(k)] = normalize(val)
+		}
+		return out
+	case []any:
+		for i := range t {
+			t[i] = normalize(t[i])
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+func (d *Document) is30() bool {
+	return strings.HasPrefix(d.OpenAPI, "3.0")
+}
+
+const maxRefDepth = 16
+
+// resolve follows a chain of local component references.
+func resolve[T any](ref, kind string, defs map[string]*T, next func(*T) string) (*T, error) {
+	for range maxRefDepth {
+		name, ok := strings.CutPrefix(ref, "#/components/"+kind+"/")
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrRef, ref)
+		}
+		t := defs[unescapePointer(name)]
+		if t == nil {
+			return nil, fmt.Errorf("%w %q: not found", ErrRef, ref)
+		}
+		if ref = next(t); ref == "" {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %q: nested too deep", ErrRef, ref)
+}
+
+func unescapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+}
+
+func (d *Document) Parameter(p *Parameter) (*Parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	return resolve(p.Ref, "parameters", d.Components.Parameters, func(p *Parameter) string { return p.Ref })
+}
+
+func (d *Document) RequestBody(b *RequestBody) (*RequestBody, error) {
+	if b.Ref == "" {
+		return b, nil
+	}
+	return resolve(b.Ref, "requestBodies", d.Components.RequestBodies, func(b *RequestBody) string { return b.Ref })
+}
+
+func (d *Document) Response(r *Response) (*Response, error) {
+	if r.Ref == "" {
+		return r, nil
+	}
+	return resolve(r.Ref, "responses", d.Components.Responses, func(r *Response) string { return r.Ref })
+}
+
+func (d *Document) Header(h *Header) (*Header, error) {
+	if h.Ref == "" {
+		return h, nil
+	}
+	return resolve(h.Ref, "headers", d.Components.Headers, func(h *Header) string { return h.Ref })
+}
+
+func (d *Document) Example(e *Example) (*Example, error) {
+	if e.Ref == "" {
+		return e, nil
+	}
+	return resolve(e.Ref, "examples", d.Components.Examples, func(e *Example) string { return e.Ref })
+}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package openapi
+
+import (
+	"errors"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parse", func() {
+	It("accepts JSON documents", func() {
+		doc, err := Parse([]byte(`{"openapi":"3.1.0","info":{"title":"t","version":"1"},"paths":{"/a":{"get":{"responses":{"200":{"description":"ok"}}}}}}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(doc.Paths["/a"].Operation("GET")).NotTo(BeNil())
+		Expect(doc.Components).NotTo(BeNil())
+	})
+
+	It("rejects other versions and broken input", func() {
+		_, err := Parse([]byte("swagger: '2.0'\n"))
+		Expect(errors.Is(err, ErrVersion)).To(BeTrue())
+
+		_, err = Parse([]byte("openapi: [\n"))
+		Expect(errors.Is(err, ErrDecode)).To(BeTrue())
+
+		_, err = Load(filepath.Join("testdata", "missing.yaml"))
+		Expect(errors.Is(err, ErrRead)).To(BeTrue())
+	})
+
+	It("resolves component references", func() {
+		doc, err := Load(filepath.Join("testdata", "petstore.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+
+		p, err := doc.Parameter(doc.Paths["/pets/{pet-id}"].Get.Parameters[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Name).To(Equal("pet-id"))
+
+		r, err := doc.Response(doc.Paths["/pets"].Post.Responses["4XX"])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Description).To(Equal("error"))
+
+		_, err = doc.Response(&Response{Ref: "#/components/responses/Nope"})
+		Expect(errors.Is(err, ErrRef)).To(BeTrue())
+	})
+})
@@ -0,0 +1,287 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"mime"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+)
+
+// ignoredHeaders never distinguish variants: they are set by clients,
+// proxies or transports rather than chosen by the caller.
+var ignoredHeaders = map[string]struct{}{
+	"Accept-Encoding":   {},
+	"Connection":        {},
+	"Content-Length":    {},
+	"Cookie":            {},
+	"Date":              {},
+	"Forwarded":         {},
+	"Traceparent":       {},
+	"Tracestate":        {},
+	"User-Agent":        {},
+	"X-Forwarded-For":   {},
+	"X-Forwarded-Host":  {},
+	"X-Forwarded-Proto": {},
+	"X-Request-Id":      {},
+}
+
+// keptHeaders are the response headers copied into the generated variants.
+var keptHeaders = []string{"Content-Type", "Location"}
+
+// Output is a generated config plus the body files its variants reference.
+type Output struct {
+	config.Bundle
+}
+
+// Build groups exchanges into one endpoint per method and path, skipping
+// methods mocker cannot serve. Distinct
+// responses become variants whose when clauses use the query parameters and
+// headers that differ between them; the first recorded response is also
+// added as a fallback without conditions.
+func Build(exchanges []Exchange, bodiesDir string) *Output {
+	out := &Output{config.Bundle{
+		Config: &config.Config{Auth: config.AuthConfig{Type: "none"}},
+		Files:  make(map[string][]byte),
+	}}
+	names := map[string]int{}
+
+	var order []string
+	groups := map[string][]Exchange{}
+	for _, ex := range exchanges {
+		if !slices.Contains(config.Methods, ex.Method) {
+			continue
+		}
+		key := ex.Method + " " + ex.Path
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], ex)
+	}
+
+	for _, key := range order {
+		group := groups[key]
+		ep := config.Endpoint{Method: group[0].Method, Path: group[0].Path}
+
+		variants := splitByResponse(group)
+		if len(variants) == 1 {
+			ep.Responses = []config.ResponseVariant{out.variant(variants[0][0], bodiesDir, names)}
+			out.Config.Endpoints = append(out.Config.Endpoints, ep)
+			continue
+		}
+
+		queryKeys, headerKeys := distinguishingKeys(variants)
+		seen := map[string]struct{}{}
+		var conditional []config.ResponseVariant
+		for _, v := range variants {
+			when := whenFor(v, queryKeys, headerKeys)
+			sig := whenSignature(when)
+			if _, dup := seen[sig]; dup || when.IsEmpty() {
+				// indistinguishable from an earlier response, e.g. a flaky upstream
+				continue
+			}
+			seen[sig] = struct{}{}
+			rv := out.variant(v[0], bodiesDir, names)
+			rv.When = when
+			conditional = append(conditional, rv)
+		}
+		slices.SortStableFunc(conditional, func(a, b config.ResponseVariant) int {
+			return conditionCount(b.When) - conditionCount(a.When)
+		})
+
+		fallback := out.variant(variants[0][0], bodiesDir, names)
+		ep.Responses = append(conditional, fallback)
+		out.Config.Endpoints = append(out.Config.Endpoints, ep)
+	}
+
+	out.Config.ApplyDefaults()
+	return out
+}
+
+func responseSignature(ex Exchange) string {
+	return fmt.Sprintf("%d\x00%s\x00%s", ex.Status, ex.RespHeader.Get("Content-Type"), ex.Body)
+}
+
+// splitByResponse groups exchanges with identical responses, in order of
+// first appearance.
+func splitByResponse(group []Exchange) [][]Exchange {
+	var out [][]Exchange
+	idx := map[string]int{}
+	for _, ex := range group {
+		sig := responseSignature(ex)
+		i, ok := idx[sig]
+		if !ok {
+			i = len(out)
+			idx[sig] = i
+			out = append(out, nil)
+		}
+		out[i] = append(out[i], ex)
+	}
+	return out
+}
+
+// distinguishingKeys returns the query parameters and headers that take
+// different values in exchanges with different responses. Keys that also
+// vary between exchanges with the same response (cache busters, request
+// ids) are noise and left out.
+func distinguishingKeys(variants [][]Exchange) (query, header []string) {
+	qk, hk := map[string]struct{}{}, map[string]struct{}{}
+	for i := range variants {
+		for j := i + 1; j < len(variants); j++ {
+			for _, a := range variants[i] {
+				for _, b := range variants[j] {
+					for k := range unionKeys(a.Query, b.Query) {
+						if differs(a, b, queryValue(k)) {
+							qk[k] = struct{}{}
+						}
+					}
+					for k := range unionKeys(a.Header, b.Header) {
+						if _, skip := ignoredHeaders[k]; !skip && differs(a, b, headerValue(k)) {
+							hk[k] = struct{}{}
+						}
+					}
+				}
+			}
+		}
+	}
+	for _, v := range variants {
+		for k := range qk {
+			if _, ok := commonMatcher(v, queryValue(k)); !ok {
+				delete(qk, k)
+			}
+		}
+		for k := range hk {
+			if _, ok := commonMatcher(v, headerValue(k)); !ok {
+				delete(hk, k)
+			}
+		}
+	}
+	return slices.Sorted(maps.Keys(qk)), slices.Sorted(maps.Keys(hk))
+}
+
+func queryValue(k string) func(Exchange) (string, bool) {
+	return func(ex Exchange) (string, bool) { return ex.Query.Get(k), ex.Query.Has(k) }
+}
+
+func headerValue(k string) func(Exchange) (string, bool) {
+	return func(ex Exchange) (string, bool) { return ex.Header.Get(k), len(ex.Header.Values(k)) > 0 }
+}
+
+func differs(a, b Exchange, get func(Exchange) (string, bool)) bool {
+	av, ap := get(a)
+	bv, bp := get(b)
+	return av != bv || ap != bp
+}
+
+func unionKeys[M ~map[string][]string](a, b M) map[string]struct{} {
+	out := make(map[string]struct{}, len(a)+len(b))
+	for k := range a {
+		out[k] = struct{}{}
+	}
+	for k := range b {
+		out[k] = struct{}{}
+	}
+	return out
+}
+
+// whenFor builds the conditions shared by all exchanges of one response.
+func whenFor(v []Exchange, queryKeys, headerKeys []string) *config.WhenClause {
+	w := &config.WhenClause{}
+	for _, k := range queryKeys {
+		if w.Query == nil {
+			w.Query = map[string]config.Matcher{}
+		}
+		w.Query[k], _ = commonMatcher(v, queryValue(k))
+	}
+	for _, k := range headerKeys {
+		if w.Header == nil {
+			w.Header = map[string]config.Matcher{}
+		}
+		w.Header[k], _ = commonMatcher(v, headerValue(k))
+	}
+	return w
+}
+
+func commonMatcher(v []Exchange, get func(Exchange) (string, bool)) (config.Matcher, bool) {
+	val, present := get(v[0])
+	for _, ex := range v[1:] {
+		if ov, op := get(ex); ov != val || op != present {
+			return config.Matcher{}, false
+		}
+	}
+	if !present {
+		return config.Matcher{Exists: &present}, true
+	}
+	return config.Eq(val), true
+}
+
+func whenSignature(w *config.WhenClause) string {
+	b, _ := json.Marshal(w)
+	return string(b)
+}
+
+func conditionCount(w *config.WhenClause) int {
+	if w == nil {
+		return 0
+	}
+	return len(w.Query) + len(w.Header)
+}
+
+func (o *Output) variant(ex Exchange, bodiesDir string, names map[string]int) config.ResponseVariant {
+	rv := config.ResponseVariant{Status: ex.Status}
+	for _, h := range keptHeaders {
+		if v := ex.RespHeader.Get(h); v != "" {
+			if rv.Headers == nil {
+				rv.Headers = map[string]string{}
+			}
+			rv.Headers[h] = v
+		}
+	}
+
+	for path, content := range o.Files {
+		if bytes.Equal(content, config.EscapeTemplate(ex.Body)) && filepath.Ext(path) == bodyExt(ex.RespHeader.Get("Content-Type")) {
+			rv.BodyFile = path
+			return rv
+		}
+	}
+
+	base := ex.Method + "_" + sanitize(ex.Path)
+	names[base]++
+	if n := names[base]; n > 1 {
+		base = fmt.Sprintf("%s_%d", base, n)
+	}
+	rv.BodyFile = filepath.Join(bodiesDir, base+bodyExt(ex.RespHeader.Get("Content-Type")))
+	o.Files[rv.BodyFile] = config.EscapeTemplate(ex.Body)
+	return rv
+}
+
+func sanitize(path string) string {
+	if s := config.FileName(path); s != "" {
+		return s
+	}
+	return "root"
+}
+
+func bodyExt(contentType string) string {
+	mt, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mt == "application/json" || strings.HasSuffix(mt, "+json"):
+		return ".json"
+	case mt == "text/html":
+		return ".html"
+	case mt == "application/xml" || mt == "text/xml" || strings.HasSuffix(mt, "+xml"):
+		return ".xml"
+	case strings.HasPrefix(mt, "text/"):
+		return ".txt"
+	default:
+		return ".body"
+	}
+}
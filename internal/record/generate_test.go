@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package record
+
+import (
+	"net/http"
+	"net/url"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+	"github.com/Bl4cky99/mocker/internal/render"
+)
+
+func exchange(query url.Values, header http.Header, status int, body string) Exchange {
+	if query == nil {
+		query = url.Values{}
+	}
+	if header == nil {
+		header = http.Header{}
+	}
+	return Exchange{
+		Method: "GET", Path: "/items", Query: query, Header: header,
+		Status: status, RespHeader: http.Header{"Content-Type": {"application/json"}}, Body: []byte(body),
+	}
+}
+
+var _ = Describe("Build", func() {
+	It("emits a single unconditional variant for identical responses", func() {
+		out := Build([]Exchange{
+			exchange(url.Values{"t": {"1"}}, nil, 200, "[]"),
+			exchange(url.Values{"t": {"2"}}, nil, 200, "[]"),
+		}, "bodies")
+		Expect(out.Config.Endpoints).To(HaveLen(1))
+		Expect(out.Config.Endpoints[0].Responses).To(HaveLen(1))
+		Expect(out.Config.Endpoints[0].Responses[0].When).To(BeNil())
+		Expect(out.Files).To(HaveLen(1))
+	})
+
+	It("ignores keys that vary within one response and noisy headers", func() {
+		out := Build([]Exchange{
+			exchange(url.Values{"page": {"1"}, "t": {"1"}}, http.Header{"User-Agent": {"a"}}, 200, "[1]"),
+			exchange(url.Values{"page": {"1"}, "t": {"2"}}, http.Header{"User-Agent": {"b"}}, 200, "[1]"),
+			exchange(url.Values{"page": {"2"}, "t": {"3"}}, http.Header{"User-Agent": {"c"}}, 200, "[2]"),
+		}, "bodies")
+		rs := out.Config.Endpoints[0].Responses
+		Expect(rs).To(HaveLen(3))
+		Expect(rs[0].When.Query).To(Equal(map[string]config.Matcher{"page": config.Eq("1")}))
+		Expect(rs[0].When.Header).To(BeEmpty())
+		Expect(rs[1].When.Query).To(Equal(map[string]config.Matcher{"page": config.Eq("2")}))
+	})
+
+	It("drops responses that cannot be told apart", func() {
+		out := Build([]Exchange{
+			exchange(nil, nil, 200, "ok"),
+			exchange(nil, nil, 503, "down"),
+		}, "bodies")
+		rs := out.Config.Endpoints[0].Responses
+		Expect(rs).To(HaveLen(1))
+		Expect(rs[0].Status).To(Equal(200))
+	})
+
+	It("skips methods mocker cannot serve", func() {
+		ex := exchange(nil, nil, 200, "")
+		ex.Method = "HEAD"
+		Expect(Build([]Exchange{ex}, "bodies").Config.Endpoints).To(BeEmpty())
+	})
+
+	It("keeps template syntax in bodies literal", func() {
+		dir := GinkgoT().TempDir()
+		out := Build([]Exchange{exchange(nil, nil, 200, `{"a":"{{ .Nope }}"}`)}, filepath.Join(dir, "bodies"))
+		Expect(out.Write(filepath.Join(dir, "config.json"))).To(Succeed())
+
+		got, err := render.New().RenderFile(render.EngineText, out.Config.Endpoints[0].Responses[0].BodyFile, render.Data{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(got)).To(Equal(`{"a":"{{ .Nope }}"}`))
+
+		_, err = config.Load(filepath.Join(dir, "config.json"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
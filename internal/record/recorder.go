@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+// Package record proxies traffic to a real upstream and turns the captured
+// request/response pairs into a mocker config with body files.
+package record
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+)
+
+// Exchange is one proxied request and the upstream's response.
+type Exchange struct {
+	Method     string
+	Path       string
+	Query      url.Values
+	Header     http.Header
+	Status     int
+	RespHeader http.Header
+	Body       []byte
+}
+
+// Recorder is a reverse proxy that keeps every exchange it forwards.
+type Recorder struct {
+	proxy *httputil.ReverseProxy
+	log   *slog.Logger
+
+	mu        sync.Mutex
+	exchanges []Exchange
+}
+
+type ctxKeyExchange struct{}
+
+func New(upstream *url.URL, log *slog.Logger) *Recorder {
+	rec := &Recorder{log: log}
+	rec.proxy = &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(upstream)
+			pr.SetXForwarded()
+			// let the transport negotiate compression so recorded bodies are plain
+			pr.Out.Header.Del("Accept-Encoding")
+		},
+		ModifyResponse: rec.capture,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			log.Error("upstream request failed", "method", r.Method, "path", r.URL.Path, "err", err)
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+		},
+	}
+	return rec
+}
+
+func (rec *Recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ex := &Exchange{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+	}
+	rec.proxy.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKeyExchange{}, ex)))
+}
+
+func (rec *Recorder) capture(resp *http.Response) error {
+	ex, ok := resp.Request.Context().Value(ctxKeyExchange{}).(*Exchange)
+	if !ok {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	ex.Status = resp.StatusCode
+	ex.RespHeader = resp.Header.Clone()
+	ex.Body = body
+
+	rec.mu.Lock()
+	rec.exchanges = append(rec.exchanges, *ex)
+	rec.mu.Unlock()
+
+	rec.log.Info("recorded", "method", ex.Method, "path", ex.Path, "status", ex.Status, "bytes", len(body))
+	return nil
+}
+
+// Exchanges returns the recorded exchanges in arrival order.
+func (rec *Recorder) Exchanges() []Exchange {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]Exchange(nil), rec.exchanges...)
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package record
+
+import (
+	"compress/gzip"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/Bl4cky99/mocker/internal/config"
+)
+
+func newUpstream() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("role") == "admin" {
+			_, _ = io.WriteString(w, `[{"id":1,"name":"alice"}]`)
+			return
+		}
+		_, _ = io.WriteString(w, `[{"id":1,"name":"alice"},{"id":2,"name":"bob"}]`)
+	})
+	mux.HandleFunc("GET /users/1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = io.WriteString(w, `{"error":"login"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"id":1,"tpl":"{{ not a template }}"}`)
+	})
+	mux.HandleFunc("POST /users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/users/3")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"id":3}`)
+	})
+	mux.HandleFunc("GET /gzip", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			_, _ = io.WriteString(gz, "plain text")
+			_ = gz.Close()
+			return
+		}
+		_, _ = io.WriteString(w, "plain text")
+	})
+	return httptest.NewServer(mux)
+}
+
+var _ = Describe("Recorder", func() {
+	It("records through the proxy and writes a config that loads", func() {
+		upstream := newUpstream()
+		defer upstream.Close()
+		u, _ := url.Parse(upstream.URL)
+
+		rec := New(u, slog.New(slog.DiscardHandler))
+		proxy := httptest.NewServer(rec)
+		defer proxy.Close()
+
+		do := func(method, path, auth, body string) *http.Response {
+			req, _ := http.NewRequest(method, proxy.URL+path, strings.NewReader(body))
+			if auth != "" {
+				req.Header.Set("Authorization", auth)
+			}
+			req.Header.Set("Accept-Encoding", "gzip")
+			resp, err := http.DefaultTransport.RoundTrip(req)
+			Expect(err).NotTo(HaveOccurred())
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+			return resp
+		}
+
+		Expect(do("GET", "/users", "", "").StatusCode).To(Equal(200))
+		do("GET", "/users?role=admin", "", "")
+		Expect(do("GET", "/users/1", "", "").StatusCode).To(Equal(401))
+		do("GET", "/users/1", "Bearer t", "")
+		do("POST", "/users", "", `{"name":"carol"}`)
+		do("GET", "/gzip", "", "")
+
+		exchanges := rec.Exchanges()
+		Expect(exchanges).To(HaveLen(6))
+		Expect(string(exchanges[5].Body)).To(Equal("plain text"))
+
+		dir := GinkgoT().TempDir()
+		out := filepath.Join(dir, "config.yaml")
+		gen := Build(exchanges, filepath.Join(dir, "bodies"))
+		Expect(gen.Write(out)).To(Succeed())
+
+		cfg, err := config.Load(out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Endpoints).To(HaveLen(4))
+
+		users := cfg.Endpoints[0]
+		Expect(users.Responses).To(HaveLen(3))
+		Expect(users.Responses[0].When.Query).To(HaveKey("role"))
+		Expect(users.Responses[2].When).To(BeNil())
+
+		user := cfg.Endpoints[1]
+		Expect(user.Responses[0].When.Header).To(HaveKey("Authorization"))
+
+		Expect(cfg.Endpoints[2].Responses[0].Headers).To(HaveKeyWithValue("Location", "/users/3"))
+		Expect(cfg.Endpoints[3].Responses[0].BodyFile).To(HaveSuffix("GET_gzip.txt"))
+	})
+})
@@ -4,7 +4,14 @@
 package render
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -13,11 +20,13 @@ type Data struct {
 	Path       map[string]string
 	Query      map[string]string
 	Header     map[string]string
+	Form       map[string]string
 	Body       any
+	RawBody    string
 	NowRFC3339 string
 }
 
-func BuildData(r *http.Request, now string) Data {
+func BuildData(r *http.Request, now string, body []byte) Data {
 	path := make(map[string]string)
 	query := make(map[string]string)
 	header := make(map[string]string)
@@ -44,10 +53,78 @@ func BuildData(r *http.Request, now string) Data {
 		}
 	}
 
+	parsed, form := ParseBody(r.Header.Get("Content-Type"), body)
+
 	return Data{
 		Path:       path,
 		Query:      query,
 		Header:     header,
+		Form:       form,
+		Body:       parsed,
+		RawBody:    string(body),
 		NowRFC3339: now,
 	}
 }
+
+// ParseBody decodes a request body according to its content type. JSON
+// bodies are returned as generic values, urlencoded and multipart bodies as
+// field maps (first value wins, file parts are skipped) and anything else as
+// a plain string.
+func ParseBody(contentType string, body []byte) (any, map[string]string) {
+	form := make(map[string]string)
+	if len(body) == 0 {
+		return nil, form
+	}
+
+	mt, params, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mt == "application/json" || strings.HasSuffix(mt, "+json"):
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, form
+		}
+		return v, form
+	case mt == "application/x-www-form-urlencoded":
+		vals, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, form
+		}
+		for k, vs := range vals {
+			if len(vs) > 0 {
+				form[k] = vs[0]
+			}
+		}
+		return formAsAny(form), form
+	case mt == "multipart/form-data" && params["boundary"] != "":
+		mr := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err != nil {
+				break
+			}
+			name := part.FormName()
+			if name == "" || part.FileName() != "" {
+				_ = part.Close()
+				continue
+			}
+			val, _ := io.ReadAll(part)
+			_ = part.Close()
+			if _, ok := form[name]; !ok {
+				form[name] = string(val)
+			}
+		}
+		return formAsAny(form), form
+	default:
+		return string(body), form
+	}
+}
+
+func formAsAny(form map[string]string) map[string]any {
+	out := make(map[string]any, len(form))
+	for k, v := range form {
+		out[k] = v
+	}
+	return out
+}
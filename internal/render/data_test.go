@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BuildData", func() {
+	It("exposes raw and parsed JSON bodies", func() {
+		raw := `{"name":"alice","age":42}`
+		req := httptest.NewRequest(http.MethodPost, "/users?x=1", strings.NewReader(raw))
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+		d := BuildData(req, "now", []byte(raw))
+
+		Expect(d.RawBody).To(Equal(raw))
+		Expect(d.Query).To(HaveKeyWithValue("x", "1"))
+		body, ok := d.Body.(map[string]any)
+		Expect(ok).To(BeTrue())
+		Expect(body).To(HaveKeyWithValue("name", "alice"))
+		Expect(body).To(HaveKeyWithValue("age", json.Number("42")))
+	})
+
+	It("leaves Body nil for an empty request", func() {
+		d := BuildData(httptest.NewRequest(http.MethodGet, "/", nil), "now", nil)
+		Expect(d.Body).To(BeNil())
+		Expect(d.RawBody).To(BeEmpty())
+		Expect(d.Form).To(BeEmpty())
+	})
+})
+
+var _ = Describe("ParseBody", func() {
+	It("parses urlencoded forms", func() {
+		body, form := ParseBody("application/x-www-form-urlencoded", []byte("a=1&b=two&a=3"))
+		Expect(form).To(Equal(map[string]string{"a": "1", "b": "two"}))
+		Expect(body).To(HaveKeyWithValue("b", "two"))
+	})
+
+	It("parses multipart fields and skips files", func() {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		Expect(mw.WriteField("name", "bob")).To(Succeed())
+		fw, err := mw.CreateFormFile("avatar", "a.png")
+		Expect(err).NotTo(HaveOccurred())
+		_, _ = fw.Write([]byte("binary"))
+		Expect(mw.Close()).To(Succeed())
+
+		_, form := ParseBody(mw.FormDataContentType(), buf.Bytes())
+		Expect(form).To(Equal(map[string]string{"name": "bob"}))
+	})
+
+	It("returns invalid JSON as nil but other types as text", func() {
+		body, _ := ParseBody("application/json", []byte("{nope"))
+		Expect(body).To(BeNil())
+
+		body, _ = ParseBody("text/plain", []byte("hello"))
+		Expect(body).To(Equal("hello"))
+	})
+})
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package render
+
+import (
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Engine selects how a body template is processed.
+type Engine string
+
+const (
+	// EngineText uses text/template and writes values unescaped.
+	EngineText Engine = "text"
+	// EngineHTML uses html/template with contextual escaping.
+	EngineHTML Engine = "html"
+	// EngineNone serves the body verbatim.
+	EngineNone Engine = "none"
+)
+
+// EngineFor returns the engine named by a variant, or, when unset,
+// html/template for HTML content types and text/template otherwise.
+func EngineFor(name, contentType string) Engine {
+	if name != "" {
+		return Engine(name)
+	}
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mt = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	if mt == "text/html" || mt == "application/xhtml+xml" {
+		return EngineHTML
+	}
+	return EngineText
+}
+
+var checker = New()
+
+// Check reports syntax errors and unknown helpers in an inline template.
+func Check(engine Engine, src string) error {
+	if engine == EngineNone {
+		return nil
+	}
+	_, err := checker.parse(engine, "inline", src)
+	return err
+}
+
+// CheckFile is Check for the template file at path.
+func CheckFile(engine Engine, path string) error {
+	if engine == EngineNone {
+		return nil
+	}
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	_, err = checker.parse(engine, filepath.Base(path), string(src))
+	return err
+}
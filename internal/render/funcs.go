@@ -4,11 +4,23 @@
 package render
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"html/template"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
-func Funcs() template.FuncMap {
+// Funcs returns the template helpers. Random values come from the
+// renderer's generator, so a seed makes them reproducible.
+func (r *Renderer) Funcs() template.FuncMap {
 	return template.FuncMap{
 		"json": func(v any) (string, error) {
 			b, err := json.Marshal(v)
@@ -17,5 +29,330 @@ func Funcs() template.FuncMap {
 			}
 			return string(b), nil
 		},
+		// generated JSON is emitted unescaped so it can be embedded in bodies
+		"fakeFromSchema": func(path string) (template.HTML, error) {
+			b, err := r.RenderSchema(path)
+			if err != nil {
+				return "", err
+			}
+			return template.HTML(b), nil
+		},
+
+		// ids
+		"uuid": r.fake.UUID,
+		"ulid": func() string { return r.fake.ULID(r.clock()) },
+
+		// randomness
+		"randInt": func(lo, hi any) (int, error) {
+			a, err := toInt(lo)
+			if err != nil {
+				return 0, err
+			}
+			b, err := toInt(hi)
+			if err != nil {
+				return 0, err
+			}
+			return r.fake.IntBetween(a, b), nil
+		},
+		"randChoice": func(vs ...any) (any, error) {
+			if len(vs) == 1 {
+				vs = toList(vs[0])
+			}
+			if len(vs) == 0 {
+				return nil, errors.New("randChoice: no values")
+			}
+			return vs[r.fake.IntBetween(0, len(vs)-1)], nil
+		},
+
+		// faker
+		"firstName": r.fake.FirstName,
+		"lastName":  r.fake.LastName,
+		"name":      r.fake.Name,
+		"email":     r.fake.Email,
+		"street":    r.fake.Street,
+		"city":      r.fake.City,
+		"zip":       r.fake.Zip,
+		"country":   r.fake.Country,
+		"address":   r.fake.Address,
+		"lorem": func(n ...any) (string, error) {
+			words := 5
+			if len(n) > 0 {
+				var err error
+				if words, err = toInt(n[0]); err != nil {
+					return "", err
+				}
+			}
+			return r.fake.Words(words), nil
+		},
+		"sentence":  r.fake.Sentence,
+		"paragraph": r.fake.Paragraph,
+
+		// time
+		"now": func(layout ...string) string {
+			return r.clock().UTC().Format(timeLayout(layout))
+		},
+		"addDuration": func(d string, t ...any) (string, error) {
+			dur, err := parseDuration(d)
+			if err != nil {
+				return "", err
+			}
+			base, err := r.timeArg(t)
+			if err != nil {
+				return "", err
+			}
+			return base.Add(dur).Format(time.RFC3339), nil
+		},
+		"formatTime": func(layout string, t any) (string, error) {
+			tm, err := r.timeArg([]any{t})
+			if err != nil {
+				return "", err
+			}
+			return tm.Format(timeLayout([]string{layout})), nil
+		},
+		"unix": func(t ...any) (int64, error) {
+			tm, err := r.timeArg(t)
+			if err != nil {
+				return 0, err
+			}
+			return tm.Unix(), nil
+		},
+
+		// strings and encoding
+		"upper":   strings.ToUpper,
+		"lower":   strings.ToLower,
+		"trim":    strings.TrimSpace,
+		"replace": func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"base64":  func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+		"base64Decode": func(s string) (string, error) {
+			b, err := base64.StdEncoding.DecodeString(s)
+			return string(b), err
+		},
+		"sha256": func(s string) string {
+			sum := sha256.Sum256([]byte(s))
+			return hex.EncodeToString(sum[:])
+		},
+
+		// math
+		"add": arith(func(a, b float64) (float64, error) { return a + b, nil }),
+		"sub": arith(func(a, b float64) (float64, error) { return a - b, nil }),
+		"mul": arith(func(a, b float64) (float64, error) { return a * b, nil }),
+		"div": arith(func(a, b float64) (float64, error) {
+			if b == 0 {
+				return 0, errors.New("div: division by zero")
+			}
+			return a / b, nil
+		}),
+		"mod": arith(func(a, b float64) (float64, error) {
+			if b == 0 {
+				return 0, errors.New("mod: division by zero")
+			}
+			return math.Mod(a, b), nil
+		}),
+
+		// fallbacks
+		"default": func(def, v any) any {
+			if empty(v) {
+				return def
+			}
+			return v
+		},
+		"coalesce": func(vs ...any) any {
+			for _, v := range vs {
+				if !empty(v) {
+					return v
+				}
+			}
+			return nil
+		},
+
+		// collections
+		"dict": func(kv ...any) (map[string]any, error) {
+			if len(kv)%2 != 0 {
+				return nil, errors.New("dict: odd number of arguments")
+			}
+			out := make(map[string]any, len(kv)/2)
+			for i := 0; i < len(kv); i += 2 {
+				out[fmt.Sprint(kv[i])] = kv[i+1]
+			}
+			return out, nil
+		},
+		"list": func(vs ...any) []any { return vs },
+		"seq": func(args ...any) ([]int, error) {
+			lo, hi := 1, 0
+			switch len(args) {
+			case 1:
+				n, err := toInt(args[0])
+				if err != nil {
+					return nil, err
+				}
+				hi = n
+			case 2:
+				var err error
+				if lo, err = toInt(args[0]); err != nil {
+					return nil, err
+				}
+				if hi, err = toInt(args[1]); err != nil {
+					return nil, err
+				}
+			default:
+				return nil, errors.New("seq: use seq N or seq FROM TO")
+			}
+			// hi-lo < 0 catches overflow on huge ranges
+			if hi >= lo && (hi-lo < 0 || hi-lo >= maxSeq) {
+				return nil, fmt.Errorf("seq: %d to %d exceeds the limit of %d elements", lo, hi, maxSeq)
+			}
+			out := []int{}
+			for i := lo; i <= hi; i++ {
+				out = append(out, i)
+			}
+			return out, nil
+		},
+	}
+}
+
+// maxSeq bounds the length of seq so a template cannot exhaust memory.
+const maxSeq = 10000
+
+var namedLayouts = map[string]string{
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"RFC1123":     time.RFC1123,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+	"TimeOnly":    time.TimeOnly,
+	"Kitchen":     time.Kitchen,
+}
+
+// timeLayout resolves the optional layout argument: a name from
+// namedLayouts, a Go layout, or RFC3339 when absent.
+func timeLayout(layout []string) string {
+	if len(layout) == 0 || layout[0] == "" {
+		return time.RFC3339
+	}
+	if l, ok := namedLayouts[layout[0]]; ok {
+		return l
+	}
+	return layout[0]
+}
+
+// timeArg reads an optional time argument given as time.Time, RFC3339
+// string or unix seconds; absent means now on the renderer clock.
+func (r *Renderer) timeArg(args []any) (time.Time, error) {
+	if len(args) == 0 {
+		return r.clock().UTC(), nil
+	}
+	switch t := args[0].(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		return time.Parse(time.RFC3339, t)
+	}
+	sec, err := toInt(args[0])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %v", args[0])
+	}
+	return time.Unix(int64(sec), 0).UTC(), nil
+}
+
+// parseDuration extends time.ParseDuration with a "d" suffix for days.
+func parseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n * float64(24*time.Hour)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// arith applies op to two numbers. Integer operands give an integer
+// result; anything else gives a float.
+func arith(op func(a, b float64) (float64, error)) func(a, b any) (any, error) {
+	return func(a, b any) (any, error) {
+		x, xi, err := toNumber(a)
+		if err != nil {
+			return nil, err
+		}
+		y, yi, err := toNumber(b)
+		if err != nil {
+			return nil, err
+		}
+		v, err := op(x, y)
+		if err != nil {
+			return nil, err
+		}
+		if xi && yi {
+			return int64(math.Trunc(v)), nil
+		}
+		return v, nil
+	}
+}
+
+// toNumber converts template values, including numeric strings from
+// query parameters, and reports whether the value is an integer.
+func toNumber(v any) (float64, bool, error) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true, nil
+	case int64:
+		return float64(n), true, nil
+	case float64:
+		return n, n == math.Trunc(n), nil
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil && !strings.ContainsAny(n.String(), ".eE"), err
+	case string:
+		if i, err := strconv.ParseInt(strings.TrimSpace(n), 10, 64); err == nil {
+			return float64(i), true, nil
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("not a number: %q", n)
+		}
+		return f, false, nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true, nil
+	case reflect.Float32:
+		return rv.Float(), false, nil
+	}
+	return 0, false, fmt.Errorf("not a number: %v", v)
+}
+
+func toInt(v any) (int, error) {
+	f, _, err := toNumber(v)
+	return int(f), err
+}
+
+func toList(v any) []any {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return []any{v}
+	}
+	out := make([]any, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out
+}
+
+// empty reports whether v is nil or the zero value of its type, including
+// empty strings, slices and maps.
+func empty(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
 	}
+	return rv.IsZero()
 }
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package render
+
+import (
+	"strconv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Funcs", func() {
+	render := func(r *Renderer, tpl string, data any) string {
+		out, err := r.RenderString(EngineText, tpl, data)
+		Expect(err).NotTo(HaveOccurred())
+		return string(out)
+	}
+
+	It("makes random helpers reproducible with a seed", func() {
+		tpl := `{{ uuid }}|{{ ulid }}|{{ randInt 1 100 }}|{{ randChoice "a" "b" "c" }}|{{ name }}|{{ email }}|{{ address }}|{{ lorem 3 }}|{{ paragraph }}`
+		a := render(New(WithSeed(9)), tpl, nil)
+		b := render(New(WithSeed(9)), tpl, nil)
+		Expect(a).To(Equal(b))
+		Expect(render(New(WithSeed(10)), tpl, nil)).NotTo(Equal(a))
+	})
+
+	It("fixes the ulid timestamp with a seed", func() {
+		a, b := New(WithSeed(3)), New(WithSeed(3))
+		for range 3 {
+			id := render(a, "{{ ulid }}", nil)
+			Expect(id).To(Equal(render(b, "{{ ulid }}", nil)))
+			Expect(id[:10]).To(Equal(New().fake.ULID(seedEpoch)[:10]))
+		}
+	})
+
+	It("fixes the time helpers with a seed", func() {
+		tpl := `{{ now }}|{{ unix }}|{{ addDuration "1h" }}`
+		a := render(New(WithSeed(3)), tpl, nil)
+		Expect(render(New(WithSeed(3)), tpl, nil)).To(Equal(a))
+		Expect(a).To(Equal("2020-01-01T00:00:00Z|1577836800|2020-01-01T01:00:00Z"))
+	})
+
+	It("generates ids", func() {
+		r := New()
+		Expect(render(r, "{{ uuid }}", nil)).To(MatchRegexp(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`))
+		Expect(render(r, "{{ ulid }}", nil)).To(MatchRegexp(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`))
+	})
+
+	It("draws random values within bounds", func() {
+		r := New(WithSeed(1))
+		for range 50 {
+			n, err := strconv.Atoi(render(r, "{{ randInt 3 5 }}", nil))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(BeNumerically(">=", 3))
+			Expect(n).To(BeNumerically("<=", 5))
+			Expect(render(r, `{{ randChoice (list "x" "y") }}`, nil)).To(BeElementOf("x", "y"))
+		}
+		Expect(render(r, "{{ lorem 4 }}", nil)).To(MatchRegexp(`^\w+ \w+ \w+ \w+$`))
+		Expect(render(r, "{{ email }}", nil)).To(ContainSubstring("@example."))
+	})
+
+	It("formats and shifts time", func() {
+		r := New()
+		Expect(render(r, `{{ now "DateOnly" }}`, nil)).To(Equal(time.Now().UTC().Format(time.DateOnly)))
+		Expect(render(r, `{{ addDuration "36h" "2025-01-01T00:00:00Z" }}`, nil)).To(Equal("2025-01-02T12:00:00Z"))
+		Expect(render(r, `{{ addDuration "-2d" "2025-01-03T00:00:00Z" | formatTime "2006/01/02" }}`, nil)).To(Equal("2025/01/01"))
+		Expect(render(r, `{{ unix "2025-01-01T00:00:00Z" }}`, nil)).To(Equal("1735689600"))
+	})
+
+	It("transforms strings", func() {
+		r := New()
+		Expect(render(r, `{{ upper "ab" }}{{ lower "CD" }}{{ trim "  e  " }}{{ replace "-" "_" "a-b" }}`, nil)).To(Equal("ABcdea_b"))
+		Expect(render(r, `{{ base64 "mocker" }}|{{ base64Decode "bW9ja2Vy" }}`, nil)).To(Equal("bW9ja2Vy|mocker"))
+		Expect(render(r, `{{ sha256 "abc" }}`, nil)).To(Equal("ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"))
+	})
+
+	It("does arithmetic on numbers and numeric strings", func() {
+		r := New()
+		data := map[string]map[string]string{"Query": {"page": "2"}}
+		Expect(render(r, `{{ add .Query.page 1 }}|{{ sub 5 7 }}|{{ mul 1.5 2 }}|{{ div 7 2 }}|{{ div 7.0 2.5 }}|{{ mod 7 3 }}`, data)).To(Equal("3|-2|3|3|2.8|1"))
+		_, err := r.RenderString(EngineText, `{{ div 1 0 }}`, nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("falls back with default and coalesce", func() {
+		r := New()
+		data := map[string]map[string]string{"Query": {"a": "", "b": "set"}}
+		Expect(render(r, `{{ .Query.a | default "x" }}|{{ .Query.b | default "x" }}|{{ coalesce .Query.a .Query.missing .Query.b }}`, data)).To(Equal("x|set|set"))
+	})
+
+	It("builds collections", func() {
+		r := New()
+		Expect(render(r, `{{ $d := dict "a" 1 "b" "two" }}{{ $d.a }}-{{ $d.b }}`, nil)).To(Equal("1-two"))
+		Expect(render(r, `{{ range list 1 2 3 }}{{ . }}{{ end }}`, nil)).To(Equal("123"))
+		Expect(render(r, `{{ range seq 3 }}{{ . }}{{ end }}|{{ range seq 4 6 }}{{ . }}{{ end }}`, nil)).To(Equal("123|456"))
+		_, err := r.RenderString(EngineText, `{{ dict "a" }}`, nil)
+		Expect(err).To(HaveOccurred())
+		_, err = r.RenderString(EngineText, `{{ range seq 0 10000 }}{{ end }}`, nil)
+		Expect(err).To(MatchError(ContainSubstring("limit of 10000 elements")))
+	})
+})
@@ -5,50 +5,148 @@ package render
 
 import (
 	"bytes"
-	"html/template"
+	"encoding/json"
+	htmltemplate "html/template"
+	"io"
 	"os"
 	"path/filepath"
 	"sync"
+	"text/template"
+	"text/template/parse"
 	"time"
+
+	"github.com/Bl4cky99/mocker/internal/fake"
 )
 
 type Renderer struct {
-	mu   sync.RWMutex
-	tpls map[string]cachedTpl
+	mu     sync.RWMutex
+	tpls   map[string]cachedTpl
+	inline map[string]executor
+	fake   *fake.Generator
+	// source of now for the time helpers and ulid
+	clock func() time.Time
 }
 
 type cachedTpl struct {
-	tpl   *template.Template
+	tpl   executor
 	mtime time.Time
 }
 
-func New() *Renderer {
-	return &Renderer{tpls: make(map[string]cachedTpl)}
+// executor is satisfied by both text/template and html/template templates.
+type executor interface {
+	Execute(w io.Writer, data any) error
+}
+
+type Option func(*Renderer)
+
+// seedEpoch is the current time of seeded renderers.
+var seedEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// WithSeed makes generated data reproducible and fixes the clock behind
+// now, unix, addDuration, formatTime and ulid.
+func WithSeed(seed uint64) Option {
+	return func(r *Renderer) {
+		r.fake = fake.New(seed)
+		r.clock = func() time.Time { return seedEpoch }
+	}
+}
+
+func New(opts ...Option) *Renderer {
+	r := &Renderer{tpls: make(map[string]cachedTpl), inline: make(map[string]executor)}
+	for _, o := range opts {
+		o(r)
+	}
+	if r.fake == nil {
+		r.fake = fake.NewRandom()
+	}
+	if r.clock == nil {
+		r.clock = time.Now
+	}
+	return r
 }
 
-func (r *Renderer) RenderString(tplSrc string, data any) ([]byte, error) {
-	tpl, err := template.New("inline").Funcs(Funcs()).Option("missingkey=default").Parse(tplSrc)
+// RenderString renders an inline template with the given engine, parsing
+// it on first use. EngineNone returns the source unchanged.
+func (r *Renderer) RenderString(engine Engine, tplSrc string, data any) ([]byte, error) {
+	if engine == EngineNone {
+		return []byte(tplSrc), nil
+	}
+	tpl, err := r.loadString(engine, tplSrc)
 	if err != nil {
 		return nil, err
 	}
+	return execute(tpl, data)
+}
 
-	var buf bytes.Buffer
-	if err := tpl.Execute(&buf, data); err != nil {
+// RenderFile renders the template file at path, caching it per engine until
+// the file changes. EngineNone returns the file as is.
+func (r *Renderer) RenderFile(engine Engine, path string, data any) ([]byte, error) {
+	if engine == EngineNone {
+		return os.ReadFile(path)
+	}
+	tpl, err := r.loadFile(engine, path)
+	if err != nil {
 		return nil, err
 	}
+	return execute(tpl, data)
+}
 
-	return buf.Bytes(), nil
+// ParseString compiles an inline template ahead of its first use.
+func (r *Renderer) ParseString(engine Engine, tplSrc string) error {
+	if engine == EngineNone {
+		return nil
+	}
+	_, err := r.loadString(engine, tplSrc)
+	return err
+}
+
+// ParseFile compiles a template file ahead of its first use.
+func (r *Renderer) ParseFile(engine Engine, path string) error {
+	if engine == EngineNone {
+		return nil
+	}
+	_, err := r.loadFile(engine, path)
+	return err
+}
+
+// ClearInline drops the cached inline templates. The cache is keyed by
+// source, so a server clears it before parsing the bodies of a new config.
+func (r *Renderer) ClearInline() {
+	r.mu.Lock()
+	clear(r.inline)
+	r.mu.Unlock()
+}
+
+func (r *Renderer) loadString(engine Engine, src string) (executor, error) {
+	key := string(engine) + ":" + src
+	r.mu.RLock()
+	tpl, ok := r.inline[key]
+	r.mu.RUnlock()
+	if ok {
+		return tpl, nil
+	}
+
+	tpl, err := r.parse(engine, "inline", src)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.inline[key] = tpl
+	r.mu.Unlock()
+	return tpl, nil
 }
 
-func (r *Renderer) RenderFile(path string, data any) ([]byte, error) {
+func (r *Renderer) loadFile(engine Engine, path string) (executor, error) {
 	abs, _ := filepath.Abs(path)
 	info, err := os.Stat(abs)
 	if err != nil {
 		return nil, err
 	}
 
+	key := string(engine) + ":" + abs
 	r.mu.RLock()
-	ct, ok := r.tpls[abs]
+	ct, ok := r.tpls[key]
 	r.mu.RUnlock()
 
 	if !ok || ct.mtime.Before(info.ModTime()) {
@@ -57,7 +155,7 @@ func (r *Renderer) RenderFile(path string, data any) ([]byte, error) {
 			return nil, err
 		}
 
-		tpl, err := template.New(filepath.Base(abs)).Funcs(Funcs()).Option("missingkey=default").Parse(string(src))
+		tpl, err := r.parse(engine, filepath.Base(abs), string(src))
 		if err != nil {
 			return nil, err
 		}
@@ -65,14 +163,82 @@ func (r *Renderer) RenderFile(path string, data any) ([]byte, error) {
 		ct = cachedTpl{tpl: tpl, mtime: info.ModTime()}
 
 		r.mu.Lock()
-		r.tpls[abs] = ct
+		r.tpls[key] = ct
 		r.mu.Unlock()
 	}
 
-	var buf bytes.Buffer
-	if err := ct.tpl.Execute(&buf, data); err != nil {
+	return ct.tpl, nil
+}
+
+func (r *Renderer) parse(engine Engine, name, src string) (executor, error) {
+	if engine == EngineHTML {
+		return htmltemplate.New(name).Funcs(r.Funcs()).Option("missingkey=default").Parse(src)
+	}
+	tpl, err := template.New(name).Funcs(template.FuncMap(r.Funcs())).Funcs(template.FuncMap{blankFunc: blank}).
+		Option("missingkey=default").Parse(src)
+	if err != nil {
 		return nil, err
 	}
+	for _, t := range tpl.Templates() {
+		if t.Tree != nil {
+			blankMissing(t.Tree, t.Tree.Root)
+		}
+	}
+	return tpl, nil
+}
+
+const blankFunc = "_blank"
+
+// blank prints missing and nil values as "", as html/template does,
+// instead of text/template's "<no value>".
+func blank(v any) any {
+	if v == nil {
+		return ""
+	}
+	return v
+}
 
+// blankMissing pipes the output of every printing action through blank.
+func blankMissing(tree *parse.Tree, node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			blankMissing(tree, c)
+		}
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) > 0 {
+			return
+		}
+		id := parse.NewIdentifier(blankFunc).SetTree(tree).SetPos(n.Pos)
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{NodeType: parse.NodeCommand, Pos: n.Pos, Args: []parse.Node{id}})
+	case *parse.IfNode:
+		blankMissing(tree, n.List)
+		blankMissing(tree, n.ElseList)
+	case *parse.RangeNode:
+		blankMissing(tree, n.List)
+		blankMissing(tree, n.ElseList)
+	case *parse.WithNode:
+		blankMissing(tree, n.List)
+		blankMissing(tree, n.ElseList)
+	}
+}
+
+func execute(tpl executor, data any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
 	return buf.Bytes(), nil
 }
+
+// RenderSchema returns JSON generated from the JSON Schema file at path.
+func (r *Renderer) RenderSchema(path string) ([]byte, error) {
+	v, err := r.fake.SchemaFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
@@ -4,6 +4,7 @@
 package render
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"time"
@@ -22,7 +23,7 @@ var _ = Describe("Renderer", func() {
 
 			r := New()
 
-			out, err := r.RenderFile(path, map[string]string{"Name": "world"})
+			out, err := r.RenderFile(EngineText, path, map[string]string{"Name": "world"})
 			Expect(err).NotTo(HaveOccurred())
 			Expect(string(out)).To(Equal("hello world"))
 
@@ -32,7 +33,7 @@ var _ = Describe("Renderer", func() {
 			future := time.Now().Add(2 * time.Second)
 			Expect(os.Chtimes(path, future, future)).To(Succeed())
 
-			out, err = r.RenderFile(path, map[string]string{"Name": "again"})
+			out, err = r.RenderFile(EngineText, path, map[string]string{"Name": "again"})
 			Expect(err).NotTo(HaveOccurred())
 			Expect(string(out)).To(Equal("hi again"))
 		})
@@ -41,16 +42,147 @@ var _ = Describe("Renderer", func() {
 	Describe("RenderString", func() {
 		It("renders a template string with data", func() {
 			r := New()
-			out, err := r.RenderString("value={{.V}}", map[string]int{"V": 42})
+			out, err := r.RenderString(EngineText, "value={{.V}}", map[string]int{"V": 42})
 			Expect(err).NotTo(HaveOccurred())
 			Expect(string(out)).To(Equal("value=42"))
 		})
 
 		It("defaults missing map keys to empty string", func() {
 			r := New()
-			out, err := r.RenderString("{{.Query.page}}", map[string]map[string]string{"Query": {}})
+			out, err := r.RenderString(EngineText, "{{.Query.page}}", map[string]map[string]string{"Query": {}})
 			Expect(err).NotTo(HaveOccurred())
 			Expect(string(out)).To(Equal(""))
 		})
 	})
+
+	Describe("ParseString and ParseFile", func() {
+		It("report syntax errors before the first render", func() {
+			r := New()
+			Expect(r.ParseString(EngineText, "{{ .A ")).NotTo(Succeed())
+			Expect(r.ParseString(EngineHTML, "{{ nope }}")).NotTo(Succeed())
+			Expect(r.ParseString(EngineNone, "{{ .A ")).To(Succeed())
+
+			path := filepath.Join(GinkgoT().TempDir(), "broken.tmpl")
+			Expect(os.WriteFile(path, []byte("{{ if }}"), 0o600)).To(Succeed())
+			Expect(r.ParseFile(EngineText, path)).NotTo(Succeed())
+		})
+
+		It("caches inline templates by engine and source", func() {
+			r := New()
+			Expect(r.ParseString(EngineText, "{{ .V }}")).To(Succeed())
+			Expect(r.inline).To(HaveLen(1))
+
+			out, err := r.RenderString(EngineText, "{{ .V }}", map[string]string{"V": "<x>"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(out)).To(Equal("<x>"))
+			Expect(r.inline).To(HaveLen(1))
+
+			out, err = r.RenderString(EngineHTML, "{{ .V }}", map[string]string{"V": "<x>"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(out)).To(Equal("&lt;x&gt;"))
+			Expect(r.inline).To(HaveLen(2))
+
+			r.ClearInline()
+			Expect(r.inline).To(BeEmpty())
+		})
+	})
+
+	Describe("Check", func() {
+		It("validates templates without a renderer", func() {
+			Expect(Check(EngineText, `{{ uuid }} {{ now "DateOnly" }}`)).To(Succeed())
+			Expect(Check(EngineText, "{{ .A ")).NotTo(Succeed())
+			Expect(Check(EngineNone, "{{ .A ")).To(Succeed())
+			Expect(CheckFile(EngineText, filepath.Join(GinkgoT().TempDir(), "missing.tmpl"))).NotTo(Succeed())
+		})
+	})
+
+	Describe("fakeFromSchema", func() {
+		It("embeds generated JSON that is reproducible with a seed", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "item.json")
+			schema := `{"type":"object","required":["id","name"],"properties":{"id":{"type":"integer","minimum":1,"maximum":9},"name":{"type":"string"}}}`
+			Expect(os.WriteFile(path, []byte(schema), 0o600)).To(Succeed())
+
+			tpl := `{"item": {{ fakeFromSchema "` + path + `" }}}`
+			a, err := New(WithSeed(7)).RenderString(EngineText, tpl, nil)
+			Expect(err).NotTo(HaveOccurred())
+			b, err := New(WithSeed(7)).RenderString(EngineText, tpl, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(a).To(Equal(b))
+
+			var out map[string]map[string]any
+			Expect(json.Unmarshal(a, &out)).To(Succeed())
+			Expect(out["item"]).To(HaveKey("id"))
+			Expect(out["item"]).To(HaveKey("name"))
+		})
+
+		It("fails rendering when the schema file is missing", func() {
+			_, err := New().RenderString(EngineText, `{{ fakeFromSchema "nope.json" }}`, nil)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("engines", func() {
+		data := map[string]any{"Q": "a<b & c+d"}
+
+		It("leaves values unescaped with text/template", func() {
+			out, err := New().RenderString(EngineText, `{"q": {{ json .Q }}}`, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(out)).To(Equal(`{"q": "a\u003cb \u0026 c+d"}`))
+
+			out, err = New().RenderString(EngineText, `{{ .Q }}`, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(out)).To(Equal("a<b & c+d"))
+		})
+
+		It("escapes contextually with html/template", func() {
+			out, err := New().RenderString(EngineHTML, `<p>{{ .Q }}</p>`, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(out)).To(Equal("<p>a&lt;b &amp; c&#43;d</p>"))
+		})
+
+		It("serves bodies verbatim with none", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "raw.txt")
+			Expect(os.WriteFile(path, []byte("{{ .Q }}"), 0o600)).To(Succeed())
+
+			out, err := New().RenderFile(EngineNone, path, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(out)).To(Equal("{{ .Q }}"))
+
+			out, err = New().RenderString(EngineNone, "{{ broken", data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(out)).To(Equal("{{ broken"))
+		})
+
+		It("caches the same file separately per engine", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "page.tmpl")
+			Expect(os.WriteFile(path, []byte("{{ .Q }}"), 0o600)).To(Succeed())
+			r := New()
+
+			html, err := r.RenderFile(EngineHTML, path, data)
+			Expect(err).NotTo(HaveOccurred())
+			text, err := r.RenderFile(EngineText, path, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(html)).To(Equal("a&lt;b &amp; c&#43;d"))
+			Expect(string(text)).To(Equal("a<b & c+d"))
+		})
+
+		It("offers the same helpers in both engines", func() {
+			for _, e := range []Engine{EngineText, EngineHTML} {
+				out, err := New().RenderString(e, `{{ upper "ok" }}`, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(out)).To(Equal("OK"))
+			}
+		})
+	})
+
+	DescribeTable("EngineFor",
+		func(name, contentType string, want Engine) {
+			Expect(EngineFor(name, contentType)).To(Equal(want))
+		},
+		Entry("json defaults to text", "", "application/json", EngineText),
+		Entry("no content type defaults to text", "", "", EngineText),
+		Entry("html with parameters", "", "text/html; charset=utf-8", EngineHTML),
+		Entry("xhtml", "", "application/xhtml+xml", EngineHTML),
+		Entry("explicit engine wins", "none", "text/html", EngineNone),
+	)
 })
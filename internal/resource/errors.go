@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package resource
+
+import "errors"
+
+var (
+	ErrNotFound     = errors.New("item not found")
+	ErrConflict     = errors.New("item already exists")
+	ErrInvalidItem  = errors.New("invalid item")
+	ErrInvalidQuery = errors.New("invalid query")
+	ErrSeed         = errors.New("invalid seed file")
+)
@@ -0,0 +1,266 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package resource
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Query describes filtering, sorting and pagination of a list request.
+//
+//	?status=active&age_gte=18&name_like=ali&_sort=-age,name&_page=2&_limit=20
+type Query struct {
+	Filters []Filter
+	Sort    []SortKey
+	Offset  int
+	// 0 means no limit
+	Limit int
+}
+
+// Filter compares the value at Field (dotted for nested objects) using Op:
+// eq, ne, gt, gte, lt, lte or like. eq and ne accept several values.
+type Filter struct {
+	Field  string
+	Op     string
+	Values []string
+}
+
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+const defaultPageSize = 10
+
+var filterOps = []string{"ne", "gte", "gt", "lte", "lt", "like"}
+
+// ParseQuery reads a Query from URL parameters. Parameters starting with an
+// underscore control sorting and paging, all others are filters.
+func ParseQuery(v url.Values) (Query, error) {
+	var q Query
+
+	for _, f := range strings.Split(v.Get("_sort"), ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		key := SortKey{Field: strings.TrimPrefix(f, "-"), Desc: strings.HasPrefix(f, "-")}
+		if strings.EqualFold(v.Get("_order"), "desc") {
+			key.Desc = !key.Desc
+		}
+		q.Sort = append(q.Sort, key)
+	}
+
+	var err error
+	if q.Limit, err = intParam(v, "_limit"); err != nil {
+		return q, err
+	}
+	if q.Offset, err = intParam(v, "_offset"); err != nil {
+		return q, err
+	}
+	page, err := intParam(v, "_page")
+	if err != nil {
+		return q, err
+	}
+	if page > 0 {
+		if q.Limit == 0 {
+			q.Limit = defaultPageSize
+		}
+		q.Offset = (page - 1) * q.Limit
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(v)) {
+		if strings.HasPrefix(k, "_") {
+			continue
+		}
+		f := Filter{Field: k, Op: "eq", Values: v[k]}
+		for _, op := range filterOps {
+			if field, ok := strings.CutSuffix(k, "_"+op); ok && field != "" {
+				f.Field, f.Op = field, op
+				break
+			}
+		}
+		q.Filters = append(q.Filters, f)
+	}
+
+	return q, nil
+}
+
+func intParam(v url.Values, key string) (int, error) {
+	s := v.Get(key)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: %s must be a non-negative integer", ErrInvalidQuery, key)
+	}
+	return n, nil
+}
+
+func (q Query) matches(it Item) bool {
+	for _, f := range q.Filters {
+		if !f.matches(lookup(it, f.Field)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f Filter) matches(v any, found bool) bool {
+	if !found {
+		return f.Op == "ne"
+	}
+	text := valueText(v)
+
+	switch f.Op {
+	case "eq":
+		return slices.Contains(f.Values, text)
+	case "ne":
+		return !slices.Contains(f.Values, text)
+	case "like":
+		return strings.Contains(strings.ToLower(text), strings.ToLower(f.Values[0]))
+	}
+
+	c, ok := compareText(v, f.Values[0])
+	if !ok {
+		return false
+	}
+	switch f.Op {
+	case "gt":
+		return c > 0
+	case "gte":
+		return c >= 0
+	case "lt":
+		return c < 0
+	default:
+		return c <= 0
+	}
+}
+
+func (q Query) sort(items []Item) {
+	if len(q.Sort) == 0 {
+		return
+	}
+	slices.SortStableFunc(items, func(a, b Item) int {
+		for _, k := range q.Sort {
+			va, _ := lookup(a, k.Field)
+			vb, _ := lookup(b, k.Field)
+			c := compareValues(va, vb)
+			if k.Desc {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
+
+func (q Query) page(items []Item) []Item {
+	if q.Offset >= len(items) {
+		return []Item{}
+	}
+	items = items[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(items) {
+		items = items[:q.Limit]
+	}
+	return items
+}
+
+func lookup(it Item, field string) (any, bool) {
+	var cur any = it
+	for _, part := range strings.Split(field, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func valueText(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	case bool:
+		return strconv.FormatBool(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		b, _ := json.Marshal(t)
+		return string(b)
+	}
+}
+
+func number(v any) (float64, bool) {
+	switch t := v.(type) {
+	case json.Number:
+		f, err := t.Float64()
+		return f, err == nil
+	case float64:
+		return t, true
+	case int:
+		return float64(t), true
+	}
+	return 0, false
+}
+
+// compareText compares an item value with a filter argument, numerically
+// when both are numbers and as strings otherwise.
+func compareText(v any, arg string) (int, bool) {
+	if n, ok := number(v); ok {
+		f, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return 0, false
+		}
+		return cmp.Compare(n, f), true
+	}
+	if s, ok := v.(string); ok {
+		return cmp.Compare(s, arg), true
+	}
+	return 0, false
+}
+
+// compareValues orders missing and null values first, then numbers, then
+// everything else by its text.
+func compareValues(a, b any) int {
+	if a == nil || b == nil {
+		return cmp.Compare(rank(a), rank(b))
+	}
+	na, aok := number(a)
+	nb, bok := number(b)
+	if aok && bok {
+		return cmp.Compare(na, nb)
+	}
+	if aok != bok {
+		if aok {
+			return -1
+		}
+		return 1
+	}
+	return cmp.Compare(valueText(a), valueText(b))
+}
+
+func rank(v any) int {
+	if v == nil {
+		return 0
+	}
+	return 1
+}
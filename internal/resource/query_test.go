@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package resource
+
+import (
+	"encoding/json"
+	"net/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Query", func() {
+	var st *Store
+
+	BeforeEach(func() {
+		st = NewStore("id", "increment")
+		Expect(st.Seed([]Item{
+			{"name": "alice", "age": json.Number("30"), "role": "admin", "address": map[string]any{"city": "Berlin"}},
+			{"name": "bob", "age": json.Number("25"), "role": "user", "address": map[string]any{"city": "Hamburg"}},
+			{"name": "carol", "age": json.Number("35"), "role": "user"},
+			{"name": "dave", "age": json.Number("25"), "role": "guest"},
+		})).To(Succeed())
+	})
+
+	names := func(raw string) ([]string, int) {
+		v, err := url.ParseQuery(raw)
+		Expect(err).NotTo(HaveOccurred())
+		q, err := ParseQuery(v)
+		Expect(err).NotTo(HaveOccurred())
+		items, total := st.List(q)
+		out := make([]string, 0, len(items))
+		for _, it := range items {
+			out = append(out, it["name"].(string))
+		}
+		return out, total
+	}
+
+	DescribeTable("filters, sorts and pages",
+		func(raw string, want []string, wantTotal int) {
+			got, total := names(raw)
+			Expect(got).To(Equal(want))
+			Expect(total).To(Equal(wantTotal))
+		},
+		Entry("no query", "", []string{"alice", "bob", "carol", "dave"}, 4),
+		Entry("equality", "role=user", []string{"bob", "carol"}, 2),
+		Entry("several values", "role=admin&role=guest", []string{"alice", "dave"}, 2),
+		Entry("not equal", "role_ne=user", []string{"alice", "dave"}, 2),
+		Entry("numeric range", "age_gte=26&age_lt=35", []string{"alice"}, 1),
+		Entry("substring", "name_like=AR", []string{"carol"}, 1),
+		Entry("nested field", "address.city=Hamburg", []string{"bob"}, 1),
+		Entry("sort by several keys", "_sort=age,-name", []string{"dave", "bob", "alice", "carol"}, 4),
+		Entry("sort with _order", "_sort=age&_order=desc", []string{"carol", "alice", "bob", "dave"}, 4),
+		Entry("limit and offset", "_limit=2&_offset=1", []string{"bob", "carol"}, 4),
+		Entry("page", "_page=2&_limit=3", []string{"dave"}, 4),
+		Entry("offset beyond the end", "_offset=10", []string{}, 4),
+	)
+
+	It("rejects invalid paging parameters", func() {
+		_, err := ParseQuery(url.Values{"_limit": {"-1"}})
+		Expect(err).To(MatchError(ErrInvalidQuery))
+		_, err = ParseQuery(url.Values{"_page": {"x"}})
+		Expect(err).To(MatchError(ErrInvalidQuery))
+	})
+})
@@ -0,0 +1,341 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+// Package resource implements the in-memory collections behind the
+// generated CRUD routes of config resources.
+package resource
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+type Item = map[string]any
+
+// Store is a concurrency-safe, insertion-ordered collection of JSON objects
+// keyed by their id field.
+type Store struct {
+	mu       sync.RWMutex
+	idField  string
+	strategy string
+	items    map[string]Item
+	order    []string
+	nextID   int64
+	seed     []Item
+}
+
+// NewStore creates an empty store. strategy is "increment" or "uuid".
+func NewStore(idField, strategy string) *Store {
+	if idField == "" {
+		idField = "id"
+	}
+	return &Store{idField: idField, strategy: strategy, items: make(map[string]Item), nextID: 1}
+}
+
+func (s *Store) IDField() string {
+	return s.idField
+}
+
+// Seed replaces the content with items and remembers them for Reset.
+// Items without an id get one generated.
+func (s *Store) Seed(items []Item) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clear()
+	for i, it := range items {
+		if it == nil {
+			return fmt.Errorf("%w: item %d is not an object", ErrSeed, i)
+		}
+		if _, err := s.insert(clone(it)); err != nil {
+			return fmt.Errorf("%w: item %d: %w", ErrSeed, i, err)
+		}
+	}
+	s.seed = make([]Item, 0, len(items))
+	for _, id := range s.order {
+		s.seed = append(s.seed, clone(s.items[id]))
+	}
+	return nil
+}
+
+// Reset restores the seeded items.
+func (s *Store) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clear()
+	for _, it := range s.seed {
+		_, _ = s.insert(clone(it))
+	}
+}
+
+// Items returns all items in insertion order.
+func (s *Store) Items() []Item {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Item, 0, len(s.order))
+	for _, id := range s.order {
+		out = append(out, clone(s.items[id]))
+	}
+	return out
+}
+
+// Restore replaces the content with items without changing the seed that
+// Reset returns to. On error the previous content is kept.
+func (s *Store) Restore(items []Item) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prevItems, prevOrder, prevNext := s.items, s.order, s.nextID
+	s.clear()
+	for i, it := range items {
+		if it == nil {
+			s.items, s.order, s.nextID = prevItems, prevOrder, prevNext
+			return fmt.Errorf("%w: item %d is not an object", ErrInvalidItem, i)
+		}
+		if _, err := s.insert(clone(it)); err != nil {
+			s.items, s.order, s.nextID = prevItems, prevOrder, prevNext
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.order)
+}
+
+// List returns the items matching q after sorting and pagination, plus the
+// number of matches before pagination.
+func (s *Store) List(q Query) ([]Item, int) {
+	s.mu.RLock()
+	matched := make([]Item, 0, len(s.order))
+	for _, id := range s.order {
+		if it := s.items[id]; q.matches(it) {
+			matched = append(matched, clone(it))
+		}
+	}
+	s.mu.RUnlock()
+
+	q.sort(matched)
+	total := len(matched)
+	return q.page(matched), total
+}
+
+func (s *Store) Get(id string) (Item, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	it, ok := s.items[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, id)
+	}
+	return clone(it), nil
+}
+
+// Create adds it, generating an id unless one is set. An existing id is a
+// conflict.
+func (s *Store) Create(it Item) (Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.insert(clone(it))
+}
+
+// Replace swaps the item stored under id. The id field of it must be unset
+// or equal id.
+func (s *Store) Replace(id string, it Item) (Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur, ok := s.items[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, id)
+	}
+	next := clone(it)
+	if err := s.keepID(next, cur, id); err != nil {
+		return nil, err
+	}
+	s.items[id] = next
+	return clone(next), nil
+}
+
+// Patch applies a JSON merge patch (RFC 7386) to the item stored under id.
+// check, if set, can veto the result before it is stored.
+func (s *Store) Patch(id string, patch Item, check func(Item) error) (Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur, ok := s.items[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNotFound, id)
+	}
+	next, _ := MergePatch(clone(cur), clone(patch)).(Item)
+	if err := s.keepID(next, cur, id); err != nil {
+		return nil, err
+	}
+	if check != nil {
+		if err := check(next); err != nil {
+			return nil, err
+		}
+	}
+	s.items[id] = next
+	return clone(next), nil
+}
+
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.items[id]; !ok {
+		return fmt.Errorf("%w: %q", ErrNotFound, id)
+	}
+	delete(s.items, id)
+	s.order = slices.DeleteFunc(s.order, func(k string) bool { return k == id })
+	return nil
+}
+
+func (s *Store) clear() {
+	s.items = make(map[string]Item)
+	s.order = nil
+	s.nextID = 1
+}
+
+func (s *Store) insert(it Item) (Item, error) {
+	raw, ok := it[s.idField]
+	if !ok || raw == nil {
+		raw = s.generateID()
+		it[s.idField] = raw
+	}
+	id, ok := IDString(raw)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s must be a string or number", ErrInvalidItem, s.idField)
+	}
+	if _, dup := s.items[id]; dup {
+		return nil, fmt.Errorf("%w: %q", ErrConflict, id)
+	}
+	if n, err := strconv.ParseInt(id, 10, 64); err == nil && n >= s.nextID {
+		s.nextID = n + 1
+	}
+	s.items[id] = it
+	s.order = append(s.order, id)
+	return clone(it), nil
+}
+
+func (s *Store) keepID(next, cur Item, id string) error {
+	if raw, ok := next[s.idField]; ok && raw != nil {
+		if got, _ := IDString(raw); got != id {
+			return fmt.Errorf("%w: %s cannot be changed", ErrInvalidItem, s.idField)
+		}
+	}
+	next[s.idField] = cur[s.idField]
+	return nil
+}
+
+func (s *Store) generateID() any {
+	if s.strategy == "uuid" {
+		return newUUID()
+	}
+	n := s.nextID
+	s.nextID++
+	return json.Number(strconv.FormatInt(n, 10))
+}
+
+// IDString returns the map key for an id value. Only strings and numbers
+// are valid ids.
+func IDString(v any) (string, bool) {
+	switch t := v.(type) {
+	case string:
+		return t, t != ""
+	case json.Number:
+		return t.String(), true
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(t), true
+	case int64:
+		return strconv.FormatInt(t, 10), true
+	}
+	return "", false
+}
+
+// clone deep-copies it, so stored items never share nested maps or slices
+// with what callers hold or encode.
+func clone(it Item) Item {
+	if it == nil {
+		return nil
+	}
+	return cloneValue(it).(Item)
+}
+
+func cloneValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			out[k] = cloneValue(val)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, val := range t {
+			out[i] = cloneValue(val)
+		}
+		return out
+	}
+	return v
+}
+
+// MergePatch applies patch to target as described in RFC 7386.
+func MergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = MergePatch(t[k], v)
+	}
+	return t
+}
+
+// DecodeItem decodes a JSON object, keeping numbers as json.Number.
+func DecodeItem(b []byte) (Item, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var it Item
+	if err := dec.Decode(&it); err != nil || it == nil {
+		return nil, fmt.Errorf("%w: body must be a JSON object", ErrInvalidItem)
+	}
+	return it, nil
+}
+
+// LoadSeed reads a JSON array of objects.
+func LoadSeed(path string) ([]Item, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSeed, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var items []Item
+	if err := dec.Decode(&items); err != nil {
+		return nil, fmt.Errorf("%w: %s: expected a JSON array of objects: %w", ErrSeed, path, err)
+	}
+	return items, nil
+}
+
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package resource
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Store", func() {
+	var st *Store
+
+	BeforeEach(func() {
+		st = NewStore("id", "increment")
+		Expect(st.Seed([]Item{
+			{"id": json.Number("1"), "name": "alice"},
+			{"name": "bob"},
+		})).To(Succeed())
+	})
+
+	It("generates increment ids after the highest seeded one", func() {
+		bob, err := st.Get("2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bob).To(HaveKeyWithValue("name", "bob"))
+
+		it, err := st.Create(Item{"name": "carol"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(it["id"]).To(Equal(json.Number("3")))
+		Expect(st.Len()).To(Equal(3))
+	})
+
+	It("generates uuids", func() {
+		st := NewStore("key", "uuid")
+		it, err := st.Create(Item{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(it["key"]).To(MatchRegexp(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`))
+	})
+
+	It("rejects duplicate and invalid ids", func() {
+		_, err := st.Create(Item{"id": json.Number("1")})
+		Expect(err).To(MatchError(ErrConflict))
+		_, err = st.Create(Item{"id": true})
+		Expect(err).To(MatchError(ErrInvalidItem))
+	})
+
+	It("replaces and patches without changing the id", func() {
+		it, err := st.Replace("1", Item{"name": "alicia"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(it).To(Equal(Item{"id": json.Number("1"), "name": "alicia"}))
+
+		_, err = st.Replace("1", Item{"id": "9"})
+		Expect(err).To(MatchError(ErrInvalidItem))
+
+		it, err = st.Patch("1", Item{"name": nil, "tags": map[string]any{"vip": true}}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(it).To(Equal(Item{"id": json.Number("1"), "tags": map[string]any{"vip": true}}))
+
+		_, err = st.Patch("42", Item{}, nil)
+		Expect(err).To(MatchError(ErrNotFound))
+	})
+
+	It("keeps the item when the patch check fails", func() {
+		_, err := st.Patch("1", Item{"name": 5}, func(Item) error { return ErrInvalidItem })
+		Expect(err).To(MatchError(ErrInvalidItem))
+		it, _ := st.Get("1")
+		Expect(it).To(HaveKeyWithValue("name", "alice"))
+	})
+
+	It("keeps nested values when the patch check fails", func() {
+		Expect(st.Seed([]Item{{"id": json.Number("1"), "addr": map[string]any{"city": "A"}}})).To(Succeed())
+
+		_, err := st.Patch("1", Item{"addr": map[string]any{"city": "B"}}, func(Item) error { return ErrInvalidItem })
+		Expect(err).To(MatchError(ErrInvalidItem))
+		it, _ := st.Get("1")
+		Expect(it).To(HaveKeyWithValue("addr", map[string]any{"city": "A"}))
+
+		_, err = st.Patch("1", Item{"addr": map[string]any{"city": "C"}}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		st.Reset()
+		it, _ = st.Get("1")
+		Expect(it).To(HaveKeyWithValue("addr", map[string]any{"city": "A"}))
+	})
+
+	It("reads while patching concurrently", func() {
+		Expect(st.Seed([]Item{{"id": json.Number("1"), "addr": map[string]any{"city": "A"}}})).To(Succeed())
+
+		var wg sync.WaitGroup
+		for i := range 4 {
+			wg.Add(2)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				for range 50 {
+					it, err := st.Get("1")
+					Expect(err).NotTo(HaveOccurred())
+					_, err = json.Marshal(it)
+					Expect(err).NotTo(HaveOccurred())
+				}
+			}()
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				for j := range 50 {
+					_, err := st.Patch("1", Item{"addr": map[string]any{"city": i*100 + j}}, nil)
+					Expect(err).NotTo(HaveOccurred())
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	It("deletes items and resets to the seed", func() {
+		Expect(st.Delete("1")).To(Succeed())
+		Expect(st.Delete("1")).To(MatchError(ErrNotFound))
+		_, _ = st.Create(Item{"name": "carol"})
+
+		st.Reset()
+		items, total := st.List(Query{})
+		Expect(total).To(Equal(2))
+		Expect(items[0]).To(HaveKeyWithValue("name", "alice"))
+		Expect(items[1]).To(HaveKeyWithValue("id", json.Number("2")))
+	})
+
+	It("restores items without touching the seed", func() {
+		Expect(st.Restore([]Item{{"id": json.Number("7"), "name": "zed"}})).To(Succeed())
+		Expect(st.Items()).To(Equal([]Item{{"id": json.Number("7"), "name": "zed"}}))
+		it, _ := st.Create(Item{})
+		Expect(it["id"]).To(Equal(json.Number("8")))
+
+		Expect(st.Restore([]Item{{"id": "a"}, {"id": "a"}})).To(MatchError(ErrConflict))
+		Expect(st.Len()).To(Equal(2))
+
+		st.Reset()
+		Expect(st.Len()).To(Equal(2))
+		_, err := st.Get("1")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("returns copies", func() {
+		it, _ := st.Get("1")
+		it["name"] = "mallory"
+		again, _ := st.Get("1")
+		Expect(again).To(HaveKeyWithValue("name", "alice"))
+	})
+})
+
+var _ = Describe("LoadSeed", func() {
+	It("reads a JSON array of objects", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "users.json")
+		Expect(os.WriteFile(path, []byte(`[{"id":1,"age":30}]`), 0o600)).To(Succeed())
+
+		items, err := LoadSeed(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(items).To(Equal([]Item{{"id": json.Number("1"), "age": json.Number("30")}}))
+	})
+
+	It("rejects other documents", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "users.json")
+		Expect(os.WriteFile(path, []byte(`{"id":1}`), 0o600)).To(Succeed())
+		_, err := LoadSeed(path)
+		Expect(err).To(MatchError(ErrSeed))
+	})
+})
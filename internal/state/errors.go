@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package state
+
+import "errors"
+
+var (
+	ErrRead    = errors.New("read state")
+	ErrWrite   = errors.New("write state")
+	ErrDecode  = errors.New("decode state")
+	ErrVersion = errors.New("unsupported state version")
+)
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+// Package state reads and writes snapshots of mocker's runtime state
+// (sequence counters, scenario states, resource items).
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const Version = 1
+
+type Snapshot struct {
+	Version   int                         `json:"version"`
+	SavedAt   time.Time                   `json:"savedAt"`
+	Sequences map[string]int              `json:"sequences,omitempty"`
+	Scenarios map[string]string           `json:"scenarios,omitempty"`
+	Resources map[string][]map[string]any `json:"resources,omitempty"`
+}
+
+// Load reads the snapshot at path. A missing file yields an empty snapshot.
+func Load(path string) (Snapshot, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Snapshot{Version: Version}, nil
+	}
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("%w: %w", ErrRead, err)
+	}
+	defer f.Close()
+	return Decode(f)
+}
+
+// Decode parses a snapshot, keeping numbers as json.Number so ids and
+// values survive a round trip unchanged.
+func Decode(r io.Reader) (Snapshot, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	dec.DisallowUnknownFields()
+	var s Snapshot
+	if err := dec.Decode(&s); err != nil {
+		return Snapshot{}, fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+	if s.Version != Version {
+		return Snapshot{}, fmt.Errorf("%w: %d", ErrVersion, s.Version)
+	}
+	return s, nil
+}
+
+// Save writes s to path atomically: the data goes to a temporary file in the
+// same directory, is synced, and then renamed over path.
+func Save(path string, s Snapshot) error {
+	s.Version = Version
+	if s.SavedAt.IsZero() {
+		s.SavedAt = time.Now().UTC()
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(s); err != nil {
+		return fmt.Errorf("%w: %w", ErrWrite, err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("%w: %w", ErrWrite, err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrWrite, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("%w: %w", ErrWrite, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("%w: %w", ErrWrite, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("%w: %w", ErrWrite, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("%w: %w", ErrWrite, err)
+	}
+	return nil
+}
+
+// Clear removes the state file. A missing file is not an error.
+func Clear(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %w", ErrWrite, err)
+	}
+	return nil
+}
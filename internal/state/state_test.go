@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("state files", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "nested", "state.json")
+	})
+
+	It("round-trips a snapshot", func() {
+		in := Snapshot{
+			Sequences: map[string]int{"GET /jobs/{id}|1": 3},
+			Scenarios: map[string]string{"orders": "created"},
+			Resources: map[string][]map[string]any{"/users": {{"id": json.Number("1"), "name": "alice"}}},
+		}
+		Expect(Save(path, in)).To(Succeed())
+
+		out, err := Load(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.Version).To(Equal(Version))
+		Expect(out.SavedAt).NotTo(BeZero())
+		Expect(out.Sequences).To(Equal(in.Sequences))
+		Expect(out.Scenarios).To(Equal(in.Scenarios))
+		Expect(out.Resources).To(Equal(in.Resources))
+	})
+
+	It("leaves no temporary files behind", func() {
+		Expect(Save(path, Snapshot{})).To(Succeed())
+		Expect(Save(path, Snapshot{})).To(Succeed())
+		entries, err := os.ReadDir(filepath.Dir(path))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+	})
+
+	It("treats a missing file as empty state", func() {
+		s, err := Load(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Resources).To(BeEmpty())
+		Expect(Clear(path)).To(Succeed())
+	})
+
+	It("rejects unknown versions and fields", func() {
+		_, err := Decode(strings.NewReader(`{"version":2}`))
+		Expect(err).To(MatchError(ErrVersion))
+		_, err = Decode(strings.NewReader(`{"version":1,"counters":{}}`))
+		Expect(err).To(MatchError(ErrDecode))
+	})
+})
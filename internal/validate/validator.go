@@ -5,8 +5,11 @@ package validate
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
 )
@@ -33,6 +36,39 @@ func CompileSchema(schemaPath string, opt JSONSchemaValidatorOptions) (*JSONSche
 
 	fileURL := "file://" + filepath.ToSlash(abs)
 
+	sch, err := newCompiler(opt).Compile(fileURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrSchemaCompile, fileURL, err)
+	}
+
+	return &JSONSchemaValidator{schema: sch}, nil
+}
+
+// CompileValue compiles an in-memory schema, e.g. one taken from an OpenAPI
+// document. id must be an absolute URL and is used in error messages.
+func CompileValue(id string, schema any, opt JSONSchemaValidatorOptions) (*JSONSchemaValidator, error) {
+	b, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrSchemaCompile, id, err)
+	}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrSchemaCompile, id, err)
+	}
+
+	c := newCompiler(opt)
+	if err := c.AddResource(id, doc); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrSchemaCompile, id, err)
+	}
+	sch, err := c.Compile(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrSchemaCompile, id, err)
+	}
+
+	return &JSONSchemaValidator{schema: sch}, nil
+}
+
+func newCompiler(opt JSONSchemaValidatorOptions) *jsonschema.Compiler {
 	c := jsonschema.NewCompiler()
 	if opt.DefaultDraft != nil {
 		c.DefaultDraft(opt.DefaultDraft)
@@ -43,13 +79,7 @@ func CompileSchema(schemaPath string, opt JSONSchemaValidatorOptions) (*JSONSche
 	if opt.AssertContent {
 		c.AssertContent()
 	}
-
-	sch, err := c.Compile(fileURL)
-	if err != nil {
-		return nil, fmt.Errorf("%w: %s: %w", ErrSchemaCompile, fileURL, err)
-	}
-
-	return &JSONSchemaValidator{schema: sch}, nil
+	return c
 }
 
 func (v *JSONSchemaValidator) Validate(body []byte) error {
@@ -62,3 +92,33 @@ func (v *JSONSchemaValidator) Validate(body []byte) error {
 	}
 	return nil
 }
+
+// ValidateValue checks an already decoded value such as a coerced query
+// parameter.
+func (v *JSONSchemaValidator) ValidateValue(val any) error {
+	b, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnmarshalJSON, err)
+	}
+	return v.Validate(b)
+}
+
+// Details splits a validation error into its individual failures, e.g.
+// "at '/name': got number, want string".
+func Details(err error) []string {
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return []string{err.Error()}
+	}
+	var out []string
+	lines := strings.Split(ve.Error(), "\n")
+	for _, l := range lines[1:] {
+		if l = strings.TrimPrefix(strings.TrimSpace(l), "- "); l != "" {
+			out = append(out, l)
+		}
+	}
+	if len(out) == 0 {
+		out = append(out, lines[0])
+	}
+	return out
+}
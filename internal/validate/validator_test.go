@@ -56,3 +56,32 @@ var _ = Describe("CompileSchema", func() {
 		})
 	})
 })
+
+var _ = Describe("CompileValue", func() {
+	It("compiles an in-memory schema and validates decoded values", func() {
+		v, err := CompileValue("mem://test/limit.json", map[string]any{"type": "integer", "maximum": 10}, JSONSchemaValidatorOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v.ValidateValue(3.0)).To(Succeed())
+		Expect(errors.Is(v.ValidateValue(11.0), ErrSchemaValidation)).To(BeTrue())
+		Expect(errors.Is(v.ValidateValue("3"), ErrSchemaValidation)).To(BeTrue())
+	})
+
+	It("returns ErrSchemaCompile for an invalid schema", func() {
+		_, err := CompileValue("mem://test/bad.json", map[string]any{"type": 5}, JSONSchemaValidatorOptions{})
+		Expect(errors.Is(err, ErrSchemaCompile)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Details", func() {
+	It("lists the individual failures", func() {
+		v, err := CompileValue("mem://test/obj.json", map[string]any{
+			"type": "object", "required": []any{"id"},
+			"properties": map[string]any{"name": map[string]any{"type": "string"}},
+		}, JSONSchemaValidatorOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		details := Details(v.Validate([]byte(`{"name":1}`)))
+		Expect(details).To(ConsistOf(ContainSubstring("'id'"), ContainSubstring("want string")))
+		Expect(Details(errors.New("plain"))).To(Equal([]string{"plain"}))
+	})
+})
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type fileState struct {
+	exists bool
+	mtime  time.Time
+	size   int64
+}
+
+// Poller detects changes to a set of files by comparing their modification
+// time and size on a fixed interval.
+type Poller struct {
+	interval time.Duration
+	paths    func() []string
+	state    map[string]fileState
+}
+
+func NewPoller(interval time.Duration, paths func() []string) *Poller {
+	p := &Poller{interval: interval, paths: paths}
+	p.state = p.snapshot()
+	return p
+}
+
+// Run blocks until ctx is done and calls onChange with the changed paths
+// whenever at least one watched file was created, modified or removed.
+func (p *Poller) Run(ctx context.Context, onChange func(changed []string)) {
+	t := time.NewTicker(p.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if changed := p.Check(); len(changed) > 0 {
+				onChange(changed)
+				// the callback may have changed the watched set
+				p.state = p.snapshot()
+			}
+		}
+	}
+}
+
+// Check compares the current state of all watched files with the last
+// snapshot, stores the new state and returns the paths that differ.
+func (p *Poller) Check() []string {
+	next := p.snapshot()
+
+	var changed []string
+	for path, st := range next {
+		if prev, ok := p.state[path]; !ok || prev != st {
+			changed = append(changed, path)
+		}
+	}
+	for path := range p.state {
+		if _, ok := next[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+
+	p.state = next
+	return changed
+}
+
+func (p *Poller) snapshot() map[string]fileState {
+	out := make(map[string]fileState)
+	for _, path := range p.paths() {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			abs = path
+		}
+
+		info, err := os.Stat(abs)
+		if err != nil {
+			out[abs] = fileState{}
+			continue
+		}
+		out[abs] = fileState{exists: true, mtime: info.ModTime(), size: info.Size()}
+	}
+	return out
+}
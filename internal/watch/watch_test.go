@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: MIT
+// Copyright (c) 2025-2026 Jason Giese (Bl4cky99)
+
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Poller", func() {
+	var (
+		dir  string
+		path string
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		path = filepath.Join(dir, "config.yaml")
+		Expect(os.WriteFile(path, []byte("a"), 0o600)).To(Succeed())
+	})
+
+	touch := func(p, content string) {
+		Expect(os.WriteFile(p, []byte(content), 0o600)).To(Succeed())
+		future := time.Now().Add(time.Second)
+		Expect(os.Chtimes(p, future, future)).To(Succeed())
+	}
+
+	It("reports nothing when files are unchanged", func() {
+		p := NewPoller(time.Millisecond, func() []string { return []string{path} })
+		Expect(p.Check()).To(BeEmpty())
+	})
+
+	It("reports modified and removed files", func() {
+		other := filepath.Join(dir, "body.json")
+		touch(other, "{}")
+		p := NewPoller(time.Millisecond, func() []string { return []string{path, other} })
+
+		touch(path, "bb")
+		Expect(p.Check()).To(ConsistOf(path))
+
+		Expect(os.Remove(other)).To(Succeed())
+		Expect(p.Check()).To(ConsistOf(other))
+		Expect(p.Check()).To(BeEmpty())
+	})
+
+	It("invokes the callback from Run until the context ends", func() {
+		p := NewPoller(5*time.Millisecond, func() []string { return []string{path} })
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		changes := make(chan []string, 1)
+		done := make(chan struct{})
+		go func() {
+			p.Run(ctx, func(changed []string) { changes <- changed })
+			close(done)
+		}()
+
+		touch(path, "changed")
+		Eventually(changes).Should(Receive(ConsistOf(path)))
+
+		cancel()
+		Eventually(done).Should(BeClosed())
+	})
+})